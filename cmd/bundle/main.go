@@ -0,0 +1,106 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+type settings struct {
+	image                 string
+	target                string
+	secretPath            string
+	resultFileImageDigest string
+}
+
+var flagValues settings
+
+func init() {
+	// Add the zap logger flag set to the CLI. The flag set must
+	// be added before calling pflag.Parse().
+	pflag.CommandLine.AddGoFlagSet(ctxlog.CustomZapFlagSet())
+
+	pflag.StringVar(&flagValues.image, "image", "", "The reference of the source bundle image to pull")
+	pflag.StringVar(&flagValues.target, "target", "/workspace/source", "The target directory to extract the source bundle to")
+	pflag.StringVar(&flagValues.secretPath, "secret-path", "", "A directory that contains a secret of type kubernetes.io/dockerconfigjson with the credentials of the registry. Optional.")
+	pflag.StringVar(&flagValues.resultFileImageDigest, "result-file-image-digest", "", "A file to write the digest of the source bundle image to")
+}
+
+func main() {
+	// create logger and context
+	l := ctxlog.NewLogger("bundle")
+	ctx := ctxlog.NewParentContext(l)
+
+	if err := Execute(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Execute performs flag parsing, input validation and the extraction of the source bundle
+func Execute(ctx context.Context) error {
+	flagValues = settings{}
+	pflag.Parse()
+
+	err := runBundle(ctx)
+	if err != nil {
+		ctxlog.Error(ctx, err, "program failed with an error")
+	}
+
+	return err
+}
+
+func runBundle(ctx context.Context) error {
+	if flagValues.image == "" {
+		return errors.New("the 'image' argument must not be empty")
+	}
+
+	if flagValues.target == "" {
+		return errors.New("the 'target' argument must not be empty")
+	}
+
+	ref, err := name.ParseReference(flagValues.image)
+	if err != nil {
+		return err
+	}
+
+	var dockerConfigFile string
+	if flagValues.secretPath != "" {
+		dockerConfigFile = filepath.Join(flagValues.secretPath, ".dockerconfigjson")
+	}
+
+	options, err := image.GetOptions(ctx, dockerConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(flagValues.target, 0755); err != nil {
+		return err
+	}
+
+	ctxlog.Info(ctx, "pulling source bundle", "image", ref.String(), "target", flagValues.target)
+	digest, err := image.PullAndUnpack(ref, flagValues.target, options...)
+	if err != nil {
+		return err
+	}
+
+	ctxlog.Info(ctx, "extracted source bundle", "image", ref.String(), "digest", digest.String())
+	if flagValues.resultFileImageDigest != "" {
+		if err := ioutil.WriteFile(flagValues.resultFileImageDigest, []byte(digest.String()), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
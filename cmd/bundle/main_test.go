@@ -0,0 +1,136 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/shipwright-io/build/cmd/bundle"
+)
+
+var _ = Describe("Bundle Loader", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	var run = func(args ...string) error {
+		os.Args = append([]string{"tool", "--zap-log-level", "fatal"}, args...)
+		return Execute(context.TODO())
+	}
+
+	var withTempDir = func(f func(target string)) {
+		path, err := ioutil.TempDir(os.TempDir(), "bundle")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(path)
+
+		f(path)
+	}
+
+	var withTempFile = func(pattern string, f func(filename string)) {
+		file, err := ioutil.TempFile(os.TempDir(), pattern)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(file.Name())
+
+		f(file.Name())
+	}
+
+	var filecontent = func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	// pushBundle pushes a source bundle image with one layer that contains the files
+	var pushBundle = func(files map[string]string) v1.Hash {
+		var buffer bytes.Buffer
+		tarWriter := tar.NewWriter(&buffer)
+		for path, content := range files {
+			Expect(tarWriter.WriteHeader(&tar.Header{Name: path, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tarWriter.Write([]byte(content))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(tarWriter.Close()).To(Succeed())
+
+		layer, err := tarball.LayerFromReader(&buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		bundle, err := mutate.AppendLayers(empty.Image, layer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remote.Write(ref, bundle)).To(Succeed())
+
+		digest, err := bundle.Digest()
+		Expect(err).ToNot(HaveOccurred())
+		return digest
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+
+		var err error
+		ref, err = name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/shipwright/source-bundle:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("validations and error cases", func() {
+		It("should fail in case mandatory arguments are missing", func() {
+			Expect(run()).To(HaveOccurred())
+		})
+
+		It("should fail in case the image does not exist", func() {
+			withTempDir(func(target string) {
+				Expect(run(
+					"--image", ref.String(),
+					"--target", target,
+				)).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("pulling a source bundle", func() {
+		It("should extract the source bundle into the target directory and store its digest in the result file", func() {
+			digest := pushBundle(map[string]string{
+				"main.go":        "package main",
+				"docs/README.md": "# Sample",
+			})
+
+			withTempDir(func(target string) {
+				withTempFile("image-digest", func(filename string) {
+					Expect(run(
+						"--image", ref.String(),
+						"--target", target,
+						"--result-file-image-digest", filename,
+					)).To(Succeed())
+
+					Expect(filecontent(filepath.Join(target, "main.go"))).To(Equal("package main"))
+					Expect(filecontent(filepath.Join(target, "docs", "README.md"))).To(Equal("# Sample"))
+					Expect(filecontent(filename)).To(Equal(digest.String()))
+				})
+			})
+		})
+	})
+})
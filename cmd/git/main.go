@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	shpgit "github.com/shipwright-io/build/pkg/git"
 	"github.com/spf13/pflag"
 )
 
@@ -43,13 +44,35 @@ type settings struct {
 	url                 string
 	revision            string
 	depth               uint
+	sparse              []string
 	target              string
 	resultFileCommitSha string
+	resultFileRef       string
 	secretPath          string
+	submoduleSecretPath string
+	submodules          string
+	lfs                 string
+	verifyPath          string
 }
 
 var flagValues settings
 
+const (
+	// fullCommitShaLength is the length of a commit SHA that is not abbreviated
+	fullCommitShaLength = 40
+
+	submodulesNone      = "none"
+	submodulesShallow   = "shallow"
+	submodulesRecursive = "recursive"
+
+	lfsEnabled  = "enabled"
+	lfsDisabled = "disabled"
+
+	// gpgPublicKeysFile and allowedSignersFile are the files of the verify path with the trusted keys
+	gpgPublicKeysFile  = "gpg-public-keys"
+	allowedSignersFile = "allowed-signers"
+)
+
 var (
 	sshGitURLRegEx = regexp.MustCompile(`^(git@|ssh:\/\/).+$`)
 	commitShaRegEx = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
@@ -64,15 +87,29 @@ func init() {
 	// the flags for `url`, and `target` will always be used, but `revision`
 	// depends on the respective use case.
 	pflag.StringVar(&flagValues.url, "url", "", "The URL of the Git repository")
-	pflag.StringVar(&flagValues.revision, "revision", "", "The revision of the Git repository to be cloned. Optional, defaults to the default branch.")
+	pflag.StringVar(&flagValues.revision, "revision", "", "The revision of the Git repository to be cloned, either a branch, a tag, a commit sha, or a ref like refs/pull/1/head. Optional, defaults to the default branch.")
 	pflag.StringVar(&flagValues.target, "target", "", "The target directory of the clone operation")
 	pflag.StringVar(&flagValues.resultFileCommitSha, "result-file-commit-sha", "", "A file to write the commit sha to.")
+	pflag.StringVar(&flagValues.resultFileRef, "result-file-ref", "", "A file to write the name of the checked out ref to, it is empty for a commit sha.")
 	pflag.StringVar(&flagValues.secretPath, "secret-path", "", "A directory that contains a secret. Either username and password for basic authentication. Or a SSH private key and optionally a known hosts file. Optional.")
 
 	// Optional flag to be able to override the default shallow clone depth,
 	// which should be fine for almost all use cases we use the Git source step
 	// for (in the context of Shipwright build).
 	pflag.UintVar(&flagValues.depth, "depth", 1, "Create a shallow clone based on the given depth")
+
+	// Optional flag to only check out the given directories, the clone is then a
+	// partial clone that only downloads the file contents of these directories.
+	pflag.StringArrayVar(&flagValues.sparse, "sparse", nil, "A directory to check out in a sparse checkout, can be specified multiple times. Optional, defaults to the whole tree.")
+
+	// Optional flags to control which submodules are cloned, with which credentials,
+	// and whether the files of Git Large File Storage are downloaded.
+	pflag.StringVar(&flagValues.submodules, "submodules", submodulesRecursive, "The submodules to clone, either none, shallow for the submodules of the repository, or recursive for nested submodules as well")
+	pflag.StringVar(&flagValues.submoduleSecretPath, "submodule-secret-path", "", "A directory that contains a secret for submodules on other hosts. Either username and password for basic authentication, with a hosts file that lists the hosts they are used for. Or a SSH private key and optionally a known hosts file. Optional.")
+	pflag.StringVar(&flagValues.lfs, "lfs", "", "Whether the files of Git Large File Storage are downloaded, either enabled or disabled. Optional, defaults to the Git LFS configuration of the environment.")
+
+	// Optional flag to verify the signature of the checked out commit with trusted keys
+	pflag.StringVar(&flagValues.verifyPath, "verify-path", "", "A directory that contains the trusted keys to verify the commit signature with. Either GPG public keys in a gpg-public-keys file, or SSH public keys in an allowed-signers file, or both. Optional.")
 }
 
 func main() {
@@ -101,7 +138,7 @@ func checkAndRun() error {
 
 // Execute performs flag parsing, input validation and the Git clone
 func Execute(ctx context.Context) error {
-	flagValues = settings{depth: 1}
+	flagValues = settings{depth: 1, submodules: submodulesRecursive}
 	pflag.Parse()
 
 	err := runGitClone(ctx)
@@ -121,6 +158,26 @@ func runGitClone(ctx context.Context) error {
 		return &ExitError{Code: 101, Message: "the 'target' argument must not be empty"}
 	}
 
+	switch flagValues.submodules {
+	case submodulesNone, submodulesShallow, submodulesRecursive:
+	default:
+		return &ExitError{Code: 102, Message: fmt.Sprintf("the 'submodules' argument must be one of %s, %s or %s", submodulesNone, submodulesShallow, submodulesRecursive)}
+	}
+
+	switch flagValues.lfs {
+	case "", lfsDisabled:
+	case lfsEnabled:
+		if _, err := exec.LookPath("git-lfs"); err != nil {
+			return &ExitError{Code: 120, Message: "Git LFS is enabled, but git-lfs is not available: " + err.Error(), Cause: err}
+		}
+	default:
+		return &ExitError{Code: 103, Message: fmt.Sprintf("the 'lfs' argument must be either %s or %s", lfsEnabled, lfsDisabled)}
+	}
+
+	if flagValues.verifyPath != "" && !hasFile(flagValues.verifyPath, gpgPublicKeysFile) && !hasFile(flagValues.verifyPath, allowedSignersFile) {
+		return &ExitError{Code: 104, Message: fmt.Sprintf("the verify path must contain a %s or an %s file", gpgPublicKeysFile, allowedSignersFile)}
+	}
+
 	if err := clone(ctx); err != nil {
 		return err
 	}
@@ -136,18 +193,63 @@ func runGitClone(ctx context.Context) error {
 		}
 	}
 
+	if flagValues.resultFileRef != "" {
+		ref, err := resolveRef(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(flagValues.resultFileRef, []byte(ref), 0644); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// isRef returns whether the revision is a ref or refspec, like refs/pull/1/head, which is
+// fetched explicitly because `git clone --branch` only supports branches and tags
+func isRef(revision string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(revision, "+"), "refs/")
+}
+
+// resolveRef returns the full name of the ref that is checked out, which is empty for a commit sha
+func resolveRef(ctx context.Context) (string, error) {
+	switch {
+	case isRef(flagValues.revision):
+		// the source of a refspec is the ref that is checked out
+		return strings.SplitN(strings.TrimPrefix(flagValues.revision, "+"), ":", 2)[0], nil
+
+	case commitShaRegEx.MatchString(flagValues.revision):
+		return "", nil
+	}
+
+	// a branch is checked out as the current branch, a tag as a detached HEAD
+	if output, err := git(ctx, "-C", flagValues.target, "symbolic-ref", "--quiet", "HEAD"); err == nil {
+		return output, nil
+	}
+
+	return "refs/tags/" + flagValues.revision, nil
+}
+
 func checkEnvironment(ctx context.Context) error {
-	var checks = []struct{ toolName, versionArg string }{
+	var checks = []struct {
+		toolName, versionArg string
+		optional             bool
+	}{
 		{toolName: "ssh", versionArg: "-V"},
 		{toolName: "git", versionArg: "version"},
-		{toolName: "git-lfs", versionArg: "version"},
+		{toolName: "git-lfs", versionArg: "version", optional: true},
+		{toolName: "gpg", versionArg: "--version", optional: true},
 	}
 
 	for _, check := range checks {
 		path, err := exec.LookPath(check.toolName)
+		if err != nil && check.optional {
+			// an optional tool is checked when it is needed
+			ctxlog.Info(ctx, check.toolName, "path", "not found")
+			continue
+		}
 		if err != nil {
 			return &ExitError{Code: 120, Message: err.Error(), Cause: err}
 		}
@@ -173,8 +275,17 @@ func clone(ctx context.Context) error {
 		"--no-tags",
 	}
 
+	// a sparse checkout starts with the files of the top-level directory, the blobs
+	// of the sparse directories are fetched when they are added to the checkout
+	if len(flagValues.sparse) > 0 {
+		cloneArgs = append(cloneArgs, "--filter=blob:none", "--sparse")
+	}
+
 	var commitSha string
 	switch {
+	case isRef(flagValues.revision):
+		// a ref is fetched into an empty repository, the clone arguments do not apply
+
 	case commitShaRegEx.MatchString(flagValues.revision):
 		commitSha = flagValues.revision
 		cloneArgs = append(cloneArgs, "--no-checkout")
@@ -192,6 +303,7 @@ func clone(ctx context.Context) error {
 	}
 
 	var addtlCredArgs []string
+	var sshPrivateKeyFiles, knownHostsFiles []string
 	if flagValues.secretPath != "" {
 		credType, err := checkCredentials()
 		if err != nil {
@@ -200,47 +312,19 @@ func clone(ctx context.Context) error {
 
 		switch credType {
 		case typePrivateKey:
-			// Since the key provided via a secret can have undesirable file
-			// permissions, it will end up failing due to SSH sanity checks.
-			// Therefore, create a temporary replacement with the right
-			// file permissions.
-			data, err := ioutil.ReadFile(filepath.Join(flagValues.secretPath, "ssh-privatekey"))
+			sshPrivateKeyFile, err := copyPrivateKey(flagValues.secretPath)
 			if err != nil {
 				return err
 			}
 
-			sshPrivateKeyFile, err := ioutil.TempFile(os.TempDir(), "ssh-private-key")
-			if err != nil {
-				return err
-			}
+			defer os.Remove(sshPrivateKeyFile)
 
-			defer os.Remove(sshPrivateKeyFile.Name())
-
-			if err := ioutil.WriteFile(sshPrivateKeyFile.Name(), data, 0400); err != nil {
-				return err
-			}
-
-			var sshCmd = []string{"ssh",
-				"-o", "BatchMode=yes",
-				"-i", sshPrivateKeyFile.Name(),
-			}
-
-			var knownHostsFile = filepath.Join(flagValues.secretPath, "known_hosts")
-			if hasFile(knownHostsFile) {
-				sshCmd = append(sshCmd,
-					"-o", "GlobalKnownHostsFile=/dev/null",
-					"-o", fmt.Sprintf("UserKnownHostsFile=%s", knownHostsFile),
-				)
-			} else {
-				sshCmd = append(sshCmd,
-					"-o", "StrictHostKeyChecking=accept-new",
-				)
+			sshPrivateKeyFiles = append(sshPrivateKeyFiles, sshPrivateKeyFile)
+			if knownHostsFile := filepath.Join(flagValues.secretPath, "known_hosts"); hasFile(knownHostsFile) {
+				knownHostsFiles = append(knownHostsFiles, knownHostsFile)
 			}
 
-			addtlCredArgs = append(addtlCredArgs,
-				"-c",
-				fmt.Sprintf(`core.sshCommand=%s`, strings.Join(sshCmd, " ")),
-			)
+			addtlCredArgs = sshCommandArgs(sshPrivateKeyFiles, knownHostsFiles)
 
 		case typeUsernamePassword:
 			repoURL, err := url.Parse(flagValues.url)
@@ -277,21 +361,276 @@ func clone(ctx context.Context) error {
 		}
 	}
 
+	// submodules on other hosts can use the credentials of an additional secret
+	var submoduleCredArgs = addtlCredArgs
+	if flagValues.submoduleSecretPath != "" {
+		switch {
+		case hasFile(flagValues.submoduleSecretPath, "ssh-privatekey"):
+			sshPrivateKeyFile, err := copyPrivateKey(flagValues.submoduleSecretPath)
+			if err != nil {
+				return err
+			}
+
+			defer os.Remove(sshPrivateKeyFile)
+
+			sshPrivateKeyFiles = append(sshPrivateKeyFiles, sshPrivateKeyFile)
+			if knownHostsFile := filepath.Join(flagValues.submoduleSecretPath, "known_hosts"); hasFile(knownHostsFile) {
+				knownHostsFiles = append(knownHostsFiles, knownHostsFile)
+			}
+
+			submoduleCredArgs = sshCommandArgs(sshPrivateKeyFiles, knownHostsFiles)
+
+		case hasFile(flagValues.submoduleSecretPath, "username") && hasFile(flagValues.submoduleSecretPath, "password"):
+			hosts, err := submoduleCredentialHosts(flagValues.submoduleSecretPath)
+			if err != nil {
+				return err
+			}
+
+			// the helper is only configured for the listed hosts, so that the credentials
+			// are not sent to any other host that a submodule URL points to
+			submoduleCredArgs = append([]string{}, addtlCredArgs...)
+			for _, host := range hosts {
+				submoduleCredArgs = append(submoduleCredArgs,
+					"-c",
+					fmt.Sprintf(`credential.%s.helper=!f() { test "$1" = get && echo "username=$(cat %s)" && echo "password=$(cat %s)"; }; f`,
+						host,
+						filepath.Join(flagValues.submoduleSecretPath, "username"),
+						filepath.Join(flagValues.submoduleSecretPath, "password"),
+					),
+				)
+			}
+
+		default:
+			return &ExitError{Code: 110, Message: "Unsupported type of submodule credentials provided, either SSH private key or username/password is supported"}
+		}
+	}
+
+	if isRef(flagValues.revision) {
+		if err := fetchRef(ctx, flagValues.revision, addtlCredArgs); err != nil {
+			return err
+		}
+
+		return completeCheckout(ctx, addtlCredArgs, submoduleCredArgs)
+	}
+
+	// a commit SHA is fetched shallowly if the server allows it, instead of cloning the whole history
+	if commitSha != "" {
+		fetched, err := fetchCommitSha(ctx, commitSha, addtlCredArgs)
+		if err != nil {
+			return err
+		}
+
+		if fetched {
+			return completeCheckout(ctx, addtlCredArgs, submoduleCredArgs)
+		}
+	}
+
 	cloneArgs = append(cloneArgs, addtlCredArgs...)
 	cloneArgs = append(cloneArgs, "--", flagValues.url, flagValues.target)
 	if _, err := git(ctx, cloneArgs...); err != nil {
 		return err
 	}
 
+	if len(flagValues.sparse) > 0 {
+		sparseArgs := []string{"-C", flagValues.target}
+		sparseArgs = append(sparseArgs, addtlCredArgs...)
+		sparseArgs = append(sparseArgs, "sparse-checkout", "set", "--")
+		sparseArgs = append(sparseArgs, flagValues.sparse...)
+		if _, err := git(ctx, sparseArgs...); err != nil {
+			return err
+		}
+	}
+
 	if commitSha != "" {
-		if _, err := git(ctx, "-C", flagValues.target, "checkout", commitSha); err != nil {
+		// the checkout of a partial clone fetches the missing blobs, and needs the credentials
+		checkoutArgs := []string{"-C", flagValues.target}
+		checkoutArgs = append(checkoutArgs, addtlCredArgs...)
+		checkoutArgs = append(checkoutArgs, "checkout", commitSha)
+		if _, err := git(ctx, checkoutArgs...); err != nil {
 			return err
 		}
 	}
 
+	return completeCheckout(ctx, addtlCredArgs, submoduleCredArgs)
+}
+
+// fetchCommitSha initializes an empty repository and fetches the commit with the configured
+// depth. It returns false, after it removed the repository again, if the server refuses to
+// send the commit, or if an abbreviated commit SHA is not the tip of a reference.
+func fetchCommitSha(ctx context.Context, revision string, addtlCredArgs []string) (bool, error) {
+	commitSha := revision
+	if len(revision) < fullCommitShaLength {
+		expanded, err := expandCommitSha(ctx, revision, addtlCredArgs)
+		if err != nil {
+			return false, err
+		}
+
+		if expanded == "" {
+			ctxlog.Info(ctx, "the abbreviated commit SHA is not the tip of a reference, falling back to a full clone", "revision", revision)
+			return false, nil
+		}
+
+		ctxlog.Info(ctx, "expanded the abbreviated commit SHA", "revision", revision, "commitSha", expanded)
+		commitSha = expanded
+	}
+
+	ctxlog.Info(ctx, "fetching the commit SHA", "commitSha", commitSha, "depth", flagValues.depth)
+
+	if err := initRepository(ctx); err != nil {
+		return false, err
+	}
+
+	if err := fetch(ctx, commitSha, addtlCredArgs); err != nil {
+		ctxlog.Info(ctx, "the server did not send the commit SHA, falling back to a full clone", "commitSha", commitSha, "error", err.Error())
+		return false, cleanDirectory(flagValues.target)
+	}
+
+	return true, checkoutFetchHead(ctx, addtlCredArgs)
+}
+
+// fetchRef fetches a ref or refspec, like refs/pull/1/head, with the depth and checks it out
+func fetchRef(ctx context.Context, ref string, addtlCredArgs []string) error {
+	ctxlog.Info(ctx, "fetching the ref", "ref", ref, "depth", flagValues.depth)
+
+	if err := initRepository(ctx); err != nil {
+		return err
+	}
+
+	if err := fetch(ctx, ref, addtlCredArgs); err != nil {
+		return err
+	}
+
+	return checkoutFetchHead(ctx, addtlCredArgs)
+}
+
+// initRepository creates an empty repository in the target directory with the URL as origin, which
+// becomes a partial clone like the one of `git clone --filter=blob:none --sparse` for a sparse checkout
+func initRepository(ctx context.Context) error {
+	if _, err := git(ctx, "init", "--quiet", flagValues.target); err != nil {
+		return err
+	}
+
+	if _, err := git(ctx, "-C", flagValues.target, "remote", "add", "origin", flagValues.url); err != nil {
+		return err
+	}
+
+	if len(flagValues.sparse) > 0 {
+		for _, config := range [][]string{{"remote.origin.promisor", "true"}, {"remote.origin.partialclonefilter", "blob:none"}} {
+			if _, err := git(ctx, "-C", flagValues.target, "config", config[0], config[1]); err != nil {
+				return err
+			}
+		}
+
+		sparseArgs := append([]string{"-C", flagValues.target, "sparse-checkout", "set", "--"}, flagValues.sparse...)
+		if _, err := git(ctx, sparseArgs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetch fetches the revision from the origin with the depth, which sets FETCH_HEAD
+func fetch(ctx context.Context, revision string, addtlCredArgs []string) error {
+	fetchArgs := []string{"-C", flagValues.target}
+	fetchArgs = append(fetchArgs, addtlCredArgs...)
+	fetchArgs = append(fetchArgs, "fetch", "--quiet", "--no-tags")
+
+	if flagValues.depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", fmt.Sprintf("%d", flagValues.depth))
+	}
+
+	if len(flagValues.sparse) > 0 {
+		fetchArgs = append(fetchArgs, "--filter=blob:none")
+	}
+
+	fetchArgs = append(fetchArgs, "origin", revision)
+	_, err := git(ctx, fetchArgs...)
+	return err
+}
+
+// checkoutFetchHead checks out the fetched revision, the checkout of a partial clone fetches the
+// missing blobs and therefore needs the credentials
+func checkoutFetchHead(ctx context.Context, addtlCredArgs []string) error {
+	checkoutArgs := []string{"-C", flagValues.target}
+	checkoutArgs = append(checkoutArgs, addtlCredArgs...)
+	checkoutArgs = append(checkoutArgs, "checkout", "--quiet", "FETCH_HEAD")
+	_, err := git(ctx, checkoutArgs...)
+	return err
+}
+
+// expandCommitSha returns the full commit SHA of the reference tip that the abbreviated
+// commit SHA matches, or an empty string if there is no unique match
+func expandCommitSha(ctx context.Context, revision string, addtlCredArgs []string) (string, error) {
+	lsRemoteArgs := append([]string{}, addtlCredArgs...)
+	lsRemoteArgs = append(lsRemoteArgs, "ls-remote", "--", flagValues.url)
+
+	output, err := git(ctx, lsRemoteArgs...)
+	if err != nil {
+		return "", err
+	}
+
+	var expanded string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != fullCommitShaLength || !strings.HasPrefix(fields[0], revision) {
+			continue
+		}
+
+		if expanded != "" && expanded != fields[0] {
+			return "", nil
+		}
+		expanded = fields[0]
+	}
+
+	return expanded, nil
+}
+
+// cleanDirectory removes the content of the directory, but not the directory itself,
+// which might be a mount point
+func cleanDirectory(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeCheckout verifies the commit signature if requested, downloads the Git LFS files if they are
+// enabled, and clones the submodules
+func completeCheckout(ctx context.Context, addtlCredArgs []string, submoduleCredArgs []string) error {
+	if flagValues.verifyPath != "" {
+		if err := verifyCommitSignature(ctx); err != nil {
+			return err
+		}
+	}
+
+	if flagValues.lfs == lfsEnabled {
+		lfsArgs := []string{"-C", flagValues.target}
+		lfsArgs = append(lfsArgs, addtlCredArgs...)
+		lfsArgs = append(lfsArgs, "lfs", "pull")
+		if _, err := git(ctx, lfsArgs...); err != nil {
+			return err
+		}
+	}
+
+	if flagValues.submodules == submodulesNone {
+		return nil
+	}
+
 	submoduleArgs := []string{"-C", flagValues.target}
-	submoduleArgs = append(submoduleArgs, addtlCredArgs...)
-	submoduleArgs = append(submoduleArgs, "submodule", "update", "--init", "--recursive")
+	submoduleArgs = append(submoduleArgs, submoduleCredArgs...)
+	submoduleArgs = append(submoduleArgs, "submodule", "update", "--init")
+	if flagValues.submodules == submodulesRecursive {
+		submoduleArgs = append(submoduleArgs, "--recursive")
+	}
 	if flagValues.depth > 0 {
 		submoduleArgs = append(submoduleArgs, "--depth", fmt.Sprintf("%d", flagValues.depth))
 	}
@@ -299,6 +638,133 @@ func clone(ctx context.Context) error {
 	return err
 }
 
+// verifyCommitSignature verifies the signature of the checked out commit with the trusted keys of the
+// verify path. The GPG keyring and the allowed signers only contain these keys, therefore a valid
+// signature is always one of a trusted key.
+func verifyCommitSignature(ctx context.Context) error {
+	gnupgHome, err := ioutil.TempDir(os.TempDir(), "gnupg")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(gnupgHome)
+
+	if previous, ok := os.LookupEnv("GNUPGHOME"); ok {
+		defer os.Setenv("GNUPGHOME", previous)
+	} else {
+		defer os.Unsetenv("GNUPGHOME")
+	}
+	os.Setenv("GNUPGHOME", gnupgHome)
+
+	if keysFile := filepath.Join(flagValues.verifyPath, gpgPublicKeysFile); hasFile(keysFile) {
+		if _, err := exec.LookPath("gpg"); err != nil {
+			return &ExitError{Code: 120, Message: "GPG public keys are provided, but gpg is not available: " + err.Error(), Cause: err}
+		}
+
+		out, err := exec.CommandContext(ctx, "gpg", "--batch", "--quiet", "--import", keysFile).CombinedOutput()
+		if err != nil {
+			return &ExitError{Code: 104, Message: fmt.Sprintf("the GPG public keys can not be imported: %s", strings.TrimSpace(string(out))), Cause: err}
+		}
+	}
+
+	// without an allowed signers file, no SSH signature is trusted
+	signersFile := filepath.Join(flagValues.verifyPath, allowedSignersFile)
+	if !hasFile(signersFile) {
+		signersFile = os.DevNull
+	} else if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return &ExitError{Code: 120, Message: "SSH allowed signers are provided, but ssh-keygen is not available: " + err.Error(), Cause: err}
+	}
+
+	if _, err := git(ctx, "-C", flagValues.target, "-c", "gpg.ssh.allowedSignersFile="+signersFile, "verify-commit", "HEAD"); err != nil {
+		var message = err.Error()
+		if exitError, ok := err.(*ExitError); ok {
+			message = exitError.Message
+		}
+
+		return &ExitError{
+			Code:    shpgit.CommitVerificationFailedExitCode,
+			Message: fmt.Sprintf("the commit is not signed with a trusted key: %s", message),
+			Cause:   err,
+		}
+	}
+
+	ctxlog.Info(ctx, "the commit is signed with a trusted key")
+	return nil
+}
+
+// submoduleCredentialHosts returns the URLs of the hosts that the submodule username and password are
+// sent to, one per line of the hosts file of the secret. A host without a scheme is an HTTPS host.
+func submoduleCredentialHosts(secretPath string) ([]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(secretPath, "hosts"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var hosts []string
+	for _, host := range strings.Fields(string(data)) {
+		if !strings.Contains(host, "://") {
+			host = "https://" + host
+		}
+		hosts = append(hosts, host)
+	}
+
+	if len(hosts) == 0 {
+		return nil, &ExitError{Code: 110, Message: "The submodule credentials with a username and password must list the hosts that they are used for in a hosts file"}
+	}
+
+	return hosts, nil
+}
+
+// copyPrivateKey copies the SSH private key of the secret to a temporary file, the caller must remove it.
+// Since the key provided via a secret can have undesirable file permissions, it will end up failing due
+// to SSH sanity checks. Therefore, create a temporary replacement with the right file permissions.
+func copyPrivateKey(secretPath string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(secretPath, "ssh-privatekey"))
+	if err != nil {
+		return "", err
+	}
+
+	sshPrivateKeyFile, err := ioutil.TempFile(os.TempDir(), "ssh-private-key")
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(sshPrivateKeyFile.Name(), data, 0400); err != nil {
+		os.Remove(sshPrivateKeyFile.Name())
+		return "", err
+	}
+
+	return sshPrivateKeyFile.Name(), nil
+}
+
+// sshCommandArgs returns the arguments that configure Git to use SSH with the private keys, which only
+// trusts the known hosts files if there are any
+func sshCommandArgs(sshPrivateKeyFiles []string, knownHostsFiles []string) []string {
+	var sshCmd = []string{"ssh",
+		"-o", "BatchMode=yes",
+	}
+
+	for _, sshPrivateKeyFile := range sshPrivateKeyFiles {
+		sshCmd = append(sshCmd, "-i", sshPrivateKeyFile)
+	}
+
+	if len(knownHostsFiles) > 0 {
+		sshCmd = append(sshCmd,
+			"-o", "GlobalKnownHostsFile=/dev/null",
+			"-o", fmt.Sprintf(`"UserKnownHostsFile=%s"`, strings.Join(knownHostsFiles, " ")),
+		)
+	} else {
+		sshCmd = append(sshCmd,
+			"-o", "StrictHostKeyChecking=accept-new",
+		)
+	}
+
+	return []string{
+		"-c",
+		fmt.Sprintf(`core.sshCommand=%s`, strings.Join(sshCmd, " ")),
+	}
+}
+
 func git(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	ctxlog.Debug(ctx, cmd.String())
@@ -307,6 +773,11 @@ func git(ctx context.Context, args ...string) (string, error) {
 	os.Setenv("GIT_TERMINAL_PROMPT", "0")
 	cmd.Stdin = nil
 
+	// Git LFS keeps the pointer files in the checkout if the download of the files is skipped
+	if flagValues.lfs == lfsDisabled {
+		cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
+	}
+
 	out, err := cmd.CombinedOutput()
 
 	var output string
@@ -8,14 +8,19 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	. "github.com/shipwright-io/build/cmd/git"
+	shpgit "github.com/shipwright-io/build/pkg/git"
 )
 
 var _ = Describe("Git Resource", func() {
@@ -50,6 +55,122 @@ var _ = Describe("Git Resource", func() {
 		Expect(ioutil.WriteFile(path, data, mode)).ToNot(HaveOccurred())
 	}
 
+	var git = func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=shipwright", "-c", "user.email=shipwright@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		Expect(err).ToNot(HaveOccurred(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	// withLocalRepository creates a repository with two commits of a top-level file and
+	// files in two directories, which can be cloned without network access
+	var withLocalRepository = func(f func(url string, commits []string)) {
+		withTempDir(func(repository string) {
+			git(repository, "init", "--quiet")
+			git(repository, "config", "uploadpack.allowFilter", "true")
+			git(repository, "config", "uploadpack.allowAnySHA1InWant", "true")
+
+			Expect(os.MkdirAll(filepath.Join(repository, "app", "cmd"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(repository, "docs"), 0755)).To(Succeed())
+
+			var commits []string
+			for _, version := range []string{"v1", "v2"} {
+				file(filepath.Join(repository, "README.md"), 0644, []byte(version))
+				file(filepath.Join(repository, "app", "cmd", "main.go"), 0644, []byte(version))
+				file(filepath.Join(repository, "docs", "index.md"), 0644, []byte(version))
+				git(repository, "add", "--all")
+				git(repository, "commit", "--quiet", "--message", version)
+				commits = append(commits, git(repository, "rev-parse", "HEAD"))
+			}
+
+			f("file://"+repository, commits)
+		})
+	}
+
+	// withLocalSubmodules creates a repository with a submodule, which itself has a nested
+	// submodule, all of them can be cloned without network access
+	var withLocalSubmodules = func(f func(url string)) {
+		// submodules with a file URL are only cloned if the file protocol is allowed
+		for key, value := range map[string]string{"GIT_CONFIG_COUNT": "1", "GIT_CONFIG_KEY_0": "protocol.file.allow", "GIT_CONFIG_VALUE_0": "always"} {
+			os.Setenv(key, value)
+			defer os.Unsetenv(key)
+		}
+
+		withTempDir(func(dir string) {
+			var repository = func(name string, submodule string) string {
+				path := filepath.Join(dir, name)
+				Expect(os.Mkdir(path, 0755)).To(Succeed())
+				git(path, "init", "--quiet")
+				file(filepath.Join(path, "README.md"), 0644, []byte(name))
+				if submodule != "" {
+					git(path, "submodule", "--quiet", "add", "file://"+filepath.Join(dir, submodule), submodule)
+				}
+				git(path, "add", "--all")
+				git(path, "commit", "--quiet", "--message", name)
+				return path
+			}
+
+			repository("nested", "")
+			repository("library", "nested")
+			f("file://" + repository("app", "library"))
+		})
+	}
+
+	// withHTTPSubmodule creates a repository with a submodule that is served by an HTTP server with basic
+	// authentication, the server records the authorization headers of all requests
+	var withHTTPSubmodule = func(f func(url string, server string, authorizations func() []string)) {
+		withTempDir(func(dir string) {
+			library := filepath.Join(dir, "library")
+			Expect(os.Mkdir(library, 0755)).To(Succeed())
+			git(library, "init", "--quiet")
+			file(filepath.Join(library, "README.md"), 0644, []byte("library"))
+			git(library, "add", "--all")
+			git(library, "commit", "--quiet", "--message", "library")
+			git(dir, "clone", "--quiet", "--bare", library, filepath.Join(dir, "library.git"))
+
+			gitPath, err := exec.LookPath("git")
+			Expect(err).ToNot(HaveOccurred())
+
+			var (
+				mutex    sync.Mutex
+				received []string
+			)
+			backend := &cgi.Handler{
+				Path: gitPath,
+				Args: []string{"http-backend"},
+				Env:  []string{"GIT_PROJECT_ROOT=" + dir, "GIT_HTTP_EXPORT_ALL=1"},
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				received = append(received, r.Header.Get("Authorization"))
+				mutex.Unlock()
+
+				if username, password, ok := r.BasicAuth(); !ok || username != "shipwright" || password != "s3cr3t" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				backend.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			app := filepath.Join(dir, "app")
+			Expect(os.Mkdir(app, 0755)).To(Succeed())
+			git(app, "init", "--quiet")
+			file(filepath.Join(app, "README.md"), 0644, []byte("app"))
+			file(filepath.Join(app, ".gitmodules"), 0644, []byte("[submodule \"library\"]\n\tpath = library\n\turl = "+server.URL+"/library.git\n"))
+			git(app, "add", "--all")
+			git(app, "update-index", "--add", "--cacheinfo", "160000,"+git(library, "rev-parse", "HEAD")+",library")
+			git(app, "commit", "--quiet", "--message", "app")
+
+			f("file://"+app, server.URL, func() []string {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return append([]string{}, received...)
+			})
+		})
+	}
+
 	Context("validations and error cases", func() {
 		It("should fail in case mandatory arguments are missing", func() {
 			Expect(run()).To(HaveOccurred())
@@ -78,6 +199,40 @@ var _ = Describe("Git Resource", func() {
 			})
 		})
 
+		It("should fail in case the submodules value is not supported", func() {
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", "https://github.com/foo/bar",
+					"--target", target,
+					"--submodules", "all",
+				)).To(HaveOccurred())
+			})
+		})
+
+		It("should fail in case the lfs value is not supported", func() {
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", "https://github.com/foo/bar",
+					"--target", target,
+					"--lfs", "true",
+				)).To(HaveOccurred())
+			})
+		})
+
+		It("should fail in case Git LFS is enabled but git-lfs is not available", func() {
+			if _, err := exec.LookPath("git-lfs"); err == nil {
+				Skip("Skipping test as `git-lfs` binary is in the PATH")
+			}
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", "https://github.com/foo/bar",
+					"--target", target,
+					"--lfs", "enabled",
+				)).To(HaveOccurred())
+			})
+		})
+
 		It("should fail in case secret path content is not recognized", func() {
 			withTempDir(func(secret string) {
 				withTempDir(func(target string) {
@@ -288,6 +443,216 @@ var _ = Describe("Git Resource", func() {
 		})
 	})
 
+	Context("cloning repositories with a configured depth or sparse checkout", func() {
+		It("should Git clone the number of commits of the depth", func() {
+			withLocalRepository(func(url string, _ []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--depth", "1",
+					)).ToNot(HaveOccurred())
+
+					Expect(git(target, "rev-list", "--count", "HEAD")).To(Equal("1"))
+				})
+			})
+		})
+
+		It("should Git clone the whole history in case the depth is zero", func() {
+			withLocalRepository(func(url string, _ []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--depth", "0",
+					)).ToNot(HaveOccurred())
+
+					Expect(git(target, "rev-list", "--count", "HEAD")).To(Equal("2"))
+				})
+			})
+		})
+
+		It("should only check out the sparse directories and the top-level files", func() {
+			withLocalRepository(func(url string, _ []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--sparse", "app/cmd",
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "app", "cmd", "main.go"))).To(Equal("v2"))
+					Expect(filepath.Join(target, "README.md")).To(BeAnExistingFile())
+					Expect(filepath.Join(target, "docs", "index.md")).ToNot(BeAnExistingFile())
+					Expect(git(target, "config", "remote.origin.partialclonefilter")).To(Equal("blob:none"))
+				})
+			})
+		})
+
+		It("should only check out the sparse directories of a commit-sha", func() {
+			withLocalRepository(func(url string, commits []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", commits[0],
+						"--sparse", "app",
+						"--sparse", "docs",
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "app", "cmd", "main.go"))).To(Equal("v1"))
+					Expect(filecontent(filepath.Join(target, "docs", "index.md"))).To(Equal("v1"))
+				})
+			})
+		})
+	})
+
+	Context("cloning a commit-sha", func() {
+		It("should fetch a commit-sha with the depth instead of cloning the whole history", func() {
+			withLocalRepository(func(url string, commits []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", commits[0],
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("v1"))
+					Expect(git(target, "rev-parse", "HEAD")).To(Equal(commits[0]))
+					Expect(git(target, "rev-parse", "--is-shallow-repository")).To(Equal("true"))
+				})
+			})
+		})
+
+		It("should expand an abbreviated commit-sha of a reference tip and fetch it", func() {
+			withLocalRepository(func(url string, commits []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", commits[1][0:7],
+					)).ToNot(HaveOccurred())
+
+					Expect(git(target, "rev-parse", "HEAD")).To(Equal(commits[1]))
+					Expect(git(target, "rev-parse", "--is-shallow-repository")).To(Equal("true"))
+				})
+			})
+		})
+
+		It("should fall back to a full clone for an abbreviated commit-sha that is not a reference tip", func() {
+			withLocalRepository(func(url string, commits []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", commits[0][0:7],
+					)).ToNot(HaveOccurred())
+
+					Expect(git(target, "rev-parse", "HEAD")).To(Equal(commits[0]))
+					Expect(git(target, "rev-parse", "--is-shallow-repository")).To(Equal("false"))
+				})
+			})
+		})
+
+		It("should fall back to a full clone in case the server refuses to send the commit-sha", func() {
+			// the protocol version 0 only allows to fetch objects that the server advertises
+			for key, value := range map[string]string{"GIT_CONFIG_COUNT": "1", "GIT_CONFIG_KEY_0": "protocol.version", "GIT_CONFIG_VALUE_0": "0"} {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+
+			withLocalRepository(func(url string, commits []string) {
+				git(strings.TrimPrefix(url, "file://"), "config", "uploadpack.allowAnySHA1InWant", "false")
+
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", commits[0],
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("v1"))
+					Expect(git(target, "rev-parse", "--is-shallow-repository")).To(Equal("false"))
+				})
+			})
+		})
+	})
+
+	Context("cloning a ref", func() {
+		It("should fetch a ref that is not a branch or tag and report it", func() {
+			withLocalRepository(func(url string, commits []string) {
+				// a pull request head is a ref outside of the branches and tags
+				git(strings.TrimPrefix(url, "file://"), "update-ref", "refs/pull/1/head", commits[0])
+
+				withTempDir(func(target string) {
+					withTempFile("ref", func(resultFileRef string) {
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--revision", "refs/pull/1/head",
+							"--result-file-ref", resultFileRef,
+						)).ToNot(HaveOccurred())
+
+						Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("v1"))
+						Expect(git(target, "rev-parse", "HEAD")).To(Equal(commits[0]))
+						Expect(git(target, "rev-parse", "--is-shallow-repository")).To(Equal("true"))
+						Expect(filecontent(resultFileRef)).To(Equal("refs/pull/1/head"))
+					})
+				})
+			})
+		})
+
+		It("should report the source of a refspec as the ref", func() {
+			withLocalRepository(func(url string, commits []string) {
+				git(strings.TrimPrefix(url, "file://"), "update-ref", "refs/merge-requests/5/head", commits[0])
+
+				withTempDir(func(target string) {
+					withTempFile("ref", func(resultFileRef string) {
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--revision", "+refs/merge-requests/5/head:refs/remotes/origin/mr-5",
+							"--result-file-ref", resultFileRef,
+						)).ToNot(HaveOccurred())
+
+						Expect(git(target, "rev-parse", "HEAD")).To(Equal(commits[0]))
+						Expect(filecontent(resultFileRef)).To(Equal("refs/merge-requests/5/head"))
+					})
+				})
+			})
+		})
+
+		It("should report the ref of the default branch", func() {
+			withLocalRepository(func(url string, commits []string) {
+				defaultBranch := git(strings.TrimPrefix(url, "file://"), "symbolic-ref", "HEAD")
+
+				withTempDir(func(target string) {
+					withTempFile("ref", func(resultFileRef string) {
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--result-file-ref", resultFileRef,
+						)).ToNot(HaveOccurred())
+
+						Expect(filecontent(resultFileRef)).To(Equal(defaultBranch))
+					})
+				})
+			})
+		})
+
+		It("should fail in case the ref does not exist", func() {
+			withLocalRepository(func(url string, commits []string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--revision", "refs/pull/2/head",
+					)).To(HaveOccurred())
+				})
+			})
+		})
+	})
+
 	Context("cloning repositories with Git Large File Storage", func() {
 		const exampleRepo = "https://github.com/shipwright-io/sample-lfs"
 
@@ -329,4 +694,299 @@ var _ = Describe("Git Resource", func() {
 			})
 		})
 	})
+
+	Context("cloning repositories with configured submodules", func() {
+		It("should Git clone nested submodules by default", func() {
+			withLocalSubmodules(func(url string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "library", "README.md"))).To(Equal("library"))
+					Expect(filecontent(filepath.Join(target, "library", "nested", "README.md"))).To(Equal("nested"))
+				})
+			})
+		})
+
+		It("should only Git clone the submodules of the repository in case they are shallow", func() {
+			withLocalSubmodules(func(url string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--submodules", "shallow",
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "library", "README.md"))).To(Equal("library"))
+					Expect(filepath.Join(target, "library", "nested", "README.md")).ToNot(BeAnExistingFile())
+				})
+			})
+		})
+
+		It("should not Git clone submodules in case they are disabled", func() {
+			withLocalSubmodules(func(url string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--submodules", "none",
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("app"))
+					Expect(filepath.Join(target, "library", "README.md")).ToNot(BeAnExistingFile())
+				})
+			})
+		})
+
+		It("should Git clone a submodule with the username and password of a listed host", func() {
+			withHTTPSubmodule(func(url string, server string, authorizations func() []string) {
+				withTempDir(func(target string) {
+					withTempDir(func(secret string) {
+						file(filepath.Join(secret, "username"), 0644, []byte("shipwright"))
+						file(filepath.Join(secret, "password"), 0644, []byte("s3cr3t"))
+						file(filepath.Join(secret, "hosts"), 0644, []byte("github.com\n"+server+"\n"))
+
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--submodule-secret-path", secret,
+						)).ToNot(HaveOccurred())
+
+						Expect(filecontent(filepath.Join(target, "library", "README.md"))).To(Equal("library"))
+					})
+				})
+			})
+		})
+
+		It("should not send the username and password to a host that is not listed", func() {
+			withHTTPSubmodule(func(url string, server string, authorizations func() []string) {
+				withTempDir(func(target string) {
+					withTempDir(func(secret string) {
+						file(filepath.Join(secret, "username"), 0644, []byte("shipwright"))
+						file(filepath.Join(secret, "password"), 0644, []byte("s3cr3t"))
+						file(filepath.Join(secret, "hosts"), 0644, []byte("github.com"))
+
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--submodule-secret-path", secret,
+						)).To(HaveOccurred())
+
+						Expect(authorizations()).ToNot(BeEmpty())
+						for _, authorization := range authorizations() {
+							Expect(authorization).To(BeEmpty())
+						}
+					})
+				})
+			})
+		})
+
+		It("should fail in case the submodule username and password do not list any host", func() {
+			withHTTPSubmodule(func(url string, server string, authorizations func() []string) {
+				withTempDir(func(target string) {
+					withTempDir(func(secret string) {
+						file(filepath.Join(secret, "username"), 0644, []byte("shipwright"))
+						file(filepath.Join(secret, "password"), 0644, []byte("s3cr3t"))
+
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--submodule-secret-path", secret,
+						)).To(HaveOccurred())
+
+						Expect(authorizations()).To(BeEmpty())
+					})
+				})
+			})
+		})
+
+		It("should fail in case the submodule secret path content is not recognized", func() {
+			withLocalSubmodules(func(url string) {
+				withTempDir(func(target string) {
+					withTempDir(func(secret string) {
+						file(filepath.Join(secret, "token"), 0644, []byte("secret"))
+
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--submodule-secret-path", secret,
+						)).To(HaveOccurred())
+					})
+				})
+			})
+		})
+	})
+
+	Context("verifying the commit signature", func() {
+		// withSignedRepository creates a repository with a commit, which the sign function can sign, and
+		// a verify path with the trusted keys that the files function writes
+		var withSignedRepository = func(sign func(repository string, dir string) []string, files func(verifyPath string, dir string), f func(url string, verifyPath string)) {
+			withTempDir(func(dir string) {
+				repository := filepath.Join(dir, "repository")
+				Expect(os.Mkdir(repository, 0755)).To(Succeed())
+				git(repository, "init", "--quiet")
+				file(filepath.Join(repository, "README.md"), 0644, []byte("signed"))
+				git(repository, "add", "--all")
+				git(repository, append(sign(repository, dir), "commit", "--quiet", "--message", "signed")...)
+
+				verifyPath := filepath.Join(dir, "verify")
+				Expect(os.Mkdir(verifyPath, 0755)).To(Succeed())
+				files(verifyPath, dir)
+
+				f("file://"+repository, verifyPath)
+			})
+		}
+
+		var sshKey = func(dir string, name string) string {
+			out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", name, "-f", filepath.Join(dir, name)).CombinedOutput()
+			Expect(err).ToNot(HaveOccurred(), string(out))
+			return filepath.Join(dir, name)
+		}
+
+		var sshSigned = func(repository string, dir string) []string {
+			return []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + sshKey(dir, "trusted") + ".pub", "-c", "commit.gpgsign=true"}
+		}
+
+		var unsigned = func(repository string, dir string) []string {
+			return []string{}
+		}
+
+		var allowedSigners = func(key string) func(verifyPath string, dir string) {
+			return func(verifyPath string, dir string) {
+				if _, err := os.Stat(filepath.Join(dir, key+".pub")); os.IsNotExist(err) {
+					sshKey(dir, key)
+				}
+				file(filepath.Join(verifyPath, "allowed-signers"), 0644, []byte("shipwright@example.com "+filecontent(filepath.Join(dir, key+".pub"))))
+			}
+		}
+
+		var exitCode = func(err error) int {
+			Expect(err).To(HaveOccurred())
+			exitError, ok := err.(*ExitError)
+			Expect(ok).To(BeTrue())
+			return exitError.Code
+		}
+
+		BeforeEach(func() {
+			if _, err := exec.LookPath("ssh-keygen"); err != nil {
+				Skip("Skipping commit signature test as `ssh-keygen` binary is not in the PATH")
+			}
+		})
+
+		It("should Git clone a commit that is signed with a trusted SSH key", func() {
+			withSignedRepository(sshSigned, allowedSigners("trusted"), func(url string, verifyPath string) {
+				withTempDir(func(target string) {
+					Expect(run(
+						"--url", url,
+						"--target", target,
+						"--verify-path", verifyPath,
+					)).ToNot(HaveOccurred())
+
+					Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("signed"))
+				})
+			})
+		})
+
+		It("should fail in case the commit is signed with a key that is not trusted", func() {
+			withSignedRepository(sshSigned, allowedSigners("untrusted"), func(url string, verifyPath string) {
+				withTempDir(func(target string) {
+					Expect(exitCode(run(
+						"--url", url,
+						"--target", target,
+						"--verify-path", verifyPath,
+					))).To(Equal(shpgit.CommitVerificationFailedExitCode))
+				})
+			})
+		})
+
+		It("should fail in case the commit is not signed", func() {
+			withSignedRepository(unsigned, allowedSigners("trusted"), func(url string, verifyPath string) {
+				withTempDir(func(target string) {
+					Expect(exitCode(run(
+						"--url", url,
+						"--target", target,
+						"--verify-path", verifyPath,
+					))).To(Equal(shpgit.CommitVerificationFailedExitCode))
+				})
+			})
+		})
+
+		It("should fail in case allowed signers are provided but ssh-keygen is not available", func() {
+			withSignedRepository(sshSigned, allowedSigners("trusted"), func(url string, verifyPath string) {
+				withTempDir(func(bin string) {
+					// a PATH with the required tools only, but without ssh-keygen
+					for _, tool := range []string{"git", "ssh"} {
+						path, err := exec.LookPath(tool)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(os.Symlink(path, filepath.Join(bin, tool))).To(Succeed())
+					}
+
+					previousPath := os.Getenv("PATH")
+					defer os.Setenv("PATH", previousPath)
+					Expect(os.Setenv("PATH", bin)).To(Succeed())
+
+					withTempDir(func(target string) {
+						Expect(exitCode(run(
+							"--url", url,
+							"--target", target,
+							"--verify-path", verifyPath,
+						))).To(Equal(120))
+					})
+				})
+			})
+		})
+
+		It("should Git clone a commit that is signed with a trusted GPG key", func() {
+			if _, err := exec.LookPath("gpg"); err != nil {
+				Skip("Skipping GPG signature test as `gpg` binary is not in the PATH")
+			}
+
+			// the test keyring is used instead of the one of the user
+			withTempDir(func(gnupgHome string) {
+				os.Setenv("GNUPGHOME", gnupgHome)
+				defer os.Unsetenv("GNUPGHOME")
+
+				var gpg = func(args ...string) string {
+					out, err := exec.Command("gpg", append([]string{"--batch", "--quiet"}, args...)...).CombinedOutput()
+					Expect(err).ToNot(HaveOccurred(), string(out))
+					return string(out)
+				}
+
+				var gpgSigned = func(repository string, dir string) []string {
+					gpg("--passphrase", "", "--quick-gen-key", "Shipwright <shipwright@example.com>", "ed25519", "sign", "never")
+					return []string{"-c", "user.signingkey=shipwright@example.com", "-c", "commit.gpgsign=true"}
+				}
+
+				var gpgPublicKeys = func(verifyPath string, dir string) {
+					file(filepath.Join(verifyPath, "gpg-public-keys"), 0644, []byte(gpg("--armor", "--export", "shipwright@example.com")))
+				}
+
+				withSignedRepository(gpgSigned, gpgPublicKeys, func(url string, verifyPath string) {
+					withTempDir(func(target string) {
+						Expect(run(
+							"--url", url,
+							"--target", target,
+							"--verify-path", verifyPath,
+						)).ToNot(HaveOccurred())
+
+						Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("signed"))
+					})
+				})
+			})
+		})
+
+		It("should fail in case the verify path does not contain trusted keys", func() {
+			withTempDir(func(verifyPath string) {
+				withTempDir(func(target string) {
+					Expect(exitCode(run(
+						"--url", "https://github.com/foo/bar",
+						"--target", target,
+						"--verify-path", verifyPath,
+					))).To(Equal(104))
+				})
+			})
+		})
+	})
 })
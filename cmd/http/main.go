@@ -0,0 +1,277 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/build/pkg/archive"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+)
+
+const (
+	extractTar = "tar"
+	extractZip = "zip"
+
+	// defaultFileName is the name of the downloaded file when the URL has no path, like wget does it
+	defaultFileName = "index.html"
+)
+
+// retryDelay is the time to wait before the next attempt, it is multiplied with the number of the attempt
+var retryDelay = time.Second
+
+type settings struct {
+	url            string
+	target         string
+	sha256         string
+	extract        string
+	secretPath     string
+	retries        uint
+	resultFileSHA  string
+	resultFileSize string
+}
+
+var flagValues settings
+
+// retryableError is an error after which the download is attempted again
+type retryableError struct {
+	cause error
+}
+
+func (e *retryableError) Error() string {
+	return e.cause.Error()
+}
+
+func init() {
+	// Add the zap logger flag set to the CLI. The flag set must
+	// be added before calling pflag.Parse().
+	pflag.CommandLine.AddGoFlagSet(ctxlog.CustomZapFlagSet())
+
+	pflag.StringVar(&flagValues.url, "url", "", "The URL of the artifact to download")
+	pflag.StringVar(&flagValues.target, "target", "", "The target directory to download or extract the artifact to")
+	pflag.StringVar(&flagValues.sha256, "sha256", "", "The hex encoded SHA-256 checksum that the artifact must match. Optional.")
+	pflag.StringVar(&flagValues.extract, "extract", "", "The archive format of the artifact to extract it, either tar or zip. Optional, the artifact is stored as a file otherwise.")
+	pflag.StringVar(&flagValues.secretPath, "secret-path", "", "A directory that contains a secret. Either username and password for basic authentication, or a token that is sent as bearer token. Optional.")
+	pflag.UintVar(&flagValues.retries, "retries", 3, "The number of retries when the download fails because of a network error or a server error")
+	pflag.StringVar(&flagValues.resultFileSHA, "result-file-sha256", "", "A file to write the SHA-256 checksum of the artifact to")
+	pflag.StringVar(&flagValues.resultFileSize, "result-file-size", "", "A file to write the size of the artifact in bytes to")
+}
+
+func main() {
+	// create logger and context
+	l := ctxlog.NewLogger("http")
+	ctx := ctxlog.NewParentContext(l)
+
+	if err := Execute(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Execute performs flag parsing, input validation and the download of the artifact
+func Execute(ctx context.Context) error {
+	flagValues = settings{retries: 3}
+	pflag.Parse()
+
+	err := runDownload(ctx)
+	if err != nil {
+		ctxlog.Error(ctx, err, "program failed with an error")
+	}
+
+	return err
+}
+
+func runDownload(ctx context.Context) error {
+	if flagValues.url == "" {
+		return errors.New("the 'url' argument must not be empty")
+	}
+
+	if flagValues.target == "" {
+		return errors.New("the 'target' argument must not be empty")
+	}
+
+	switch flagValues.extract {
+	case "", extractTar, extractZip:
+	default:
+		return fmt.Errorf("the archive format %q is not supported, use %s or %s", flagValues.extract, extractTar, extractZip)
+	}
+
+	artifactURL, err := url.Parse(flagValues.url)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := loadAuthorization(flagValues.secretPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(flagValues.target, 0755); err != nil {
+		return err
+	}
+
+	// download into a temporary file in the target directory, so that it can be renamed
+	// to its final name once the checksum is verified
+	file, err := ioutil.TempFile(flagValues.target, ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	var checksum string
+	var size int64
+	for attempt := uint(0); ; attempt++ {
+		checksum, size, err = download(ctx, artifactURL, authorization, file)
+		if err == nil {
+			break
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt >= flagValues.retries {
+			return err
+		}
+
+		ctxlog.Info(ctx, "download failed, retrying", "url", artifactURL.Redacted(), "attempt", attempt+1, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * retryDelay):
+		}
+	}
+
+	if flagValues.sha256 != "" && !strings.EqualFold(flagValues.sha256, checksum) {
+		return fmt.Errorf("the SHA-256 checksum %s of the artifact does not match the expected checksum %s", checksum, flagValues.sha256)
+	}
+
+	ctxlog.Info(ctx, "downloaded artifact", "url", artifactURL.Redacted(), "sha256", checksum, "size", size)
+
+	switch flagValues.extract {
+	case extractTar:
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := archive.ExtractTar(file, flagValues.target); err != nil {
+			return err
+		}
+
+	case extractZip:
+		if err := archive.ExtractZip(file, size, flagValues.target); err != nil {
+			return err
+		}
+
+	default:
+		if err := file.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(file.Name(), filepath.Join(flagValues.target, fileName(artifactURL))); err != nil {
+			return err
+		}
+	}
+
+	if flagValues.resultFileSHA != "" {
+		if err := ioutil.WriteFile(flagValues.resultFileSHA, []byte(checksum), 0644); err != nil {
+			return err
+		}
+	}
+
+	if flagValues.resultFileSize != "" {
+		if err := ioutil.WriteFile(flagValues.resultFileSize, []byte(strconv.FormatInt(size, 10)), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// download writes the artifact to the file, replacing the content of a previous attempt,
+// and returns its checksum and size
+func download(ctx context.Context, artifactURL *url.URL, authorization string, file *os.File) (string, int64, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	if err := file.Truncate(0); err != nil {
+		return "", 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifactURL.String(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", 0, &retryableError{cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("failed to download %s: unexpected status %s", artifactURL.Redacted(), resp.Status)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return "", 0, &retryableError{cause: err}
+		}
+		return "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), resp.Body)
+	if err != nil {
+		return "", 0, &retryableError{cause: err}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// loadAuthorization returns the value of the Authorization header for the
+// credentials in the secret directory
+func loadAuthorization(secretPath string) (string, error) {
+	if secretPath == "" {
+		return "", nil
+	}
+
+	if token, err := ioutil.ReadFile(filepath.Join(secretPath, "token")); err == nil {
+		return "Bearer " + strings.TrimSpace(string(token)), nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	username, usernameErr := ioutil.ReadFile(filepath.Join(secretPath, "username"))
+	password, passwordErr := ioutil.ReadFile(filepath.Join(secretPath, "password"))
+	if usernameErr != nil || passwordErr != nil {
+		return "", errors.New("the secret must contain either a token, or a username and a password")
+	}
+
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(string(username), string(password))
+	return req.Header.Get("Authorization"), nil
+}
+
+// fileName returns the name of the file to store the artifact in, which is the last
+// element of the URL path
+func fileName(artifactURL *url.URL) string {
+	name := path.Base(artifactURL.Path)
+	if name == "." || name == "/" {
+		return defaultFileName
+	}
+	return name
+}
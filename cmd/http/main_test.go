@@ -0,0 +1,292 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/shipwright-io/build/cmd/http"
+)
+
+var _ = Describe("HTTP Downloader", func() {
+	var (
+		server   *httptest.Server
+		requests int32
+		failures int32
+		files    map[string][]byte
+	)
+
+	var run = func(args ...string) error {
+		os.Args = append([]string{"tool", "--zap-log-level", "fatal"}, args...)
+		return Execute(context.TODO())
+	}
+
+	var withTempDir = func(f func(target string)) {
+		path, err := ioutil.TempDir(os.TempDir(), "http")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(path)
+
+		f(path)
+	}
+
+	var filecontent = func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	var checksum = func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	var tarball = func(entries map[string]string) []byte {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for path, content := range entries {
+			Expect(tarWriter.WriteHeader(&tar.Header{Name: path, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tarWriter.Write([]byte(content))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+		return buffer.Bytes()
+	}
+
+	BeforeEach(func() {
+		requests = 0
+		failures = 0
+		files = map[string][]byte{}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if atomic.LoadInt32(&failures) > 0 {
+				atomic.AddInt32(&failures, -1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			switch r.URL.Path {
+			case "/private/logo.svg":
+				if username, password, ok := r.BasicAuth(); !ok || username != "shipwright" || password != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+			case "/token/logo.svg":
+				if r.Header.Get("Authorization") != "Bearer secret-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+			}
+
+			content, ok := files[filepath.Base(r.URL.Path)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("validations and error cases", func() {
+		It("should fail in case mandatory arguments are missing", func() {
+			Expect(run()).To(HaveOccurred())
+		})
+
+		It("should fail in case the archive format is not supported", func() {
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/logo.svg",
+					"--target", target,
+					"--extract", "rar",
+				)).To(HaveOccurred())
+			})
+		})
+
+		It("should fail without retries in case the artifact does not exist", func() {
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/missing.svg",
+					"--target", target,
+				)).To(HaveOccurred())
+
+				Expect(requests).To(Equal(int32(1)))
+			})
+		})
+
+		It("should fail in case the checksum does not match and not keep the artifact", func() {
+			files["logo.svg"] = []byte("<svg/>")
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/logo.svg",
+					"--target", target,
+					"--sha256", checksum([]byte("something else")),
+				)).To(HaveOccurred())
+
+				entries, err := ioutil.ReadDir(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(entries).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("downloading an artifact", func() {
+		It("should store the artifact under its name and write the checksum and size to the result files", func() {
+			files["logo.svg"] = []byte("<svg/>")
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/logo.svg",
+					"--target", target,
+					"--sha256", checksum(files["logo.svg"]),
+					"--result-file-sha256", filepath.Join(target, "sha256"),
+					"--result-file-size", filepath.Join(target, "size"),
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "logo.svg"))).To(Equal("<svg/>"))
+				Expect(filecontent(filepath.Join(target, "sha256"))).To(Equal(checksum(files["logo.svg"])))
+				Expect(filecontent(filepath.Join(target, "size"))).To(Equal(strconv.Itoa(len(files["logo.svg"]))))
+			})
+		})
+
+		It("should retry the download in case the server fails", func() {
+			files["logo.svg"] = []byte("<svg/>")
+			failures = 1
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/logo.svg",
+					"--target", target,
+				)).To(Succeed())
+
+				Expect(requests).To(Equal(int32(2)))
+				Expect(filecontent(filepath.Join(target, "logo.svg"))).To(Equal("<svg/>"))
+			})
+		})
+
+		It("should authenticate with the username and password of the secret", func() {
+			files["logo.svg"] = []byte("<svg/>")
+
+			withTempDir(func(target string) {
+				secretPath := filepath.Join(target, ".secret")
+				Expect(os.Mkdir(secretPath, 0700)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(secretPath, "username"), []byte("shipwright"), 0600)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(secretPath, "password"), []byte("secret"), 0600)).To(Succeed())
+
+				Expect(run(
+					"--url", server.URL+"/private/logo.svg",
+					"--target", filepath.Join(target, "sources"),
+				)).To(HaveOccurred())
+
+				Expect(run(
+					"--url", server.URL+"/private/logo.svg",
+					"--target", filepath.Join(target, "sources"),
+					"--secret-path", secretPath,
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "sources", "logo.svg"))).To(Equal("<svg/>"))
+			})
+		})
+
+		It("should authenticate with the token of the secret", func() {
+			files["logo.svg"] = []byte("<svg/>")
+
+			withTempDir(func(target string) {
+				secretPath := filepath.Join(target, ".secret")
+				Expect(os.Mkdir(secretPath, 0700)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(secretPath, "token"), []byte("secret-token\n"), 0600)).To(Succeed())
+
+				Expect(run(
+					"--url", server.URL+"/token/logo.svg",
+					"--target", filepath.Join(target, "sources"),
+					"--secret-path", secretPath,
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "sources", "logo.svg"))).To(Equal("<svg/>"))
+			})
+		})
+	})
+
+	Context("extracting an archive", func() {
+		It("should extract a gzip compressed tar archive", func() {
+			files["sources.tar.gz"] = tarball(map[string]string{
+				"main.go":       "package main",
+				"cmd/README.md": "# cmd",
+			})
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/sources.tar.gz",
+					"--target", target,
+					"--extract", "tar",
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "main.go"))).To(Equal("package main"))
+				Expect(filecontent(filepath.Join(target, "cmd", "README.md"))).To(Equal("# cmd"))
+				_, err := os.Stat(filepath.Join(target, "sources.tar.gz"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+
+		It("should keep archive entries inside of the target directory", func() {
+			files["sources.tar.gz"] = tarball(map[string]string{"../escaped.txt": "content"})
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/sources.tar.gz",
+					"--target", filepath.Join(target, "sources"),
+					"--extract", "tar",
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "sources", "escaped.txt"))).To(Equal("content"))
+				_, err := os.Stat(filepath.Join(target, "escaped.txt"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+
+		It("should extract a zip archive", func() {
+			var buffer bytes.Buffer
+			zipWriter := zip.NewWriter(&buffer)
+			writer, err := zipWriter.Create("src/index.js")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = writer.Write([]byte("console.log('hello')"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipWriter.Close()).To(Succeed())
+			files["sources.zip"] = buffer.Bytes()
+
+			withTempDir(func(target string) {
+				Expect(run(
+					"--url", server.URL+"/sources.zip",
+					"--target", target,
+					"--extract", "zip",
+				)).To(Succeed())
+
+				Expect(filecontent(filepath.Join(target, "src", "index.js"))).To(Equal("console.log('hello')"))
+			})
+		})
+	})
+})
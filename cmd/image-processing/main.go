@@ -0,0 +1,241 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+type settings struct {
+	image                     string
+	labels                    []string
+	annotations               []string
+	additionalTags            []string
+	buildRunName              string
+	commitShaFile             string
+	secretPath                string
+	signingKey                string
+	sbomFormat                string
+	resultFileImageDigest     string
+	resultFileImageTags       string
+	resultFileImageSignature  string
+	resultFileImageSBOMDigest string
+}
+
+var flagValues settings
+
+func init() {
+	// Add the zap logger flag set to the CLI. The flag set must
+	// be added before calling pflag.Parse().
+	pflag.CommandLine.AddGoFlagSet(ctxlog.CustomZapFlagSet())
+
+	pflag.StringVar(&flagValues.image, "image", "", "The reference of the image that the build strategy pushed")
+	pflag.StringArrayVar(&flagValues.labels, "label", nil, "A label in the format key=value to set in the image configuration. Can be specified multiple times.")
+	pflag.StringArrayVar(&flagValues.annotations, "annotation", nil, "An annotation in the format key=value to set in the image manifest. Can be specified multiple times.")
+	pflag.StringArrayVar(&flagValues.additionalTags, "additional-tag", nil, "A tag, besides the tag of the image, to push the image as. Can contain the placeholders $(commit-sha), $(commit-sha-short), $(buildrun-name) and $(timestamp). Can be specified multiple times.")
+	pflag.StringVar(&flagValues.buildRunName, "buildrun-name", "", "The name of the BuildRun to resolve the $(buildrun-name) placeholder")
+	pflag.StringVar(&flagValues.commitShaFile, "commit-sha-file", "", "A file that contains the commit SHA of the source to resolve the $(commit-sha) placeholders")
+	pflag.StringVar(&flagValues.secretPath, "secret-path", "", "A directory that contains a secret of type kubernetes.io/dockerconfigjson with the credentials of the registry. Optional.")
+	pflag.StringVar(&flagValues.signingKey, "signing-key", "", "A file that contains the unencrypted private key in PEM format to sign the image with. Optional.")
+	pflag.StringVar(&flagValues.sbomFormat, "sbom-format", "", "The format of the SBOM to generate and attach to the image, either SPDX or CycloneDX. Optional.")
+	pflag.StringVar(&flagValues.resultFileImageDigest, "result-file-image-digest", "", "A file to write the digest of the image to")
+	pflag.StringVar(&flagValues.resultFileImageTags, "result-file-image-tags", "", "A file to write the comma-separated list of tags of the image to")
+	pflag.StringVar(&flagValues.resultFileImageSBOMDigest, "result-file-image-sbom-digest", "", "A file to write the digest of the SBOM artifact of the image to")
+	pflag.StringVar(&flagValues.resultFileImageSignature, "result-file-image-signature", "", "A file to write the reference of the signature of the image to")
+}
+
+func main() {
+	// create logger and context
+	l := ctxlog.NewLogger("image-processing")
+	ctx := ctxlog.NewParentContext(l)
+
+	if err := Execute(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Execute performs flag parsing, input validation and the processing of the image
+func Execute(ctx context.Context) error {
+	flagValues = settings{}
+	pflag.Parse()
+
+	err := runImageProcessing(ctx)
+	if err != nil {
+		ctxlog.Error(ctx, err, "program failed with an error")
+	}
+
+	return err
+}
+
+func runImageProcessing(ctx context.Context) error {
+	if flagValues.image == "" {
+		return errors.New("the 'image' argument must not be empty")
+	}
+
+	ref, err := name.ParseReference(flagValues.image)
+	if err != nil {
+		return err
+	}
+
+	var signer crypto.Signer
+	if flagValues.signingKey != "" {
+		data, err := ioutil.ReadFile(flagValues.signingKey)
+		if err != nil {
+			return err
+		}
+
+		if signer, err = image.LoadPrivateKey(data); err != nil {
+			return fmt.Errorf("failed to load the signing key: %w", err)
+		}
+	}
+
+	var sbomFormat image.SBOMFormat
+	if flagValues.sbomFormat != "" {
+		if sbomFormat, err = image.ParseSBOMFormat(flagValues.sbomFormat); err != nil {
+			return err
+		}
+	}
+
+	labels, err := splitKeyValues(flagValues.labels)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := splitKeyValues(flagValues.annotations)
+	if err != nil {
+		return err
+	}
+
+	var dockerConfigFile string
+	if flagValues.secretPath != "" {
+		dockerConfigFile = filepath.Join(flagValues.secretPath, ".dockerconfigjson")
+	}
+
+	options, err := image.GetOptions(ctx, dockerConfigFile)
+	if err != nil {
+		return err
+	}
+
+	ctxlog.Info(ctx, "mutating image", "image", ref.String(), "labels", labels, "annotations", annotations)
+	digest, err := image.MutateImage(ref, labels, annotations, options...)
+	if err != nil {
+		return err
+	}
+
+	ctxlog.Info(ctx, "pushed image", "image", ref.String(), "digest", digest.String())
+	if flagValues.resultFileImageDigest != "" {
+		if err := ioutil.WriteFile(flagValues.resultFileImageDigest, []byte(digest.String()), 0644); err != nil {
+			return err
+		}
+	}
+
+	var tags []string
+	if tag, ok := ref.(name.Tag); ok {
+		tags = append(tags, tag.TagStr())
+	}
+
+	additionalTags, err := resolveAdditionalTags()
+	if err != nil {
+		return err
+	}
+
+	if len(additionalTags) > 0 {
+		ctxlog.Info(ctx, "tagging image", "image", ref.Context().String(), "digest", digest.String(), "tags", additionalTags)
+		if err := image.Tag(ref.Context().Digest(digest.String()), additionalTags, options...); err != nil {
+			return err
+		}
+		tags = append(tags, additionalTags...)
+	}
+
+	if flagValues.resultFileImageTags != "" {
+		if err := ioutil.WriteFile(flagValues.resultFileImageTags, []byte(strings.Join(tags, ",")), 0644); err != nil {
+			return err
+		}
+	}
+
+	if sbomFormat != "" {
+		ctxlog.Info(ctx, "attaching SBOM", "image", ref.Context().String(), "digest", digest.String(), "format", sbomFormat)
+		sbom, err := image.AttachSBOM(ref.Context().Digest(digest.String()), sbomFormat, options...)
+		if err != nil {
+			return err
+		}
+
+		ctxlog.Info(ctx, "pushed SBOM", "sbom", sbom.String())
+		if flagValues.resultFileImageSBOMDigest != "" {
+			if err := ioutil.WriteFile(flagValues.resultFileImageSBOMDigest, []byte(sbom.DigestStr()), 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	if signer != nil {
+		ctxlog.Info(ctx, "signing image", "image", ref.Context().String(), "digest", digest.String())
+		signature, err := image.Sign(ref.Context().Digest(digest.String()), signer, options...)
+		if err != nil {
+			return err
+		}
+
+		ctxlog.Info(ctx, "pushed signature", "signature", signature.String())
+		if flagValues.resultFileImageSignature != "" {
+			if err := ioutil.WriteFile(flagValues.resultFileImageSignature, []byte(signature.String()), 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveAdditionalTags() ([]string, error) {
+	values := image.TagValues{
+		BuildRunName: flagValues.buildRunName,
+		Timestamp:    time.Now(),
+	}
+
+	if flagValues.commitShaFile != "" {
+		data, err := ioutil.ReadFile(flagValues.commitShaFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		values.CommitSha = strings.TrimSpace(string(data))
+	}
+
+	var tags []string
+	for _, template := range flagValues.additionalTags {
+		tag, err := image.ResolveTag(template, values)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func splitKeyValues(values []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%q is not in the format key=value", value)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
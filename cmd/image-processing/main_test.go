@@ -0,0 +1,243 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/shipwright-io/build/cmd/image-processing"
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("Image Processing Resource", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	var run = func(args ...string) error {
+		os.Args = append([]string{"tool", "--zap-log-level", "fatal"}, args...)
+		return Execute(context.TODO())
+	}
+
+	var withTempFile = func(pattern string, f func(filename string)) {
+		file, err := ioutil.TempFile(os.TempDir(), pattern)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(file.Name())
+
+		f(file.Name())
+	}
+
+	var filecontent = func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	var pushImage = func() {
+		Expect(remote.Write(ref, mutate.MediaType(empty.Image, types.OCIManifestSchema1))).To(Succeed())
+	}
+
+	var get = func(ref name.Reference) *remote.Descriptor {
+		descriptor, err := remote.Get(ref)
+		Expect(err).ToNot(HaveOccurred())
+		return descriptor
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+
+		var err error
+		ref, err = name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/shipwright/sample:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("validations and error cases", func() {
+		It("should fail in case mandatory arguments are missing", func() {
+			Expect(run()).To(HaveOccurred())
+		})
+
+		It("should fail in case a label is not in the format key=value", func() {
+			Expect(run(
+				"--image", ref.String(),
+				"--label", "no-value",
+			)).To(HaveOccurred())
+		})
+
+		It("should fail in case the image does not exist", func() {
+			Expect(run(
+				"--image", ref.String(),
+				"--label", "team=a",
+			)).To(HaveOccurred())
+		})
+	})
+
+	Context("mutating the image", func() {
+		It("should set the labels and annotations and store the new digest in the result file", func() {
+			pushImage()
+
+			withTempFile("image-digest", func(filename string) {
+				Expect(run(
+					"--image", ref.String(),
+					"--label", "team=a",
+					"--label", "description=with=equals",
+					"--annotation", "org.opencontainers.image.vendor=shipwright",
+					"--result-file-image-digest", filename,
+				)).To(Succeed())
+
+				img, err := remote.Image(ref)
+				Expect(err).ToNot(HaveOccurred())
+
+				digest, err := img.Digest()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filecontent(filename)).To(Equal(digest.String()))
+
+				manifest, err := img.Manifest()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(manifest.Annotations).To(Equal(map[string]string{"org.opencontainers.image.vendor": "shipwright"}))
+
+				config, err := img.ConfigFile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config.Config.Labels).To(Equal(map[string]string{"team": "a", "description": "with=equals"}))
+			})
+		})
+	})
+
+	Context("tagging the image", func() {
+		It("should push the image under the resolved additional tags and store all tags in the result file", func() {
+			pushImage()
+
+			withTempFile("commit-sha", func(commitShaFile string) {
+				Expect(ioutil.WriteFile(commitShaFile, []byte("0e0583421a5e4bf562ffe33f3651e16ba0c78591\n"), 0644)).To(Succeed())
+
+				withTempFile("image-tags", func(filename string) {
+					Expect(run(
+						"--image", ref.String(),
+						"--additional-tag", "$(commit-sha-short)",
+						"--additional-tag", "$(buildrun-name)",
+						"--buildrun-name", "sample-buildrun",
+						"--commit-sha-file", commitShaFile,
+						"--result-file-image-tags", filename,
+					)).To(Succeed())
+
+					Expect(filecontent(filename)).To(Equal("latest,0e05834,sample-buildrun"))
+
+					latest := get(ref)
+					for _, tag := range []string{"0e05834", "sample-buildrun"} {
+						Expect(get(ref.Context().Tag(tag)).Digest).To(Equal(latest.Digest))
+					}
+				})
+			})
+		})
+
+		It("should fail in case the commit SHA is not available", func() {
+			pushImage()
+
+			Expect(run(
+				"--image", ref.String(),
+				"--additional-tag", "$(commit-sha)",
+			)).To(HaveOccurred())
+		})
+	})
+
+	Context("attaching an SBOM", func() {
+		It("should attach the SBOM to the image and store its digest in the result file", func() {
+			pushImage()
+
+			withTempFile("image-sbom-digest", func(filename string) {
+				Expect(run(
+					"--image", ref.String(),
+					"--sbom-format", "SPDX",
+					"--result-file-image-sbom-digest", filename,
+				)).To(Succeed())
+
+				referrers := get(ref.Context().Tag("sha256-" + get(ref).Digest.Hex))
+
+				index, err := v1.ParseIndexManifest(bytes.NewReader(referrers.Manifest))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(index.Manifests).To(HaveLen(1))
+				Expect(filecontent(filename)).To(Equal(index.Manifests[0].Digest.String()))
+			})
+		})
+
+		It("should fail in case the SBOM format is not supported", func() {
+			pushImage()
+
+			Expect(run(
+				"--image", ref.String(),
+				"--sbom-format", "unknown",
+			)).To(HaveOccurred())
+		})
+	})
+
+	Context("signing the image", func() {
+		var withSigningKey = func(f func(filename string)) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			der, err := x509.MarshalECPrivateKey(key)
+			Expect(err).ToNot(HaveOccurred())
+
+			withTempFile("signing-key", func(filename string) {
+				Expect(ioutil.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)).To(Succeed())
+				f(filename)
+			})
+		}
+
+		It("should push the signature of the image and store its reference in the result file", func() {
+			pushImage()
+
+			withSigningKey(func(signingKey string) {
+				withTempFile("image-signature", func(filename string) {
+					Expect(run(
+						"--image", ref.String(),
+						"--signing-key", signingKey,
+						"--result-file-image-signature", filename,
+					)).To(Succeed())
+
+					signature := get(image.SignatureTag(ref.Context().Digest(get(ref).Digest.String())))
+					Expect(filecontent(filename)).To(Equal(ref.Context().Digest(signature.Digest.String()).String()))
+				})
+			})
+		})
+
+		It("should fail in case the signing key is invalid", func() {
+			pushImage()
+
+			withTempFile("signing-key", func(signingKey string) {
+				Expect(ioutil.WriteFile(signingKey, []byte("not a key"), 0600)).To(Succeed())
+
+				Expect(run(
+					"--image", ref.String(),
+					"--signing-key", signingKey,
+				)).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -3,10 +3,12 @@ module github.com/shipwright-io/build
 go 1.15
 
 require (
+	github.com/docker/cli v20.10.2+incompatible
 	github.com/go-git/go-git/v5 v5.3.1-0.20210421110026-67d34902b0c4
 	github.com/go-logr/logr v0.4.0
 	github.com/go-logr/zapr v0.4.0 // indirect
 	github.com/go-openapi/spec v0.20.2
+	github.com/google/go-containerregistry v0.4.1-0.20210128200529-19c2b639fab1
 	github.com/onsi/ginkgo v1.12.1
 	github.com/onsi/gomega v1.10.3
 	github.com/prometheus/client_golang v1.9.0
@@ -4,15 +4,55 @@
 
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // BuildSourceType enumerates build source type names.
 type BuildSourceType string
 
-// BuildSource remote artifact definition, also known as "sources". Simple "name" and "url" pairs,
-// initially without "credentials" (authentication) support yet.
+// ExtractFormat is the archive format of a remote artifact that is extracted after the download
+type ExtractFormat string
+
+const (
+	// ExtractFormatTar extracts a tar archive, which can be gzip compressed
+	ExtractFormatTar ExtractFormat = "tar"
+
+	// ExtractFormatZip extracts a zip archive
+	ExtractFormatZip ExtractFormat = "zip"
+)
+
+// BuildSource remote artifact definition, also known as "sources". The artifact is downloaded
+// over HTTP(S) into the source directory before the build starts.
 type BuildSource struct {
 	// Name instance entry.
 	Name string `json:"name"`
 
 	// URL remote artifact location.
 	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA-256 checksum that the downloaded artifact must match.
+	//
+	// +optional
+	SHA256 *string `json:"sha256,omitempty"`
+
+	// Credentials references a Secret that contains credentials to download the artifact.
+	// The Secret contains either a username and password for basic authentication, or a
+	// token that is sent as bearer token.
+	//
+	// +optional
+	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+
+	// Extract defines the archive format of the artifact, either tar or zip. When it is
+	// defined, the archive is extracted instead of stored as a file.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=tar;zip
+	Extract *ExtractFormat `json:"extract,omitempty"`
+
+	// TargetPath is the directory, relative to the source directory, to which the artifact
+	// is downloaded or extracted. If not defined, the source directory is used.
+	//
+	// +optional
+	TargetPath *string `json:"targetPath,omitempty"`
 }
@@ -26,6 +26,10 @@ const (
 	SpecSourceSecretRefNotFound BuildReason = "SpecSourceSecretRefNotFound"
 	// SpecOutputSecretRefNotFound indicates the referenced secret in output is missing
 	SpecOutputSecretRefNotFound BuildReason = "SpecOutputSecretRefNotFound"
+	// SpecOutputSigningSecretRefNotFound indicates the referenced secret with the signing key of the output is missing
+	SpecOutputSigningSecretRefNotFound BuildReason = "SpecOutputSigningSecretRefNotFound"
+	// SpecSourceConfigMapRefNotFound indicates the referenced configmap in source is missing
+	SpecSourceConfigMapRefNotFound BuildReason = "SpecSourceConfigMapRefNotFound"
 	// SpecBuilderSecretRefNotFound indicates the referenced secret in builder is missing
 	SpecBuilderSecretRefNotFound BuildReason = "SpecBuilderSecretRefNotFound"
 	// MultipleSecretRefNotFound indicates that multiple secrets are missing
@@ -36,12 +40,57 @@ const (
 	RestrictedParametersInUse BuildReason = "RestrictedParametersInUse"
 	// UndefinedParameter indicates the definition of param that was not defined in the strategy parameters
 	UndefinedParameter BuildReason = "UndefinedParameter"
+	// WrongParameterValueType indicates that a parameter value does not match the type of the strategy parameter
+	WrongParameterValueType BuildReason = "WrongParameterValueType"
+	// InvalidParameterValue indicates that a parameter value is not one of the allowed values,
+	// or does not match the pattern of the strategy parameter
+	InvalidParameterValue BuildReason = "InvalidParameterValue"
+	// ParamValueRefNotFound indicates that a ConfigMap or Secret, or one of their keys, referenced
+	// by a parameter value does not exist
+	ParamValueRefNotFound BuildReason = "ParamValueRefNotFound"
+	// ProtectedEnvVarsInUse indicates the definition of environment variables that are protected by the strategy
+	ProtectedEnvVarsInUse BuildReason = "ProtectedEnvVarsInUse"
+	// UndefinedStep indicates that resources are defined for a step that does not exist in the strategy
+	UndefinedStep BuildReason = "UndefinedStep"
+	// ResourcesOutOfBounds indicates that the resources defined for the strategy steps are not
+	// within the resource bounds of the strategy
+	ResourcesOutOfBounds BuildReason = "ResourcesOutOfBounds"
+	// SchedulingNotAllowed indicates that a toleration, priority class or runtime class is set
+	// that is not within the scheduling bounds of the strategy
+	SchedulingNotAllowed BuildReason = "SchedulingNotAllowed"
+	// UndefinedCache indicates that a cache is bound that is not declared by the strategy
+	UndefinedCache BuildReason = "UndefinedCache"
+	// InvalidAdditionalTag indicates that an additional tag of the output image is not a valid tag
+	InvalidAdditionalTag BuildReason = "InvalidAdditionalTag"
+	// SpecSourcesInvalid indicates that an entry of the sources is not valid
+	SpecSourcesInvalid BuildReason = "SpecSourcesInvalid"
+	// SpecSourceInvalid indicates that the source defines neither or both of a Git URL and a source bundle image
+	SpecSourceInvalid BuildReason = "SpecSourceInvalid"
 	// RemoteRepositoryUnreachable indicates the referenced repository is unreachable
 	RemoteRepositoryUnreachable BuildReason = "RemoteRepositoryUnreachable"
 	// AllValidationsSucceeded indicates a Build was successfully validated
 	AllValidationsSucceeded = "all validations succeeded"
 )
 
+const (
+	// BuildReady indicates that all validations of the Build succeeded and
+	// that it can be used by BuildRuns
+	BuildReady Type = "Ready"
+
+	// BuildSourceValid indicates whether the sources of the Build are valid
+	BuildSourceValid Type = "SourceValid"
+
+	// BuildStrategyValid indicates whether the referenced strategy exists and the
+	// parameters of the Build match it
+	BuildStrategyValid Type = "StrategyValid"
+
+	// BuildCredentialsValid indicates whether all referenced secrets exist
+	BuildCredentialsValid Type = "CredentialsValid"
+
+	// BuildRuntimeValid indicates whether the runtime image definition is valid
+	BuildRuntimeValid Type = "RuntimeValid"
+)
+
 const (
 	// BuildDomain is the domain used for all labels and annotations for this resource
 	BuildDomain = "build.shipwright.io"
@@ -65,10 +114,25 @@ const (
 	AnnotationBuildVerifyRepository = BuildDomain + "/verify.repository"
 )
 
+// BuildRunPolicy defines how the BuildRuns of a Build are executed in relation to each other
+type BuildRunPolicy string
+
+const (
+	// RunPolicyParallel executes all BuildRuns of a Build at the same time
+	RunPolicyParallel BuildRunPolicy = "Parallel"
+
+	// RunPolicySerial executes the BuildRuns of a Build one after the other, in the order of their creation
+	RunPolicySerial BuildRunPolicy = "Serial"
+
+	// RunPolicySerialLatestOnly executes the BuildRuns of a Build one after the other, but only
+	// the latest of the queued BuildRuns is executed, the ones it supersedes are canceled
+	RunPolicySerialLatestOnly BuildRunPolicy = "SerialLatestOnly"
+)
+
 // BuildSpec defines the desired state of Build
 type BuildSpec struct {
-	// Source refers to the Git repository containing the
-	// source code to be built.
+	// Source refers to the Git repository or the source bundle image
+	// containing the source code to be built.
 	Source Source `json:"source"`
 
 	// Sources slice of BuildSource, defining external build artifacts complementary to VCS
@@ -99,6 +163,28 @@ type BuildSpec struct {
 	// +optional
 	ParamValues []ParamValue `json:"paramValues,omitempty"`
 
+	// Env contains additional environment variables that are set in all
+	// steps of the strategy. The names must not collide with the protected
+	// environment variables of the strategy.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources overrides the compute resources of the strategy steps, either
+	// of a single step or of all steps. The values must be within the resource
+	// bounds of the strategy.
+	// +optional
+	Resources []StepResources `json:"resources,omitempty"`
+
+	// Scheduling defines where the pods of the BuildRuns of this Build are
+	// scheduled. Its fields overwrite the scheduling defaults of the strategy.
+	// +optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
+
+	// Caches binds the caches of the strategy to PersistentVolumeClaims, so
+	// that their content is kept across the BuildRuns of this Build
+	// +optional
+	Caches []BuildCache `json:"caches,omitempty"`
+
 	// Runtime represents the runtime-image.
 	//
 	// Deprecated: This feature is deprecated and will be removed in a
@@ -117,6 +203,134 @@ type BuildSpec struct {
 	// +optional
 	// +kubebuilder:validation:Format=duration
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// RunPolicy defines how the BuildRuns of this Build are executed in
+	// relation to each other. Defaults to Parallel.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Parallel;Serial;SerialLatestOnly
+	RunPolicy *BuildRunPolicy `json:"runPolicy,omitempty"`
+
+	// Retention defines how long and how many of the completed BuildRuns
+	// of this Build are kept before they are deleted.
+	//
+	// +optional
+	Retention *BuildRetention `json:"retention,omitempty"`
+
+	// Retries defines how often a BuildRun of this Build is retried when its
+	// TaskRun fails for a reason that is considered temporary. The value can
+	// be overwritten in the BuildRun.
+	//
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
+}
+
+// StepResources overrides the compute resources of strategy steps
+type StepResources struct {
+	// Step is the name of the strategy step whose resources are overridden.
+	// The resources of all strategy steps are overridden if it is not set.
+	// +optional
+	Step string `json:"step,omitempty"`
+
+	// ResourceRequirements contains the limits and requests that override the
+	// ones of the strategy step, resources that are not listed are kept
+	corev1.ResourceRequirements `json:",inline"`
+}
+
+// Scheduling defines the constraints for scheduling the pod of a BuildRun
+type Scheduling struct {
+	// NodeSelector must match the labels of a node for the pod to be scheduled on it
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the pod
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity defines the node affinity, pod affinity and pod anti-affinity of the pod
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the pod
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName is the name of the RuntimeClass that is used to run the pod
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+}
+
+// BuildCache binds a cache of the strategy to a PersistentVolumeClaim
+type BuildCache struct {
+	// Name of the cache in the strategy
+	Name string `json:"name"`
+
+	// ClaimName is the name of an existing PersistentVolumeClaim in the namespace
+	// of the Build. If not set, a PersistentVolumeClaim is provisioned for the Build.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+}
+
+// Retries defines the retries of a failed BuildRun
+type Retries struct {
+	// Limit defines the maximum number of retries of a failed BuildRun.
+	//
+	// +kubebuilder:validation:Minimum=1
+	Limit int `json:"limit"`
+
+	// Backoff defines the time to wait before the first retry. It doubles
+	// with each further retry. Retries happen right away if not set.
+	//
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// Reasons are the reasons of the pod, or of a waiting or terminated container,
+	// for which a failed BuildRun is retried. Defaults to Evicted, ErrImagePull and
+	// ImagePullBackOff.
+	//
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+
+	// ExitCodes are the exit codes of a failed step for which the BuildRun is
+	// retried, for example the exit code of a step that failed to push the image.
+	// A failed step is not retried if not set.
+	//
+	// +optional
+	ExitCodes []int32 `json:"exitCodes,omitempty"`
+}
+
+// BuildRetention defines the cleanup of the completed BuildRuns of a Build
+type BuildRetention struct {
+	// SucceededLimit defines the maximum number of succeeded BuildRuns that are kept.
+	// With a limit of zero, succeeded BuildRuns are deleted once they complete.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SucceededLimit *int32 `json:"succeededLimit,omitempty"`
+
+	// FailedLimit defines the maximum number of failed BuildRuns that are kept.
+	// With a limit of zero, failed BuildRuns are deleted once they complete.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedLimit *int32 `json:"failedLimit,omitempty"`
+
+	// TTLAfterFinished defines how long a completed BuildRun is kept before it is deleted.
+	//
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	TTLAfterFinished *metav1.Duration `json:"ttlAfterFinished,omitempty"`
+}
+
+// GetRunPolicy returns the configured run policy, or Parallel in case
+// no run policy is set
+func (buildSpec *BuildSpec) GetRunPolicy() BuildRunPolicy {
+	if buildSpec == nil || buildSpec.RunPolicy == nil {
+		return RunPolicyParallel
+	}
+
+	return *buildSpec.RunPolicy
 }
 
 // StrategyName returns the name of the configured strategy, or 'undefined' in
@@ -133,6 +347,18 @@ func (buildSpec *BuildSpec) StrategyName() string {
 	return buildSpec.Strategy.Name
 }
 
+// DefaultRetryReasons are the reasons for which a failed BuildRun is retried if
+// its retries do not define reasons
+var DefaultRetryReasons = []string{"Evicted", "ErrImagePull", "ImagePullBackOff"}
+
+// GetReasons returns the reasons for which a failed BuildRun is retried
+func (retries *Retries) GetReasons() []string {
+	if retries.Reasons != nil {
+		return retries.Reasons
+	}
+	return DefaultRetryReasons
+}
+
 // Image refers to an container image with credentials
 type Image struct {
 	// Image is the reference of the image.
@@ -143,6 +369,61 @@ type Image struct {
 	//
 	// +optional
 	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+
+	// Labels references the additional labels to be applied on the image
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations references the additional annotations to be applied on the image
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// AdditionalTags are tags, besides the tag of the image, under which the image is pushed.
+	// They can contain the placeholders $(commit-sha), $(commit-sha-short), $(buildrun-name)
+	// and $(timestamp).
+	//
+	// +optional
+	AdditionalTags []string `json:"additionalTags,omitempty"`
+
+	// Signing configures the signing of the image after it got pushed. The signature
+	// is pushed as an artifact next to the image.
+	//
+	// +optional
+	Signing *ImageSigning `json:"signing,omitempty"`
+
+	// SBOM configures the generation of a software bill of materials of the image after
+	// it got pushed. The SBOM is attached to the image as a referrer artifact.
+	//
+	// +optional
+	SBOM *ImageSBOM `json:"sbom,omitempty"`
+}
+
+// ImageSigning defines how an image is signed
+type ImageSigning struct {
+	// SecretRef references the key of a Secret that holds the unencrypted private key
+	// in PEM format. ECDSA, RSA and Ed25519 keys are supported.
+	SecretRef corev1.SecretKeySelector `json:"secretRef"`
+}
+
+// SBOMFormat is the format of a software bill of materials
+type SBOMFormat string
+
+const (
+	// SBOMFormatSPDX generates the SBOM in the JSON format of SPDX
+	SBOMFormatSPDX SBOMFormat = "SPDX"
+
+	// SBOMFormatCycloneDX generates the SBOM in the JSON format of CycloneDX
+	SBOMFormatCycloneDX SBOMFormat = "CycloneDX"
+)
+
+// ImageSBOM defines how the software bill of materials of an image is generated
+type ImageSBOM struct {
+	// Format is the format of the SBOM, either SPDX or CycloneDX.
+	//
+	// +kubebuilder:validation:Enum=SPDX;CycloneDX
+	Format SBOMFormat `json:"format"`
 }
 
 // Runtime represents the runtime-image, created using parts of builder-image, and a different
@@ -192,17 +473,28 @@ type User struct {
 
 // BuildStatus defines the observed state of Build
 type BuildStatus struct {
-	// The Register status of the Build
+	// The Register status of the Build, it mirrors the status of the Ready condition
+	// Deprecated: use the Ready condition instead
 	// +optional
 	Registered corev1.ConditionStatus `json:"registered,omitempty"`
 
 	// The reason of the registered Build, it's an one-word camelcase
+	// Deprecated: use the reason of the Ready condition instead
 	// +optional
 	Reason BuildReason `json:"reason,omitempty"`
 
 	// The message of the registered Build, either an error or succeed message
+	// Deprecated: use the message of the Ready condition instead
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the Build spec that was last validated
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the results of the validations of the Build
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -211,8 +503,8 @@ type BuildStatus struct {
 // Build is the Schema representing a Build definition
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=builds,scope=Namespaced
-// +kubebuilder:printcolumn:name="Registered",type="string",JSONPath=".status.registered",description="The register status of the Build"
-// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.reason",description="The reason of the registered Build, either an error or succeed message"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="The ready status of the Build"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",description="The reason of the Ready condition, either an error or succeed reason"
 // +kubebuilder:printcolumn:name="BuildStrategyKind",type="string",JSONPath=".spec.strategy.kind",description="The BuildStrategy type which is used for this Build"
 // +kubebuilder:printcolumn:name="BuildStrategyName",type="string",JSONPath=".spec.strategy.name",description="The BuildStrategy name which is used for this Build"
 // +kubebuilder:printcolumn:name="CreationTime",type="date",JSONPath=".metadata.creationTimestamp",description="The create time of this Build"
@@ -236,3 +528,29 @@ type BuildList struct {
 func init() {
 	SchemeBuilder.Register(&Build{}, &BuildList{})
 }
+
+// GetCondition returns a condition based on a type from a list of Conditions
+func (bs *BuildStatus) GetCondition(t Type) *Condition {
+	for _, c := range bs.Conditions {
+		if c.Type == t {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetCondition updates a list of conditions with the provided condition, the
+// last transition time is kept when the status of the condition did not change
+func (bs *BuildStatus) SetCondition(condition *Condition) {
+	for i, c := range bs.Conditions {
+		if c.Type == condition.Type {
+			if c.Status == condition.Status {
+				condition.LastTransitionTime = c.LastTransitionTime
+			}
+			bs.Conditions[i] = *condition
+			return
+		}
+	}
+
+	bs.Conditions = append(bs.Conditions, *condition)
+}
@@ -20,10 +20,25 @@ const (
 	LabelBuildRunGeneration = BuildRunDomain + "/generation"
 )
 
+// BuildRunRequestedState defines the state that a user can request for a BuildRun
+type BuildRunRequestedState string
+
+const (
+	// BuildRunStateCancel indicates that the user wants to cancel the BuildRun,
+	// if not already canceled or terminated
+	BuildRunStateCancel BuildRunRequestedState = "BuildRunCanceled"
+)
+
 // BuildRunSpec defines the desired state of BuildRun
 type BuildRunSpec struct {
 	// BuildRef refers to the Build
-	BuildRef *BuildRef `json:"buildRef"`
+	// +optional
+	BuildRef *BuildRef `json:"buildRef,omitempty"`
+
+	// BuildSpec is an embedded Build specification that is used instead
+	// of a referenced Build. Either BuildRef or BuildSpec must be set.
+	// +optional
+	BuildSpec *BuildSpec `json:"buildSpec,omitempty"`
 
 	// ServiceAccount refers to the kubernetes serviceaccount
 	// which is used for resource control.
@@ -41,10 +56,39 @@ type BuildRunSpec struct {
 	// +optional
 	ParamValues []ParamValue `json:"paramValues,omitempty"`
 
+	// Env contains additional environment variables that are set in all
+	// steps of the strategy. They overwrite the ones with the same name
+	// that are defined in the Build.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources overrides the compute resources of the strategy steps, either
+	// of a single step or of all steps. They are applied after the ones that
+	// are defined in the Build.
+	// +optional
+	Resources []StepResources `json:"resources,omitempty"`
+
+	// Scheduling defines where the pod of the BuildRun is scheduled. Its
+	// fields overwrite the ones that are defined in the Build.
+	// +optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
+
 	// Output refers to the location where the generated
 	// image would be pushed to. It will overwrite the output image in build spec
 	// +optional
 	Output *Image `json:"output,omitempty"`
+
+	// State is used for canceling a BuildRun. Setting it to BuildRunCanceled
+	// stops the execution of the BuildRun and its TaskRun.
+	// +optional
+	// +kubebuilder:validation:Enum=BuildRunCanceled
+	State *BuildRunRequestedState `json:"state,omitempty"`
+
+	// Retries defines how often the BuildRun is retried when its TaskRun
+	// fails for a reason that is considered temporary. It overwrites the
+	// retries defined in the Build.
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
 }
 
 // BuildRunStatus defines the observed state of BuildRun
@@ -74,6 +118,39 @@ type BuildRunStatus struct {
 	// FailedAt points to the resource where the BuildRun failed
 	// +optional
 	FailedAt *FailedAt `json:"failedAt,omitempty"`
+
+	// Sources holds the results emitted from the step definition
+	// of the different sources
+	// +optional
+	Sources []SourceResult `json:"sources,omitempty"`
+
+	// Output holds the results emitted from the step definition
+	// of the build strategy, like the digest of the image
+	// +optional
+	Output *Output `json:"output,omitempty"`
+
+	// Attempts holds the TaskRuns that were executed for this BuildRun,
+	// there is more than one attempt when a failed BuildRun is retried
+	// +optional
+	Attempts []BuildRunAttempt `json:"attempts,omitempty"`
+}
+
+// BuildRunAttempt describes one execution of a BuildRun
+type BuildRunAttempt struct {
+	// TaskRunName is the name of the TaskRun of this attempt
+	TaskRunName string `json:"taskRunName"`
+
+	// Reason is the reason of the Succeeded condition of the TaskRun
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// StartTime is the time the TaskRun started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the TaskRun completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // FailedAt describes the location where the failure happened
@@ -82,6 +159,80 @@ type FailedAt struct {
 	Container string `json:"container,omitempty"`
 }
 
+// SourceResult holds the results emitted from a source step
+type SourceResult struct {
+	// Name is the name of the source
+	Name string `json:"name"`
+
+	// Git holds the results emitted from the
+	// step definition of a Git source
+	// +optional
+	Git *GitSourceResult `json:"git,omitempty"`
+
+	// Bundle holds the results emitted from the
+	// step definition of a source bundle image
+	// +optional
+	Bundle *BundleSourceResult `json:"bundle,omitempty"`
+
+	// HTTP holds the results emitted from the
+	// step definition of a HTTP source
+	// +optional
+	HTTP *HTTPSourceResult `json:"http,omitempty"`
+}
+
+// GitSourceResult holds the results emitted from the Git source step
+type GitSourceResult struct {
+	// CommitSha holds the commit sha of the cloned source
+	// +optional
+	CommitSha string `json:"commitSha,omitempty"`
+
+	// Ref holds the full name of the ref that was checked out, like
+	// refs/heads/main or refs/pull/1/head. It is empty for a commit sha.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+}
+
+// BundleSourceResult holds the results emitted from the source bundle step
+type BundleSourceResult struct {
+	// Digest holds the digest of the pulled source bundle image
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// HTTPSourceResult holds the results emitted from the HTTP source step
+type HTTPSourceResult struct {
+	// SHA256 holds the SHA-256 checksum of the downloaded artifact
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Size holds the size of the downloaded artifact in bytes
+	// +optional
+	Size int64 `json:"size,omitempty"`
+}
+
+// Output holds the information about the container image that the BuildRun built
+type Output struct {
+	// Digest holds the digest of the output image
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Size holds the compressed size of the output image
+	// +optional
+	Size int64 `json:"size,omitempty"`
+
+	// Tags holds all tags under which the output image was pushed
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Signature holds the reference of the signature of the output image
+	// +optional
+	Signature string `json:"signature,omitempty"`
+
+	// SBOMDigest holds the digest of the SBOM artifact that is attached to the output image
+	// +optional
+	SBOMDigest string `json:"sbomDigest,omitempty"`
+}
+
 // BuildRef can be used to refer to a specific instance of a Build.
 type BuildRef struct {
 	// Name of the referent; More info: http://kubernetes.io/docs/user-guide/identifiers#names
@@ -168,6 +319,41 @@ func init() {
 	SchemeBuilder.Register(&BuildRun{}, &BuildRunList{})
 }
 
+// IsCanceled returns true if the BuildRun's spec status is set to BuildRunCanceled state.
+func (br *BuildRun) IsCanceled() bool {
+	return br.Spec.State != nil && *br.Spec.State == BuildRunStateCancel
+}
+
+// IsStandalone returns true if the BuildRun embeds its Build specification
+// instead of referencing a Build
+func (br *BuildRun) IsStandalone() bool {
+	return br.Spec.BuildSpec != nil
+}
+
+// BuildName returns the name of the referenced Build, or an empty string
+// for a BuildRun that embeds its Build specification
+func (br *BuildRun) BuildName() string {
+	if br.Spec.BuildRef == nil {
+		return ""
+	}
+
+	return br.Spec.BuildRef.Name
+}
+
+// GetRetries returns the retries of the BuildRun, the ones defined in the
+// BuildRun have precedence over the ones of the Build
+func (br *BuildRun) GetRetries() *Retries {
+	if br.Spec.Retries != nil {
+		return br.Spec.Retries
+	}
+
+	if br.Status.BuildSpec != nil {
+		return br.Status.BuildSpec.Retries
+	}
+
+	return nil
+}
+
 // GetReason returns the condition Reason, it ensures that by getting the Reason
 // the call will not panic if the Condition is not present
 func (c *Condition) GetReason() string {
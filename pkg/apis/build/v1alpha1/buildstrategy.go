@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -16,10 +17,107 @@ const (
 	ClusterBuildStrategyKind BuildStrategyKind = "ClusterBuildStrategy"
 )
 
+// BuildStrategyReady indicates that the validation of a build strategy succeeded
+// and that it can be used by Builds
+const BuildStrategyReady Type = "Ready"
+
+// BuildStrategyReason is a type used for populating the reason of
+// the Ready condition of build strategies
+type BuildStrategyReason string
+
+const (
+	// StrategySucceeded indicates that the strategy passed all validations
+	StrategySucceeded BuildStrategyReason = "Succeeded"
+	// StrategyDuplicateStepNames indicates that more than one step has the same name
+	StrategyDuplicateStepNames BuildStrategyReason = "DuplicateStepNames"
+	// StrategyRestrictedParametersInUse indicates that a parameter collides with a system reserved parameter
+	StrategyRestrictedParametersInUse BuildStrategyReason = "RestrictedParametersInUse"
+	// StrategyUndefinedParameterReference indicates that a step references a parameter that is not defined
+	StrategyUndefinedParameterReference BuildStrategyReason = "UndefinedParameterReference"
+	// StrategyUndefinedResultReference indicates that a step references a result that is not defined
+	StrategyUndefinedResultReference BuildStrategyReason = "UndefinedResultReference"
+	// StrategyInvalidParameterDefinition indicates that the pattern or the defaults of a parameter are invalid
+	StrategyInvalidParameterDefinition BuildStrategyReason = "InvalidParameterDefinition"
+	// StrategyUndefinedCacheVolume indicates that a cache is declared for a volume that no step mounts
+	StrategyUndefinedCacheVolume BuildStrategyReason = "UndefinedCacheVolume"
+	// StrategyDeprecatedPlaceholdersInUse indicates that the strategy is valid, but a step
+	// uses the deprecated $(build.*) placeholders
+	StrategyDeprecatedPlaceholdersInUse BuildStrategyReason = "DeprecatedPlaceholdersInUse"
+)
+
 // BuildStrategySpec defines the desired state of BuildStrategy
 type BuildStrategySpec struct {
 	BuildSteps []BuildStep `json:"buildSteps,omitempty"`
 	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// ProtectedEnvVars lists the names of environment variables that Builds
+	// and BuildRuns must not set in the steps of the strategy
+	// +optional
+	ProtectedEnvVars []string `json:"protectedEnvVars,omitempty"`
+
+	// ResourceBounds limits the compute resources that Builds and BuildRuns
+	// can set for the steps of the strategy
+	// +optional
+	ResourceBounds *ResourceBounds `json:"resourceBounds,omitempty"`
+
+	// Scheduling defines the defaults for scheduling the pods of the BuildRuns
+	// that use the strategy, Builds and BuildRuns can overwrite its fields
+	// +optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
+
+	// SchedulingBounds lists the tolerations, priority classes and runtime classes
+	// that Builds and BuildRuns can set for the pods of the strategy
+	// +optional
+	SchedulingBounds *SchedulingBounds `json:"schedulingBounds,omitempty"`
+
+	// Caches declares volumes of the steps that hold caches, Builds can bind them
+	// to PersistentVolumeClaims so that they are kept across BuildRuns
+	// +optional
+	Caches []StrategyCache `json:"caches,omitempty"`
+}
+
+// StrategyCache declares a volume of the strategy steps that holds a cache
+type StrategyCache struct {
+	// Name of the cache, it must match the name of a volume mount of the steps
+	Name string `json:"name"`
+
+	// Description of the content of the cache
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Size of the PersistentVolumeClaim that is provisioned for a Build. Defaults to 1Gi.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// ResourceBounds defines the minimum and maximum quantities of compute resources
+type ResourceBounds struct {
+	// Min holds the minimum quantities of the resources
+	// +optional
+	Min corev1.ResourceList `json:"min,omitempty"`
+
+	// Max holds the maximum quantities of the resources
+	// +optional
+	Max corev1.ResourceList `json:"max,omitempty"`
+}
+
+// SchedulingBounds defines the scheduling settings that Builds and BuildRuns can set,
+// the ones that are not listed are reserved to the strategy
+type SchedulingBounds struct {
+	// Tolerations lists the tolerations that Builds and BuildRuns can set. A toleration
+	// is allowed when its key, operator, value and effect match one of the list.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassNames lists the names of the PriorityClasses that Builds and
+	// BuildRuns can set
+	// +optional
+	PriorityClassNames []string `json:"priorityClassNames,omitempty"`
+
+	// RuntimeClassNames lists the names of the RuntimeClasses that Builds and
+	// BuildRuns can set
+	// +optional
+	RuntimeClassNames []string `json:"runtimeClassNames,omitempty"`
 }
 
 // Parameter holds a name-description with a default value
@@ -36,9 +134,46 @@ type Parameter struct {
 	// +required
 	Description string `json:"description"`
 
-	// Reasonable default value for the parameter
+	// Type of the parameter, either string or array. Defaults to string.
+	// +optional
+	// +kubebuilder:validation:Enum=string;array
+	Type ParameterType `json:"type,omitempty"`
+
+	// Reasonable default value for the parameter of type string
 	// +optional
 	Default *string `json:"default"`
+
+	// Reasonable default values for the parameter of type array
+	// +optional
+	Defaults *[]string `json:"defaults,omitempty"`
+
+	// AllowedValues restricts the values of the parameter, for a parameter of type array
+	// every value must be one of them
+	// +optional
+	AllowedValues []string `json:"allowedValues,omitempty"`
+
+	// Pattern is a regular expression that the values of the parameter must match
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// ParameterType defines the type of a strategy parameter
+type ParameterType string
+
+const (
+	// ParameterTypeString is a parameter with a single string value
+	ParameterTypeString ParameterType = "string"
+
+	// ParameterTypeArray is a parameter with a list of string values
+	ParameterTypeArray ParameterType = "array"
+)
+
+// GetType returns the type of the parameter, defaulting to string
+func (p Parameter) GetType() ParameterType {
+	if p.Type == "" {
+		return ParameterTypeString
+	}
+	return p.Type
 }
 
 // BuildStep defines a partial step that needs to run in container for
@@ -49,6 +184,13 @@ type BuildStep struct {
 
 // BuildStrategyStatus defines the observed state of BuildStrategy
 type BuildStrategyStatus struct {
+	// ObservedGeneration is the generation of the strategy spec that was last validated
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the result of the validation of the strategy
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // BuildStrategyKind defines the type of BuildStrategy used by the build.
@@ -76,4 +218,35 @@ type BuilderStrategy interface {
 	GetResourceLabels() map[string]string
 	GetBuildSteps() []BuildStep
 	GetParameters() []Parameter
+	GetProtectedEnvVars() []string
+	GetResourceBounds() *ResourceBounds
+	GetScheduling() *Scheduling
+	GetSchedulingBounds() *SchedulingBounds
+	GetCaches() []StrategyCache
+}
+
+// GetCondition returns a condition based on a type from a list of Conditions
+func (bss *BuildStrategyStatus) GetCondition(t Type) *Condition {
+	for _, c := range bss.Conditions {
+		if c.Type == t {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetCondition updates a list of conditions with the provided condition, the
+// last transition time is kept when the status of the condition did not change
+func (bss *BuildStrategyStatus) SetCondition(condition *Condition) {
+	for i, c := range bss.Conditions {
+		if c.Type == condition.Type {
+			if c.Status == condition.Status {
+				condition.LastTransitionTime = c.LastTransitionTime
+			}
+			bss.Conditions[i] = *condition
+			return
+		}
+	}
+
+	bss.Conditions = append(bss.Conditions, *condition)
 }
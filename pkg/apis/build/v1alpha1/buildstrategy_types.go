@@ -79,6 +79,34 @@ func (s BuildStrategy) GetParameters() []Parameter {
 	return s.Spec.Parameters
 }
 
+// GetProtectedEnvVars returns the names of the environment variables that are
+// protected by the build strategy
+func (s BuildStrategy) GetProtectedEnvVars() []string {
+	return s.Spec.ProtectedEnvVars
+}
+
+// GetResourceBounds returns the bounds of the step resources that Builds and
+// BuildRuns can set for the build strategy
+func (s BuildStrategy) GetResourceBounds() *ResourceBounds {
+	return s.Spec.ResourceBounds
+}
+
+// GetScheduling returns the scheduling defaults of the build strategy
+func (s BuildStrategy) GetScheduling() *Scheduling {
+	return s.Spec.Scheduling
+}
+
+// GetSchedulingBounds returns the scheduling settings that Builds and BuildRuns
+// can set for the build strategy
+func (s BuildStrategy) GetSchedulingBounds() *SchedulingBounds {
+	return s.Spec.SchedulingBounds
+}
+
+// GetCaches returns the caches that are declared by the build strategy
+func (s BuildStrategy) GetCaches() []StrategyCache {
+	return s.Spec.Caches
+}
+
 func init() {
 	SchemeBuilder.Register(&BuildStrategy{}, &BuildStrategyList{})
 }
@@ -80,6 +80,34 @@ func (s ClusterBuildStrategy) GetParameters() []Parameter {
 	return s.Spec.Parameters
 }
 
+// GetProtectedEnvVars returns the names of the environment variables that are
+// protected by the cluster build strategy
+func (s ClusterBuildStrategy) GetProtectedEnvVars() []string {
+	return s.Spec.ProtectedEnvVars
+}
+
+// GetResourceBounds returns the bounds of the step resources that Builds and
+// BuildRuns can set for the cluster build strategy
+func (s ClusterBuildStrategy) GetResourceBounds() *ResourceBounds {
+	return s.Spec.ResourceBounds
+}
+
+// GetScheduling returns the scheduling defaults of the cluster build strategy
+func (s ClusterBuildStrategy) GetScheduling() *Scheduling {
+	return s.Spec.Scheduling
+}
+
+// GetSchedulingBounds returns the scheduling settings that Builds and BuildRuns
+// can set for the cluster build strategy
+func (s ClusterBuildStrategy) GetSchedulingBounds() *SchedulingBounds {
+	return s.Spec.SchedulingBounds
+}
+
+// GetCaches returns the caches that are declared by the cluster build strategy
+func (s ClusterBuildStrategy) GetCaches() []StrategyCache {
+	return s.Spec.Caches
+}
+
 func init() {
 	SchemeBuilder.Register(&ClusterBuildStrategy{}, &ClusterBuildStrategyList{})
 }
@@ -4,9 +4,39 @@
 
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // ParamValue is a key/value that populates a strategy parameter
 // used in the execution of the strategy steps
 type ParamValue struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name string `json:"name"`
+
+	// Value of a parameter of type string
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Values of a parameter of type array
+	// +optional
+	Values []string `json:"values,omitempty"`
+
+	// ValueFrom references a key of a ConfigMap or Secret that holds the value of
+	// a parameter of type string
+	// +optional
+	ValueFrom *ParamValueSource `json:"valueFrom,omitempty"`
+}
+
+// ParamValueSource references the source of a parameter value, only one of
+// its fields may be set
+type ParamValueSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the namespace of the Build,
+	// the value is passed to the steps as a parameter
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret in the namespace of the Build, the value
+	// is passed to the steps as an environment variable
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
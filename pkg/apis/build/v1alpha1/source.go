@@ -8,10 +8,44 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// Source describes the Git source repository to fetch.
+// GitSubmodules defines which submodules of a Git repository are cloned
+type GitSubmodules string
+
+const (
+	// GitSubmodulesNone does not clone submodules
+	GitSubmodulesNone GitSubmodules = "none"
+
+	// GitSubmodulesShallow clones the submodules of the repository, but not their nested submodules
+	GitSubmodulesShallow GitSubmodules = "shallow"
+
+	// GitSubmodulesRecursive clones the submodules of the repository and their nested submodules
+	GitSubmodulesRecursive GitSubmodules = "recursive"
+)
+
+// GitLFS defines whether the files of Git Large File Storage are downloaded
+type GitLFS string
+
+const (
+	// GitLFSEnabled downloads the Git LFS files of the checked out revision
+	GitLFSEnabled GitLFS = "enabled"
+
+	// GitLFSDisabled keeps the Git LFS pointer files in the checkout
+	GitLFSDisabled GitLFS = "disabled"
+)
+
+// Source describes the Git source repository or the source bundle image to fetch.
 type Source struct {
-	// URL describes the URL of the Git repository.
-	URL string `json:"url"`
+	// URL describes the URL of the Git repository. Either the URL or the
+	// BundleContainer must be defined.
+	//
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// BundleContainer describes the source bundle image to pull the source
+	// code from, instead of cloning a Git repository.
+	//
+	// +optional
+	BundleContainer *BundleContainer `json:"bundleContainer,omitempty"`
 
 	// Revision describes the Git revision (e.g., branch, tag, commit SHA,
 	// etc.) to fetch.
@@ -27,8 +61,94 @@ type Source struct {
 	ContextDir *string `json:"contextDir,omitempty"`
 
 	// Credentials references a Secret that contains credentials to access
-	// the repository.
+	// the repository. For a source bundle image, the Secret must be of type
+	// kubernetes.io/dockerconfigjson.
 	//
 	// +optional
 	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+
+	// Git describes how the Git repository is cloned. It can not be used
+	// with a BundleContainer.
+	//
+	// +optional
+	Git *GitSource `json:"git,omitempty"`
+}
+
+// GitSource describes how a Git repository is cloned
+type GitSource struct {
+	// Sparse lists the directories of the repository that are checked out, in
+	// addition to the files of the top-level directory. Only the file contents
+	// of these directories are downloaded. An empty list checks out the
+	// ContextDir.
+	//
+	// If not defined, the whole tree is checked out.
+	//
+	// +optional
+	Sparse *[]string `json:"sparse,omitempty"`
+
+	// Depth is the number of commits of the history that are cloned. Zero
+	// clones the whole history.
+	//
+	// If not defined, only the latest commit is cloned.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Depth *int `json:"depth,omitempty"`
+
+	// Submodules defines which submodules are cloned, either none, shallow
+	// for the submodules of the repository, or recursive for their nested
+	// submodules as well. Submodules are cloned with the same depth as the
+	// repository.
+	//
+	// If not defined, submodules are cloned recursively.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=none;shallow;recursive
+	Submodules *GitSubmodules `json:"submodules,omitempty"`
+
+	// SubmoduleCredentials references a Secret with the credentials for
+	// submodules on other hosts than the repository, either an SSH private
+	// key or a username and password.
+	//
+	// +optional
+	SubmoduleCredentials *corev1.LocalObjectReference `json:"submoduleCredentials,omitempty"`
+
+	// LFS defines whether the files of Git Large File Storage are downloaded,
+	// either enabled or disabled.
+	//
+	// If not defined, the Git LFS configuration of the environment is used.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=enabled;disabled
+	LFS *GitLFS `json:"lfs,omitempty"`
+
+	// Verify references the trusted keys that the signature of the checked
+	// out commit is verified with. A commit that is not signed with one of
+	// these keys fails the BuildRun.
+	//
+	// +optional
+	Verify *GitVerification `json:"verify,omitempty"`
+}
+
+// GitVerification references a Secret or a ConfigMap with the trusted keys,
+// either GPG public keys in the gpg-public-keys key, or SSH public keys in
+// the allowed-signers key in the format of ssh-keygen, or both. Only one of
+// its fields may be set.
+type GitVerification struct {
+	// SecretRef references a Secret with the trusted keys.
+	//
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// ConfigMapRef references a ConfigMap with the trusted keys.
+	//
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+}
+
+// BundleContainer describes a source bundle image, which is an image that
+// contains the source code in its layers
+type BundleContainer struct {
+	// Image is the reference of the source bundle image.
+	Image string `json:"image"`
 }
@@ -20,7 +20,7 @@ func (in *Build) DeepCopyInto(out *Build) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -42,6 +42,22 @@ func (in *Build) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildCache) DeepCopyInto(out *BuildCache) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildCache.
+func (in *BuildCache) DeepCopy() *BuildCache {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildList) DeepCopyInto(out *BuildList) {
 	*out = *in
@@ -91,6 +107,37 @@ func (in *BuildRef) DeepCopy() *BuildRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildRetention) DeepCopyInto(out *BuildRetention) {
+	*out = *in
+	if in.SucceededLimit != nil {
+		in, out := &in.SucceededLimit, &out.SucceededLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedLimit != nil {
+		in, out := &in.FailedLimit, &out.FailedLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildRetention.
+func (in *BuildRetention) DeepCopy() *BuildRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildRun) DeepCopyInto(out *BuildRun) {
 	*out = *in
@@ -119,6 +166,30 @@ func (in *BuildRun) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildRunAttempt) DeepCopyInto(out *BuildRunAttempt) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildRunAttempt.
+func (in *BuildRunAttempt) DeepCopy() *BuildRunAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildRunAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildRunList) DeepCopyInto(out *BuildRunList) {
 	*out = *in
@@ -160,6 +231,11 @@ func (in *BuildRunSpec) DeepCopyInto(out *BuildRunSpec) {
 		*out = new(BuildRef)
 		**out = **in
 	}
+	if in.BuildSpec != nil {
+		in, out := &in.BuildSpec, &out.BuildSpec
+		*out = new(BuildSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServiceAccount != nil {
 		in, out := &in.ServiceAccount, &out.ServiceAccount
 		*out = new(ServiceAccount)
@@ -173,13 +249,44 @@ func (in *BuildRunSpec) DeepCopyInto(out *BuildRunSpec) {
 	if in.ParamValues != nil {
 		in, out := &in.ParamValues, &out.ParamValues
 		*out = make([]ParamValue, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]StepResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Output != nil {
 		in, out := &in.Output, &out.Output
 		*out = new(Image)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(BuildRunRequestedState)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(Retries)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -226,6 +333,25 @@ func (in *BuildRunStatus) DeepCopyInto(out *BuildRunStatus) {
 		*out = new(FailedAt)
 		**out = **in
 	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SourceResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(Output)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]BuildRunAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -242,6 +368,26 @@ func (in *BuildRunStatus) DeepCopy() *BuildRunStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildSource) DeepCopyInto(out *BuildSource) {
 	*out = *in
+	if in.SHA256 != nil {
+		in, out := &in.SHA256, &out.SHA256
+		*out = new(string)
+		**out = **in
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Extract != nil {
+		in, out := &in.Extract, &out.Extract
+		*out = new(ExtractFormat)
+		**out = **in
+	}
+	if in.TargetPath != nil {
+		in, out := &in.TargetPath, &out.TargetPath
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -265,7 +411,9 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 		if **in != nil {
 			in, out := *in, *out
 			*out = make([]BuildSource, len(*in))
-			copy(*out, *in)
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
 		}
 	}
 	if in.Strategy != nil {
@@ -286,6 +434,32 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 	if in.ParamValues != nil {
 		in, out := &in.ParamValues, &out.ParamValues
 		*out = make([]ParamValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]StepResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Caches != nil {
+		in, out := &in.Caches, &out.Caches
+		*out = make([]BuildCache, len(*in))
 		copy(*out, *in)
 	}
 	if in.Runtime != nil {
@@ -299,6 +473,21 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.RunPolicy != nil {
+		in, out := &in.RunPolicy, &out.RunPolicy
+		*out = new(BuildRunPolicy)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(BuildRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(Retries)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -315,6 +504,13 @@ func (in *BuildSpec) DeepCopy() *BuildSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -351,7 +547,7 @@ func (in *BuildStrategy) DeepCopyInto(out *BuildStrategy) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -423,6 +619,33 @@ func (in *BuildStrategySpec) DeepCopyInto(out *BuildStrategySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ProtectedEnvVars != nil {
+		in, out := &in.ProtectedEnvVars, &out.ProtectedEnvVars
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceBounds != nil {
+		in, out := &in.ResourceBounds, &out.ResourceBounds
+		*out = new(ResourceBounds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchedulingBounds != nil {
+		in, out := &in.SchedulingBounds, &out.SchedulingBounds
+		*out = new(SchedulingBounds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Caches != nil {
+		in, out := &in.Caches, &out.Caches
+		*out = make([]StrategyCache, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -439,6 +662,13 @@ func (in *BuildStrategySpec) DeepCopy() *BuildStrategySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildStrategyStatus) DeepCopyInto(out *BuildStrategyStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -452,13 +682,45 @@ func (in *BuildStrategyStatus) DeepCopy() *BuildStrategyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleContainer) DeepCopyInto(out *BundleContainer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleContainer.
+func (in *BundleContainer) DeepCopy() *BundleContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleSourceResult) DeepCopyInto(out *BundleSourceResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleSourceResult.
+func (in *BundleSourceResult) DeepCopy() *BundleSourceResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleSourceResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterBuildStrategy) DeepCopyInto(out *ClusterBuildStrategy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -568,6 +830,114 @@ func (in *FailedAt) DeepCopy() *FailedAt {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSource) DeepCopyInto(out *GitSource) {
+	*out = *in
+	if in.Sparse != nil {
+		in, out := &in.Sparse, &out.Sparse
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.Depth != nil {
+		in, out := &in.Depth, &out.Depth
+		*out = new(int)
+		**out = **in
+	}
+	if in.Submodules != nil {
+		in, out := &in.Submodules, &out.Submodules
+		*out = new(GitSubmodules)
+		**out = **in
+	}
+	if in.SubmoduleCredentials != nil {
+		in, out := &in.SubmoduleCredentials, &out.SubmoduleCredentials
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.LFS != nil {
+		in, out := &in.LFS, &out.LFS
+		*out = new(GitLFS)
+		**out = **in
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(GitVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSource.
+func (in *GitSource) DeepCopy() *GitSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSourceResult) DeepCopyInto(out *GitSourceResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSourceResult.
+func (in *GitSourceResult) DeepCopy() *GitSourceResult {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSourceResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitVerification) DeepCopyInto(out *GitVerification) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitVerification.
+func (in *GitVerification) DeepCopy() *GitVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(GitVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSourceResult) DeepCopyInto(out *HTTPSourceResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceResult.
+func (in *HTTPSourceResult) DeepCopy() *HTTPSourceResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSourceResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
@@ -576,6 +946,35 @@ func (in *Image) DeepCopyInto(out *Image) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalTags != nil {
+		in, out := &in.AdditionalTags, &out.AdditionalTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Signing != nil {
+		in, out := &in.Signing, &out.Signing
+		*out = new(ImageSigning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SBOM != nil {
+		in, out := &in.SBOM, &out.SBOM
+		*out = new(ImageSBOM)
+		**out = **in
+	}
 	return
 }
 
@@ -589,9 +988,73 @@ func (in *Image) DeepCopy() *Image {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSBOM) DeepCopyInto(out *ImageSBOM) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSBOM.
+func (in *ImageSBOM) DeepCopy() *ImageSBOM {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSBOM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSigning) DeepCopyInto(out *ImageSigning) {
+	*out = *in
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSigning.
+func (in *ImageSigning) DeepCopy() *ImageSigning {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSigning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Output) DeepCopyInto(out *Output) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
+func (in *Output) DeepCopy() *Output {
+	if in == nil {
+		return nil
+	}
+	out := new(Output)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParamValue) DeepCopyInto(out *ParamValue) {
 	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParamValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -605,6 +1068,32 @@ func (in *ParamValue) DeepCopy() *ParamValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParamValueSource) DeepCopyInto(out *ParamValueSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamValueSource.
+func (in *ParamValueSource) DeepCopy() *ParamValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParamValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
@@ -613,6 +1102,20 @@ func (in *Parameter) DeepCopyInto(out *Parameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -626,6 +1129,67 @@ func (in *Parameter) DeepCopy() *Parameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retries) DeepCopyInto(out *Retries) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExitCodes != nil {
+		in, out := &in.ExitCodes, &out.ExitCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retries.
+func (in *Retries) DeepCopy() *Retries {
+	if in == nil {
+		return nil
+	}
+	out := new(Retries)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Runtime) DeepCopyInto(out *Runtime) {
 	*out = *in
@@ -677,6 +1241,84 @@ func (in *Runtime) DeepCopy() *Runtime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scheduling) DeepCopyInto(out *Scheduling) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scheduling.
+func (in *Scheduling) DeepCopy() *Scheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(Scheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingBounds) DeepCopyInto(out *SchedulingBounds) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PriorityClassNames != nil {
+		in, out := &in.PriorityClassNames, &out.PriorityClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuntimeClassNames != nil {
+		in, out := &in.RuntimeClassNames, &out.RuntimeClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingBounds.
+func (in *SchedulingBounds) DeepCopy() *SchedulingBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccount) DeepCopyInto(out *ServiceAccount) {
 	*out = *in
@@ -701,6 +1343,11 @@ func (in *ServiceAccount) DeepCopy() *ServiceAccount {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
+	if in.BundleContainer != nil {
+		in, out := &in.BundleContainer, &out.BundleContainer
+		*out = new(BundleContainer)
+		**out = **in
+	}
 	if in.Revision != nil {
 		in, out := &in.Revision, &out.Revision
 		*out = new(string)
@@ -716,6 +1363,11 @@ func (in *Source) DeepCopyInto(out *Source) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -729,6 +1381,54 @@ func (in *Source) DeepCopy() *Source {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceResult) DeepCopyInto(out *SourceResult) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSourceResult)
+		**out = **in
+	}
+	if in.Bundle != nil {
+		in, out := &in.Bundle, &out.Bundle
+		*out = new(BundleSourceResult)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPSourceResult)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceResult.
+func (in *SourceResult) DeepCopy() *SourceResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepResources) DeepCopyInto(out *StepResources) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepResources.
+func (in *StepResources) DeepCopy() *StepResources {
+	if in == nil {
+		return nil
+	}
+	out := new(StepResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Strategy) DeepCopyInto(out *Strategy) {
 	*out = *in
@@ -750,6 +1450,27 @@ func (in *Strategy) DeepCopy() *Strategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyCache) DeepCopyInto(out *StrategyCache) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyCache.
+func (in *StrategyCache) DeepCopy() *StrategyCache {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -0,0 +1,263 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive extracts tar and zip archives into a target directory. The entries
+// of an archive can not write outside of the target directory, neither with their
+// names, nor through symbolic links that previous entries created.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// WhiteoutPrefix marks a file of an image layer that deletes the file of a previous layer
+	WhiteoutPrefix = ".wh."
+	// OpaqueWhiteout marks a directory of an image layer that hides the files of previous layers
+	OpaqueWhiteout = ".wh..wh..opq"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ExtractTar extracts the tar archive, which can be gzip compressed, to the target directory
+func ExtractTar(reader io.Reader, target string) error {
+	return extractTar(reader, target, false)
+}
+
+// ExtractLayer extracts the tar archive of an image layer, which can be gzip compressed, to the
+// target directory, and applies the whiteouts of the layer to the files of previous layers
+func ExtractLayer(reader io.Reader, target string) error {
+	return extractTar(reader, target, true)
+}
+
+func extractTar(reader io.Reader, target string, whiteouts bool) error {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+
+	bufferedReader := bufio.NewReader(reader)
+	reader = bufferedReader
+	if magic, err := bufferedReader.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the tar archive: %w", err)
+		}
+
+		path, err := targetPath(target, header.Name)
+		if err != nil {
+			return err
+		}
+		dir, base := filepath.Split(path)
+
+		if whiteouts && strings.HasPrefix(base, WhiteoutPrefix) {
+			if err := applyWhiteout(target, dir, base); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := makeParents(target, path); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := makeDir(path, os.FileMode(header.Mode)&os.ModePerm|0700); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := writeFile(path, tarReader, os.FileMode(header.Mode)&os.ModePerm); err != nil {
+				return err
+			}
+
+		case tar.TypeSymlink:
+			if err := replaceWith(path, func() error { return os.Symlink(header.Linkname, path) }); err != nil {
+				return err
+			}
+
+		case tar.TypeLink:
+			linkTarget, err := targetPath(target, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := checkParents(target, linkTarget); err != nil {
+				return err
+			}
+			if err := replaceWith(path, func() error { return os.Link(linkTarget, path) }); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ExtractZip extracts the zip archive to the target directory
+func ExtractZip(reader io.ReaderAt, size int64, target string) error {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return fmt.Errorf("failed to read the zip archive: %w", err)
+	}
+
+	for _, entry := range zipReader.File {
+		path, err := targetPath(target, entry.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := makeParents(target, path); err != nil {
+			return err
+		}
+
+		if entry.FileInfo().IsDir() {
+			if err := makeDir(path, entry.Mode()&os.ModePerm|0700); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(path, content, entry.Mode()&os.ModePerm)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyWhiteout removes the file that the whiteout marks, or the content of the
+// directory of an opaque whiteout
+func applyWhiteout(target string, dir string, base string) error {
+	if err := checkParents(target, filepath.Join(dir, base)); err != nil {
+		return err
+	}
+
+	if base != OpaqueWhiteout {
+		return os.RemoveAll(filepath.Join(dir, strings.TrimPrefix(base, WhiteoutPrefix)))
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetPath returns the path of the entry of an archive in the target directory, entries
+// with a path outside of the target directory are extracted relative to the target directory
+func targetPath(target string, entry string) (string, error) {
+	path := filepath.Join(target, filepath.Clean("/"+entry))
+	if path != target && !strings.HasPrefix(path, target+string(filepath.Separator)) {
+		return "", fmt.Errorf("the archive entry %s is outside of the target directory", entry)
+	}
+	return path, nil
+}
+
+// checkParents fails if one of the existing parent directories of the path inside of the
+// target directory is a symbolic link, since writing through it could leave the target directory
+func checkParents(target string, path string) error {
+	relative, err := filepath.Rel(target, filepath.Dir(path))
+	if err != nil || relative == "." {
+		return err
+	}
+
+	current := target
+	for _, element := range strings.Split(relative, string(filepath.Separator)) {
+		current = filepath.Join(current, element)
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("the archive entry %s is below the symbolic link %s", strings.TrimPrefix(path, target), strings.TrimPrefix(current, target))
+		}
+	}
+	return nil
+}
+
+// makeParents creates the missing parent directories of the path
+func makeParents(target string, path string) error {
+	if err := checkParents(target, path); err != nil {
+		return err
+	}
+	return os.MkdirAll(filepath.Dir(path), 0755)
+}
+
+// makeDir creates the directory, a file or symbolic link that a previous entry
+// created with the same path is replaced
+func makeDir(path string, mode os.FileMode) error {
+	if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(path, mode)
+}
+
+func writeFile(path string, content io.Reader, mode os.FileMode) error {
+	return replaceWith(path, func() error {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(file, content); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	})
+}
+
+// replaceWith removes the file that a previous entry created with the same path,
+// which might be a symbolic link, before it creates the new one
+func replaceWith(path string, create func() error) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return create()
+}
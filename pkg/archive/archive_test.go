@@ -0,0 +1,225 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/archive"
+)
+
+// entry is an entry of a tar archive, a symbolic or hard link if the link name is set
+type entry struct {
+	name     string
+	content  string
+	linkname string
+	typeflag byte
+}
+
+func file(name string, content string) entry {
+	return entry{name: name, content: content, typeflag: tar.TypeReg}
+}
+
+func symlink(name string, linkname string) entry {
+	return entry{name: name, linkname: linkname, typeflag: tar.TypeSymlink}
+}
+
+func hardlink(name string, linkname string) entry {
+	return entry{name: name, linkname: linkname, typeflag: tar.TypeLink}
+}
+
+func tarball(entries ...entry) *bytes.Buffer {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		Expect(tarWriter.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Linkname: entry.linkname,
+			Typeflag: entry.typeflag,
+			Mode:     0644,
+			Size:     int64(len(entry.content)),
+		})).To(Succeed())
+		_, err := tarWriter.Write([]byte(entry.content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+	return &buffer
+}
+
+var _ = Describe("Extracting archives", func() {
+	var dir, target, outside string
+
+	var filecontent = func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "archive")
+		Expect(err).ToNot(HaveOccurred())
+
+		target, outside = filepath.Join(dir, "target"), filepath.Join(dir, "outside")
+		Expect(os.Mkdir(target, 0755)).To(Succeed())
+		Expect(os.Mkdir(outside, 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("ExtractTar", func() {
+		It("extracts the files and links of a gzip compressed tar archive", func() {
+			Expect(archive.ExtractTar(tarball(
+				file("main.go", "package main"),
+				file("cmd/README.md", "# cmd"),
+				symlink("README.md", "cmd/README.md"),
+				hardlink("main-copy.go", "main.go"),
+			), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "main.go"))).To(Equal("package main"))
+			Expect(filecontent(filepath.Join(target, "cmd", "README.md"))).To(Equal("# cmd"))
+			Expect(filecontent(filepath.Join(target, "README.md"))).To(Equal("# cmd"))
+			Expect(filecontent(filepath.Join(target, "main-copy.go"))).To(Equal("package main"))
+		})
+
+		It("keeps entries inside of the target directory", func() {
+			Expect(archive.ExtractTar(tarball(
+				file("../outside/escaped.txt", "content"),
+				hardlink("link.txt", "../../outside/escaped.txt"),
+			), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "outside", "escaped.txt"))).To(Equal("content"))
+			Expect(filecontent(filepath.Join(target, "link.txt"))).To(Equal("content"))
+			Expect(filepath.Join(outside, "escaped.txt")).ToNot(BeAnExistingFile())
+		})
+
+		It("fails to write a file through a symbolic link of a previous entry", func() {
+			Expect(archive.ExtractTar(tarball(
+				symlink("src", outside),
+				file("src/escaped.txt", "content"),
+			), target)).To(MatchError("the archive entry /src/escaped.txt is below the symbolic link /src"))
+
+			Expect(filepath.Join(outside, "escaped.txt")).ToNot(BeAnExistingFile())
+		})
+
+		It("fails to write a file through a relative symbolic link of a previous entry", func() {
+			Expect(archive.ExtractTar(tarball(
+				symlink("src", "../outside"),
+				file("src/nested/escaped.txt", "content"),
+			), target)).ToNot(Succeed())
+
+			Expect(filepath.Join(outside, "nested")).ToNot(BeADirectory())
+		})
+
+		It("fails to create a hard link to a file through a symbolic link", func() {
+			Expect(ioutil.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)).To(Succeed())
+
+			Expect(archive.ExtractTar(tarball(
+				symlink("src", outside),
+				hardlink("secret.txt", "src/secret.txt"),
+			), target)).ToNot(Succeed())
+
+			Expect(filepath.Join(target, "secret.txt")).ToNot(BeAnExistingFile())
+		})
+
+		It("replaces a symbolic link with a file instead of writing to the link target", func() {
+			Expect(ioutil.WriteFile(filepath.Join(outside, "config.txt"), []byte("original"), 0644)).To(Succeed())
+
+			Expect(archive.ExtractTar(tarball(
+				symlink("config.txt", filepath.Join(outside, "config.txt")),
+				file("config.txt", "replaced"),
+			), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "config.txt"))).To(Equal("replaced"))
+			Expect(filecontent(filepath.Join(outside, "config.txt"))).To(Equal("original"))
+		})
+
+		It("replaces the links of duplicate entries", func() {
+			Expect(archive.ExtractTar(tarball(
+				file("a.txt", "a"),
+				file("b.txt", "b"),
+				symlink("link.txt", "a.txt"),
+				symlink("link.txt", "b.txt"),
+				hardlink("copy.txt", "a.txt"),
+				hardlink("copy.txt", "b.txt"),
+			), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "link.txt"))).To(Equal("b"))
+			Expect(filecontent(filepath.Join(target, "copy.txt"))).To(Equal("b"))
+		})
+
+		It("does not apply whiteouts", func() {
+			Expect(archive.ExtractTar(tarball(
+				file("main.go", "package main"),
+				file(".wh.main.go", ""),
+			), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "main.go"))).To(Equal("package main"))
+		})
+	})
+
+	Context("ExtractLayer", func() {
+		It("applies the whiteouts of the layer", func() {
+			Expect(archive.ExtractLayer(tarball(
+				file("main.go", "package main"),
+				file("docs/README.md", "# Sample"),
+				file("docs/guide.md", "# Guide"),
+			), target)).To(Succeed())
+
+			Expect(archive.ExtractLayer(tarball(
+				file(".wh.main.go", ""),
+				file("docs/.wh..wh..opq", ""),
+				file("docs/index.md", "# Index"),
+			), target)).To(Succeed())
+
+			Expect(filepath.Join(target, "main.go")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(target, "docs", "README.md")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(target, "docs", "guide.md")).ToNot(BeAnExistingFile())
+			Expect(filecontent(filepath.Join(target, "docs", "index.md"))).To(Equal("# Index"))
+		})
+
+		It("fails to apply a whiteout through a symbolic link of a previous layer", func() {
+			Expect(ioutil.WriteFile(filepath.Join(outside, "keep.txt"), []byte("keep"), 0644)).To(Succeed())
+
+			Expect(archive.ExtractLayer(tarball(symlink("src", outside)), target)).To(Succeed())
+			Expect(archive.ExtractLayer(tarball(file("src/.wh..wh..opq", "")), target)).ToNot(Succeed())
+
+			Expect(filecontent(filepath.Join(outside, "keep.txt"))).To(Equal("keep"))
+		})
+	})
+
+	Context("ExtractZip", func() {
+		It("extracts the files of a zip archive inside of the target directory", func() {
+			var buffer bytes.Buffer
+			zipWriter := zip.NewWriter(&buffer)
+			for name, content := range map[string]string{"src/index.js": "console.log('hello')", "../escaped.txt": "content"} {
+				writer, err := zipWriter.Create(name)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = writer.Write([]byte(content))
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(zipWriter.Close()).To(Succeed())
+
+			Expect(archive.ExtractZip(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), target)).To(Succeed())
+
+			Expect(filecontent(filepath.Join(target, "src", "index.js"))).To(Equal("console.log('hello')"))
+			Expect(filecontent(filepath.Join(target, "escaped.txt"))).To(Equal("content"))
+			Expect(filepath.Join(dir, "escaped.txt")).ToNot(BeAnExistingFile())
+		})
+	})
+})
@@ -25,16 +25,25 @@ const (
 	kanikoDefaultImage = "gcr.io/kaniko-project/executor:v1.6.0"
 	kanikoImageEnvVar  = "KANIKO_CONTAINER_IMAGE"
 
-	remoteArtifactsDefaultImage = "quay.io/quay/busybox:latest"
-	remoteArtifactsEnvVar       = "REMOTE_ARTIFACTS_CONTAINER_IMAGE"
-
-	// the Git image is built using ko which can replace environment variable values in the deployment, so once we decide to move
-	// from environment variables to a ConfigMap, then we should move the container template, but retain the environment variable
-	// (or make it an argument like Tekton)
+	// the images of the steps are built using ko which can replace environment variable values in the deployment, so once we decide
+	// to move from environment variables to a ConfigMap, then we should move the container templates, but retain the environment
+	// variables (or make them arguments like Tekton)
 	gitDefaultImage            = "quay.io/shipwright/git:latest"
 	gitImageEnvVar             = "GIT_CONTAINER_IMAGE"
 	gitContainerTemplateEnvVar = "GIT_CONTAINER_TEMPLATE"
 
+	imageProcessingDefaultImage            = "quay.io/shipwright/image-processing:latest"
+	imageProcessingImageEnvVar             = "IMAGE_PROCESSING_CONTAINER_IMAGE"
+	imageProcessingContainerTemplateEnvVar = "IMAGE_PROCESSING_CONTAINER_TEMPLATE"
+
+	httpDefaultImage            = "quay.io/shipwright/http:latest"
+	httpImageEnvVar             = "HTTP_CONTAINER_IMAGE"
+	httpContainerTemplateEnvVar = "HTTP_CONTAINER_TEMPLATE"
+
+	bundleDefaultImage            = "quay.io/shipwright/bundle:latest"
+	bundleImageEnvVar             = "BUNDLE_CONTAINER_IMAGE"
+	bundleContainerTemplateEnvVar = "BUNDLE_CONTAINER_TEMPLATE"
+
 	// environment variable to override the buckets
 	metricBuildRunCompletionDurationBucketsEnvVar = "PROMETHEUS_BR_COMP_DUR_BUCKETS"
 	metricBuildRunEstablishDurationBucketsEnvVar  = "PROMETHEUS_BR_EST_DUR_BUCKETS"
@@ -76,15 +85,17 @@ var (
 // Config hosts different parameters that
 // can be set to use on the Build controllers
 type Config struct {
-	CtxTimeOut                    time.Duration
-	GitContainerTemplate          corev1.Container
-	KanikoContainerImage          string
-	RemoteArtifactsContainerImage string
-	TerminationLogPath            string
-	Prometheus                    PrometheusConfig
-	ManagerOptions                ManagerOptions
-	Controllers                   Controllers
-	KubeAPIOptions                KubeAPIOptions
+	CtxTimeOut                       time.Duration
+	GitContainerTemplate             corev1.Container
+	BundleContainerTemplate          corev1.Container
+	HTTPContainerTemplate            corev1.Container
+	ImageProcessingContainerTemplate corev1.Container
+	KanikoContainerImage             string
+	TerminationLogPath               string
+	Prometheus                       PrometheusConfig
+	ManagerOptions                   ManagerOptions
+	Controllers                      Controllers
+	KubeAPIOptions                   KubeAPIOptions
 }
 
 // PrometheusConfig contains the specific configuration for the
@@ -136,8 +147,37 @@ func NewDefaultConfig() *Config {
 				RunAsGroup: nonRoot,
 			},
 		},
-		KanikoContainerImage:          kanikoDefaultImage,
-		RemoteArtifactsContainerImage: remoteArtifactsDefaultImage,
+		BundleContainerTemplate: corev1.Container{
+			Image: bundleDefaultImage,
+			Command: []string{
+				"/ko-app/bundle",
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:  nonRoot,
+				RunAsGroup: nonRoot,
+			},
+		},
+		HTTPContainerTemplate: corev1.Container{
+			Image: httpDefaultImage,
+			Command: []string{
+				"/ko-app/http",
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:  nonRoot,
+				RunAsGroup: nonRoot,
+			},
+		},
+		ImageProcessingContainerTemplate: corev1.Container{
+			Image: imageProcessingDefaultImage,
+			Command: []string{
+				"/ko-app/image-processing",
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:  nonRoot,
+				RunAsGroup: nonRoot,
+			},
+		},
+		KanikoContainerImage: kanikoDefaultImage,
 		Prometheus: PrometheusConfig{
 			BuildRunCompletionDurationBuckets: metricBuildRunCompletionDurationBuckets,
 			BuildRunEstablishDurationBuckets:  metricBuildRunEstablishDurationBuckets,
@@ -178,27 +218,24 @@ func (c *Config) SetConfigFromEnv() error {
 		c.CtxTimeOut = time.Duration(i) * time.Second
 	}
 
-	if gitContainerTemplate := os.Getenv(gitContainerTemplateEnvVar); gitContainerTemplate != "" {
-		c.GitContainerTemplate = corev1.Container{}
-		if err := json.Unmarshal([]byte(gitContainerTemplate), &c.GitContainerTemplate); err != nil {
-			return err
-		}
-		if c.GitContainerTemplate.Image == "" {
-			c.GitContainerTemplate.Image = gitDefaultImage
-		}
+	if err := loadContainerTemplate(&c.GitContainerTemplate, gitContainerTemplateEnvVar, gitImageEnvVar, gitDefaultImage); err != nil {
+		return err
+	}
+
+	if err := loadContainerTemplate(&c.BundleContainerTemplate, bundleContainerTemplateEnvVar, bundleImageEnvVar, bundleDefaultImage); err != nil {
+		return err
 	}
 
-	// the dedicated environment variable for the image overwrites what is defined in the git container template
-	if gitImage := os.Getenv(gitImageEnvVar); gitImage != "" {
-		c.GitContainerTemplate.Image = gitImage
+	if err := loadContainerTemplate(&c.HTTPContainerTemplate, httpContainerTemplateEnvVar, httpImageEnvVar, httpDefaultImage); err != nil {
+		return err
 	}
 
-	if kanikoImage := os.Getenv(kanikoImageEnvVar); kanikoImage != "" {
-		c.KanikoContainerImage = kanikoImage
+	if err := loadContainerTemplate(&c.ImageProcessingContainerTemplate, imageProcessingContainerTemplateEnvVar, imageProcessingImageEnvVar, imageProcessingDefaultImage); err != nil {
+		return err
 	}
 
-	if remoteArtifactsImage := os.Getenv(remoteArtifactsEnvVar); remoteArtifactsImage != "" {
-		c.RemoteArtifactsContainerImage = remoteArtifactsImage
+	if kanikoImage := os.Getenv(kanikoImageEnvVar); kanikoImage != "" {
+		c.KanikoContainerImage = kanikoImage
 	}
 
 	if err := updateBucketsConfig(&c.Prometheus.BuildRunCompletionDurationBuckets, metricBuildRunCompletionDurationBucketsEnvVar); err != nil {
@@ -260,6 +297,26 @@ func (c *Config) SetConfigFromEnv() error {
 	return nil
 }
 
+// loadContainerTemplate replaces the container with the template of the environment variable, using the default image
+// when the template does not define one. The dedicated environment variable for the image overwrites the template image.
+func loadContainerTemplate(container *corev1.Container, templateEnvVarName string, imageEnvVarName string, defaultImage string) error {
+	if template := os.Getenv(templateEnvVarName); template != "" {
+		*container = corev1.Container{}
+		if err := json.Unmarshal([]byte(template), container); err != nil {
+			return err
+		}
+		if container.Image == "" {
+			container.Image = defaultImage
+		}
+	}
+
+	if image := os.Getenv(imageEnvVarName); image != "" {
+		container.Image = image
+	}
+
+	return nil
+}
+
 func stringToFloat64Array(strings []string) ([]float64, error) {
 	floats := make([]float64, len(strings))
 
@@ -164,6 +164,66 @@ var _ = Describe("Config", func() {
 				}))
 			})
 		})
+
+		It("should allow for an override of the bundle container template and image", func() {
+			var overrides = map[string]string{
+				"BUNDLE_CONTAINER_TEMPLATE": `{"command":["/ko-app/bundle"],"resources":{"requests":{"cpu":"0.5","memory":"128Mi"}}}`,
+				"BUNDLE_CONTAINER_IMAGE":    "myregistry/custom/bundle:override",
+			}
+
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.BundleContainerTemplate).To(Equal(corev1.Container{
+					Image:   "myregistry/custom/bundle:override",
+					Command: []string{"/ko-app/bundle"},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("0.5"),
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				}))
+			})
+		})
+
+		It("should allow for an override of the HTTP container template and image", func() {
+			var overrides = map[string]string{
+				"HTTP_CONTAINER_TEMPLATE": `{"command":["/ko-app/http"],"resources":{"requests":{"cpu":"0.5","memory":"128Mi"}}}`,
+				"HTTP_CONTAINER_IMAGE":    "myregistry/custom/http:override",
+			}
+
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.HTTPContainerTemplate).To(Equal(corev1.Container{
+					Image:   "myregistry/custom/http:override",
+					Command: []string{"/ko-app/http"},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("0.5"),
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				}))
+			})
+		})
+
+		It("should allow for an override of the image processing container template and image", func() {
+			var overrides = map[string]string{
+				"IMAGE_PROCESSING_CONTAINER_TEMPLATE": `{"command":["/ko-app/image-processing"],"resources":{"requests":{"cpu":"0.5","memory":"128Mi"}}}`,
+				"IMAGE_PROCESSING_CONTAINER_IMAGE":    "myregistry/custom/image-processing:override",
+			}
+
+			configWithEnvVariableOverrides(overrides, func(config *Config) {
+				Expect(config.ImageProcessingContainerTemplate).To(Equal(corev1.Container{
+					Image:   "myregistry/custom/image-processing:override",
+					Command: []string{"/ko-app/image-processing"},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("0.5"),
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				}))
+			})
+		})
 	})
 })
 
@@ -19,6 +19,7 @@ import (
 	"github.com/shipwright-io/build/pkg/reconciler/buildrun"
 	"github.com/shipwright-io/build/pkg/reconciler/buildstrategy"
 	"github.com/shipwright-io/build/pkg/reconciler/clusterbuildstrategy"
+	"github.com/shipwright-io/build/pkg/reconciler/retention"
 )
 
 // NewManager add all the controllers to the manager and register the required schemes
@@ -63,5 +64,9 @@ func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, op
 		return nil, err
 	}
 
+	if err := retention.Add(ctx, config, mgr); err != nil {
+		return nil, err
+	}
+
 	return mgr, nil
 }
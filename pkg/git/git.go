@@ -23,6 +23,11 @@ const (
 	gitProtocol   = "ssh"
 )
 
+// CommitVerificationFailedExitCode is the exit code of the Git step for a commit that is not signed
+// with a trusted key, the BuildRun controller maps it to a failure reason. It is below 128, because
+// codes above 128 signal a process that was terminated by a signal.
+const CommitVerificationFailedExitCode = 115
+
 // ValidateGitURLExists validate if a source URL exists or not
 // Note: We have an upcoming PR for the Build Status, where we
 // intend to define a single Status.Reason in the form of 'remoteRepositoryUnreachable',
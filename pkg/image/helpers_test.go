@@ -0,0 +1,120 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+// newRegistry starts an in-process registry, the caller must close it
+func newRegistry() (*httptest.Server, string) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(ioutil.Discard, "", 0))))
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+// layer returns a layer with a tar archive of the given files
+func layer(files map[string]string) v1.Layer {
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+	for path, content := range files {
+		Expect(tarWriter.WriteHeader(&tar.Header{Name: path, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tarWriter.Write([]byte(content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tarWriter.Close()).To(Succeed())
+
+	layer, err := tarball.LayerFromReader(&buffer)
+	Expect(err).ToNot(HaveOccurred())
+	return layer
+}
+
+// newImage returns an image with one layer per set of files
+func newImage(layers ...map[string]string) v1.Image {
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	for _, files := range layers {
+		var err error
+		img, err = mutate.AppendLayers(img, layer(files))
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	configFile, err := img.ConfigFile()
+	Expect(err).ToNot(HaveOccurred())
+	configFile = configFile.DeepCopy()
+	configFile.Architecture = "amd64"
+	configFile.OS = "linux"
+	configFile.Config.Labels = map[string]string{"maintainer": "team-a"}
+	configFile.Config.Cmd = []string{"/bin/sh"}
+
+	img, err = mutate.ConfigFile(img, configFile)
+	Expect(err).ToNot(HaveOccurred())
+	return img
+}
+
+// pushImage pushes an image with one layer that contains the given files
+func pushImage(ref name.Reference, files map[string]string) v1.Image {
+	return pushLayers(ref, files)
+}
+
+// pushLayers pushes an image with one layer per set of files
+func pushLayers(ref name.Reference, layers ...map[string]string) v1.Image {
+	img := newImage(layers...)
+	Expect(remote.Write(ref, img)).To(Succeed())
+	return img
+}
+
+// newIndex returns an image index that references the given images
+func newIndex(images ...v1.Image) v1.ImageIndex {
+	var index v1.ImageIndex = empty.Index
+	for _, img := range images {
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{Add: img})
+	}
+	return index
+}
+
+// pushIndex pushes an image index that references the given images
+func pushIndex(ref name.Reference, images ...v1.Image) v1.ImageIndex {
+	index := newIndex(images...)
+	Expect(remote.WriteIndex(ref, index)).To(Succeed())
+	return index
+}
+
+// digestOf returns the digest of an image or image index
+func digestOf(artifact interface{ Digest() (v1.Hash, error) }) v1.Hash {
+	digest, err := artifact.Digest()
+	Expect(err).ToNot(HaveOccurred())
+	return digest
+}
+
+// get fetches the descriptor of the manifest that the reference points to
+func get(ref name.Reference) *remote.Descriptor {
+	descriptor, err := remote.Get(ref)
+	Expect(err).ToNot(HaveOccurred())
+	return descriptor
+}
+
+// imageConfig returns the configuration of the image that the reference points to
+func imageConfig(ref name.Reference) *v1.ConfigFile {
+	img, err := remote.Image(ref)
+	Expect(err).ToNot(HaveOccurred())
+
+	config, err := img.ConfigFile()
+	Expect(err).ToNot(HaveOccurred())
+	return config
+}
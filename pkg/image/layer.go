@@ -0,0 +1,49 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// dataLayer is a layer of an artifact, like a signature or an SBOM, that stores the data as
+// it is. Other than the layers of container images, it is not a compressed tar archive.
+type dataLayer struct {
+	data      []byte
+	digest    v1.Hash
+	mediaType types.MediaType
+}
+
+var _ v1.Layer = (*dataLayer)(nil)
+
+func newDataLayer(data []byte, mediaType types.MediaType) (*dataLayer, error) {
+	digest, _, err := v1.SHA256(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dataLayer{data: data, digest: digest, mediaType: mediaType}, nil
+}
+
+func (l *dataLayer) Digest() (v1.Hash, error) { return l.digest, nil }
+
+func (l *dataLayer) DiffID() (v1.Hash, error) { return l.digest, nil }
+
+func (l *dataLayer) Compressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.data)), nil
+}
+
+func (l *dataLayer) Uncompressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.data)), nil
+}
+
+func (l *dataLayer) Size() (int64, error) { return int64(len(l.data)), nil }
+
+func (l *dataLayer) MediaType() (types.MediaType, error) { return l.mediaType, nil }
@@ -0,0 +1,231 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// MutateImage sets the labels in the configuration and the annotations in the manifest of
+// the image that the reference points to, pushes the result to the same reference and returns
+// the digest of the mutated image. For an image index, the labels and annotations are set on
+// all images of the index, the annotations are also set on the index itself.
+func MutateImage(ref name.Reference, labels map[string]string, annotations map[string]string, options ...remote.Option) (v1.Hash, error) {
+	descriptor, err := remote.Get(ref, options...)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	if len(labels) == 0 && len(annotations) == 0 {
+		return descriptor.Digest, nil
+	}
+
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return v1.Hash{}, err
+		}
+
+		mutated, err := mutateIndex(index, labels, annotations)
+		if err != nil {
+			return v1.Hash{}, err
+		}
+
+		digest, err := mutated.Digest()
+		if err != nil {
+			return v1.Hash{}, err
+		}
+
+		return digest, remote.WriteIndex(pushReference(ref, digest), mutated, options...)
+	}
+
+	image, err := descriptor.Image()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	mutated, err := mutateImage(image, labels, annotations)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	digest, err := mutated.Digest()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	return digest, remote.Write(pushReference(ref, digest), mutated, options...)
+}
+
+// pushReference returns the reference to push the mutated image to, a digest
+// reference cannot point to it, which is why it is pushed by its new digest
+func pushReference(ref name.Reference, digest v1.Hash) name.Reference {
+	if _, isDigest := ref.(name.Digest); isDigest {
+		return ref.Context().Digest(digest.String())
+	}
+	return ref
+}
+
+func mutateImage(image v1.Image, labels map[string]string, annotations map[string]string) (v1.Image, error) {
+	if len(labels) > 0 {
+		configFile, err := image.ConfigFile()
+		if err != nil {
+			return nil, err
+		}
+
+		configFile = configFile.DeepCopy()
+		configFile.Config.Labels = mergeStrings(configFile.Config.Labels, labels)
+
+		if image, err = mutate.ConfigFile(image, configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(annotations) > 0 {
+		image = &annotatedImage{Image: image, annotations: annotations}
+	}
+
+	return image, nil
+}
+
+func mutateIndex(index v1.ImageIndex, labels map[string]string, annotations map[string]string) (v1.ImageIndex, error) {
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, err := index.MediaType()
+	if err != nil {
+		return nil, err
+	}
+
+	mutated := mutate.IndexMediaType(empty.Index, mediaType)
+	for _, descriptor := range indexManifest.Manifests {
+		var child mutate.Appendable
+
+		if descriptor.MediaType.IsIndex() {
+			childIndex, err := index.ImageIndex(descriptor.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			if child, err = mutateIndex(childIndex, labels, annotations); err != nil {
+				return nil, err
+			}
+		} else {
+			childImage, err := index.Image(descriptor.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			if child, err = mutateImage(childImage, labels, annotations); err != nil {
+				return nil, err
+			}
+		}
+
+		mutated = mutate.AppendManifests(mutated, mutate.IndexAddendum{
+			Add: child,
+			Descriptor: v1.Descriptor{
+				URLs:        descriptor.URLs,
+				Annotations: descriptor.Annotations,
+				Platform:    descriptor.Platform,
+			},
+		})
+	}
+
+	// the annotations of the index itself are not retained by the mutate package
+	if indexAnnotations := mergeStrings(indexManifest.Annotations, annotations); len(indexAnnotations) > 0 {
+		mutated = &annotatedIndex{index: mutated, annotations: indexAnnotations}
+	}
+
+	return mutated, nil
+}
+
+// mergeStrings returns a copy of the existing values, in which the given values are set
+func mergeStrings(existing map[string]string, values map[string]string) map[string]string {
+	if len(existing) == 0 && len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(existing)+len(values))
+	for key, value := range existing {
+		result[key] = value
+	}
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+// annotatedImage is an image with additional annotations in its manifest
+type annotatedImage struct {
+	v1.Image
+	annotations map[string]string
+}
+
+func (i *annotatedImage) Manifest() (*v1.Manifest, error) {
+	manifest, err := i.Image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest = manifest.DeepCopy()
+	manifest.Annotations = mergeStrings(manifest.Annotations, i.annotations)
+	return manifest, nil
+}
+
+func (i *annotatedImage) RawManifest() ([]byte, error) { return partial.RawManifest(i) }
+
+func (i *annotatedImage) Digest() (v1.Hash, error) { return partial.Digest(i) }
+
+func (i *annotatedImage) Size() (int64, error) { return partial.Size(i) }
+
+// annotatedIndex is an image index with additional annotations in its manifest, it cannot
+// embed the index because of the name collision with its ImageIndex method
+type annotatedIndex struct {
+	index       v1.ImageIndex
+	annotations map[string]string
+}
+
+var _ v1.ImageIndex = (*annotatedIndex)(nil)
+
+func (i *annotatedIndex) MediaType() (types.MediaType, error) { return i.index.MediaType() }
+
+func (i *annotatedIndex) IndexManifest() (*v1.IndexManifest, error) {
+	indexManifest, err := i.index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	indexManifest = indexManifest.DeepCopy()
+	indexManifest.Annotations = mergeStrings(indexManifest.Annotations, i.annotations)
+	return indexManifest, nil
+}
+
+func (i *annotatedIndex) RawManifest() ([]byte, error) {
+	indexManifest, err := i.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(indexManifest)
+}
+
+func (i *annotatedIndex) Digest() (v1.Hash, error) { return partial.Digest(i) }
+
+func (i *annotatedIndex) Size() (int64, error) { return partial.Size(i) }
+
+func (i *annotatedIndex) Image(digest v1.Hash) (v1.Image, error) { return i.index.Image(digest) }
+
+func (i *annotatedIndex) ImageIndex(digest v1.Hash) (v1.ImageIndex, error) {
+	return i.index.ImageIndex(digest)
+}
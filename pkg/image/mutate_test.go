@@ -0,0 +1,114 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("MutateImage", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	BeforeEach(func() {
+		var host string
+		server, host = newRegistry()
+
+		var err error
+		ref, err = name.ParseReference(host + "/shipwright/sample:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sets the labels and annotations of an image and returns its new digest", func() {
+		original := pushImage(ref, map[string]string{"app/main.go": "package main"})
+
+		digest, err := image.MutateImage(ref,
+			map[string]string{"org.opencontainers.image.source": "https://github.com/shipwright-io/sample-go"},
+			map[string]string{"org.opencontainers.image.vendor": "shipwright"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).ToNot(Equal(digestOf(original)))
+
+		mutated := get(ref)
+		Expect(mutated.Digest).To(Equal(digest))
+
+		manifest, err := v1.ParseManifest(bytes.NewReader(mutated.Manifest))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Annotations).To(Equal(map[string]string{"org.opencontainers.image.vendor": "shipwright"}))
+
+		config := imageConfig(ref)
+		Expect(config.Config.Labels).To(Equal(map[string]string{
+			"maintainer":                      "team-a",
+			"org.opencontainers.image.source": "https://github.com/shipwright-io/sample-go",
+		}))
+		Expect(config.Config.Cmd).To(Equal([]string{"/bin/sh"}))
+	})
+
+	It("sets the labels and annotations on all images of an index", func() {
+		first := newImage(map[string]string{"amd64": "binary"})
+		second := newImage(map[string]string{"arm64": "binary"})
+		pushIndex(ref, first, second)
+
+		digest, err := image.MutateImage(ref,
+			map[string]string{"team": "a"},
+			map[string]string{"release": "stable"})
+		Expect(err).ToNot(HaveOccurred())
+
+		mutated := get(ref)
+		Expect(mutated.Digest).To(Equal(digest))
+		Expect(mutated.MediaType.IsIndex()).To(BeTrue())
+
+		index, err := v1.ParseIndexManifest(bytes.NewReader(mutated.Manifest))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(index.Annotations).To(Equal(map[string]string{"release": "stable"}))
+		Expect(index.Manifests).To(HaveLen(2))
+
+		for _, descriptor := range index.Manifests {
+			Expect(descriptor.Digest).ToNot(BeElementOf(digestOf(first), digestOf(second)))
+
+			childRef := ref.Context().Digest(descriptor.Digest.String())
+			Expect(imageConfig(childRef).Config.Labels).To(HaveKeyWithValue("team", "a"))
+
+			manifest, err := v1.ParseManifest(bytes.NewReader(get(childRef).Manifest))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest.Annotations).To(Equal(map[string]string{"release": "stable"}))
+		}
+	})
+
+	It("pushes an image that is referenced by digest by its new digest", func() {
+		original := pushImage(ref, map[string]string{"app/main.go": "package main"})
+
+		digest, err := image.MutateImage(ref.Context().Digest(digestOf(original).String()), map[string]string{"team": "a"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(imageConfig(ref.Context().Digest(digest.String())).Config.Labels).To(HaveKeyWithValue("team", "a"))
+	})
+
+	It("does not push the image when there are no labels and annotations", func() {
+		original := pushImage(ref, map[string]string{"app/main.go": "package main"})
+
+		digest, err := image.MutateImage(ref, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(digestOf(original)))
+	})
+
+	It("fails for an image that does not exist", func() {
+		_, err := image.MutateImage(ref, map[string]string{"team": "a"}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,98 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// GetOptions returns the options to access registries in the context of the caller, with
+// the credentials of the keychain that LoadDockerConfigKeychain returns for the file
+func GetOptions(ctx context.Context, dockerConfigFile string) ([]remote.Option, error) {
+	keychain, err := LoadDockerConfigKeychain(dockerConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(keychain),
+	}, nil
+}
+
+// LoadDockerConfigKeychain returns a keychain with the credentials of the docker config file,
+// like the one of a secret of type kubernetes.io/dockerconfigjson. Registries that the file
+// has no credentials for, or all registries if the file is empty or does not exist, fall back
+// to the default docker config of the user.
+func LoadDockerConfigKeychain(dockerConfigFile string) (authn.Keychain, error) {
+	if dockerConfigFile == "" {
+		return authn.DefaultKeychain, nil
+	}
+
+	file, err := os.Open(dockerConfigFile)
+	if os.IsNotExist(err) {
+		return authn.DefaultKeychain, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	configFile, err := config.LoadFromReader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return authn.NewMultiKeychain(&dockerConfigKeychain{configFile: configFile}, authn.DefaultKeychain), nil
+}
+
+// IsNotFound returns whether the error is the response of a registry to a request
+// for a manifest or a blob that does not exist
+func IsNotFound(err error) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound
+}
+
+// dockerConfigKeychain resolves the credentials of a registry from a docker config file
+type dockerConfigKeychain struct {
+	configFile *configfile.ConfigFile
+}
+
+// Resolve implements authn.Keychain
+func (k *dockerConfigKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	// like in the default keychain, the credentials of Docker Hub are stored under its legacy URL
+	key := target.RegistryStr()
+	if key == name.DefaultRegistry {
+		key = authn.DefaultAuthKey
+	}
+
+	authConfig, err := k.configFile.GetAuthConfig(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if authConfig == (types.AuthConfig{}) {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      authConfig.Username,
+		Password:      authConfig.Password,
+		Auth:          authConfig.Auth,
+		IdentityToken: authConfig.IdentityToken,
+		RegistryToken: authConfig.RegistryToken,
+	}), nil
+}
@@ -0,0 +1,92 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("LoadDockerConfigKeychain", func() {
+	var (
+		dir                 string
+		dockerConfig        string
+		dockerConfigDefined bool
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir(os.TempDir(), "docker-config")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(dir, "home"), 0755)).To(Succeed())
+
+		// the default keychain reads the config.json file in the DOCKER_CONFIG directory
+		dockerConfig, dockerConfigDefined = os.LookupEnv("DOCKER_CONFIG")
+		Expect(os.Setenv("DOCKER_CONFIG", filepath.Join(dir, "home"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if dockerConfigDefined {
+			os.Setenv("DOCKER_CONFIG", dockerConfig)
+		} else {
+			os.Unsetenv("DOCKER_CONFIG")
+		}
+		os.RemoveAll(dir)
+	})
+
+	var file = func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	var authorization = func(keychain authn.Keychain, registry string) *authn.AuthConfig {
+		target, err := name.NewRegistry(registry)
+		Expect(err).ToNot(HaveOccurred())
+
+		authenticator, err := keychain.Resolve(target)
+		Expect(err).ToNot(HaveOccurred())
+
+		authConfig, err := authenticator.Authorization()
+		Expect(err).ToNot(HaveOccurred())
+		return authConfig
+	}
+
+	It("reads the credentials of the file, with the default docker config as fallback", func() {
+		file("home/config.json", `{"auths":{"docker.io":{"username":"other","password":"secret"},"ghcr.io":{"username":"octocat","password":"token"}}}`)
+
+		keychain, err := image.LoadDockerConfigKeychain(
+			file(".dockerconfigjson", `{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNzd29yZA=="},"quay.io":{"username":"robot","identitytoken":"token"}}}`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(authorization(keychain, "docker.io")).To(Equal(&authn.AuthConfig{Username: "user", Password: "password"}))
+		Expect(authorization(keychain, "quay.io")).To(Equal(&authn.AuthConfig{Username: "robot", IdentityToken: "token"}))
+		Expect(authorization(keychain, "ghcr.io")).To(Equal(&authn.AuthConfig{Username: "octocat", Password: "token"}))
+		Expect(authorization(keychain, "registry.example.com")).To(Equal(&authn.AuthConfig{}))
+	})
+
+	It("uses the default docker config if the file does not exist", func() {
+		file("home/config.json", `{"auths":{"ghcr.io":{"username":"octocat","password":"token"}}}`)
+
+		keychain, err := image.LoadDockerConfigKeychain(filepath.Join(dir, "missing.json"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(authorization(keychain, "ghcr.io")).To(Equal(&authn.AuthConfig{Username: "octocat", Password: "token"}))
+	})
+
+	It("fails for credentials in an invalid format", func() {
+		_, err := image.LoadDockerConfigKeychain(file("config.json", `{"auths":{"quay.io":{"auth":"bm8tY29sb24="}}}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,98 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// referrerImage is an artifact that refers to the image of the subject, the v1 package
+// does not know the artifactType and subject fields of a manifest yet
+type referrerImage struct {
+	v1.Image
+	artifactType string
+	subject      v1.Descriptor
+}
+
+func (i *referrerImage) RawManifest() ([]byte, error) {
+	manifest, err := i.Image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		*v1.Manifest
+		ArtifactType string         `json:"artifactType"`
+		Subject      *v1.Descriptor `json:"subject"`
+	}{manifest, i.artifactType, &i.subject})
+}
+
+func (i *referrerImage) Digest() (v1.Hash, error) { return partial.Digest(i) }
+
+func (i *referrerImage) Size() (int64, error) { return partial.Size(i) }
+
+// referrerDescriptor is the descriptor of an artifact in the index of the referrers of an image
+type referrerDescriptor struct {
+	v1.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// referrersIndex is the image index that lists the artifacts that refer to an image
+type referrersIndex struct {
+	SchemaVersion int64                `json:"schemaVersion"`
+	MediaType     types.MediaType      `json:"mediaType"`
+	Manifests     []referrerDescriptor `json:"manifests"`
+}
+
+// rawManifest is a manifest that is pushed as it is, with remote.Tag
+type rawManifest struct {
+	mediaType types.MediaType
+	raw       []byte
+}
+
+func (m *rawManifest) RawManifest() ([]byte, error) { return m.raw, nil }
+
+func (m *rawManifest) MediaType() (types.MediaType, error) { return m.mediaType, nil }
+
+// addReferrer adds the artifact to the referrers index that is stored under the sha256-<hex>
+// tag of the subject. Not all registries support to query the referrers of an image, which
+// is why the index is maintained following the fallback of the OCI distribution specification.
+func addReferrer(subject name.Digest, artifact *referrerImage, options ...remote.Option) error {
+	tag := digestTag(subject, "")
+	index := referrersIndex{SchemaVersion: 2, MediaType: types.OCIImageIndex}
+
+	existing, err := remote.Get(tag, options...)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(existing.Manifest, &index); err != nil {
+			return err
+		}
+	case !IsNotFound(err):
+		return err
+	}
+
+	descriptor, err := partial.Descriptor(artifact)
+	if err != nil {
+		return err
+	}
+
+	index.Manifests = append(index.Manifests, referrerDescriptor{
+		Descriptor:   *descriptor,
+		ArtifactType: artifact.artifactType,
+	})
+
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	return remote.Tag(tag, &rawManifest{mediaType: index.MediaType, raw: raw}, options...)
+}
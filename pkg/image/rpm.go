@@ -0,0 +1,315 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+)
+
+// rpmDatabaseFiles are the files of the rpm database in the Berkeley DB, NDB and SQLite formats,
+// in the default and the newer sysimage location
+var rpmDatabaseFiles = []string{
+	"var/lib/rpm/Packages",
+	"var/lib/rpm/Packages.db",
+	"var/lib/rpm/rpmdb.sqlite",
+	"usr/lib/sysimage/rpm/Packages",
+	"usr/lib/sysimage/rpm/Packages.db",
+	"usr/lib/sysimage/rpm/rpmdb.sqlite",
+}
+
+// the tags and types of the rpm header that are read
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+	rpmTagLicense = 1014
+	rpmTagArch    = 1022
+
+	rpmTypeInt32      = 4
+	rpmTypeString     = 6
+	rpmTypeI18NString = 9
+)
+
+func isRPMDatabase(filename string) bool {
+	for _, rpmDatabaseFile := range rpmDatabaseFiles {
+		if filename == rpmDatabaseFile {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRPMDatabase returns the installed packages of the rpm database, which is a Berkeley DB
+// hash database, an NDB database or an SQLite database depending on its file name
+func parseRPMDatabase(filename string, data []byte, distro string, distroVersion string) ([]Package, error) {
+	var headers [][]byte
+	var err error
+
+	switch path.Base(filename) {
+	case "Packages":
+		headers, err = readBerkeleyDB(data)
+	case "Packages.db":
+		headers, err = readNDB(data)
+	default:
+		headers, err = readSQLiteTable(data, "Packages", 1)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the rpm database /%s: %w", filename, err)
+	}
+
+	var packages []Package
+	for _, header := range headers {
+		tags, err := parseRPMHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read a package of the rpm database /%s: %w", filename, err)
+		}
+
+		// the public keys that rpm trusts are stored as packages
+		if tags[rpmTagName] == "" || tags[rpmTagName] == "gpg-pubkey" {
+			continue
+		}
+
+		version := tags[rpmTagVersion]
+		if tags[rpmTagRelease] != "" {
+			version += "-" + tags[rpmTagRelease]
+		}
+
+		var qualifiers map[string]string
+		if epoch := tags[rpmTagEpoch]; epoch != "" {
+			qualifiers = map[string]string{"epoch": epoch}
+		}
+
+		pkg := newPackage("rpm", distro, distroVersion, tags[rpmTagName], version, tags[rpmTagArch], tags[rpmTagLicense], qualifiers)
+		if qualifiers != nil {
+			pkg.Version = qualifiers["epoch"] + ":" + version
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// parseRPMHeader returns the values of the tags of an rpm header that are read. The header
+// starts with the number of index entries and the size of the data store, followed by the
+// index entries of 16 bytes each and the data store, all in big endian.
+func parseRPMHeader(data []byte) (map[int]string, error) {
+	if len(data) < 8 {
+		return nil, errors.New("the header is too short")
+	}
+
+	indexCount := int(binary.BigEndian.Uint32(data[0:4]))
+	storeSize := int(binary.BigEndian.Uint32(data[4:8]))
+	storeOffset := 8 + 16*indexCount
+	if indexCount < 0 || storeSize < 0 || storeOffset < 0 || storeOffset+storeSize > len(data) || storeOffset+storeSize < storeOffset {
+		return nil, errors.New("the header is truncated")
+	}
+	store := data[storeOffset : storeOffset+storeSize]
+
+	tags := map[int]string{}
+	for i := 0; i < indexCount; i++ {
+		entry := data[8+16*i : 8+16*(i+1)]
+		tag := int(binary.BigEndian.Uint32(entry[0:4]))
+		dataType := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(binary.BigEndian.Uint32(entry[8:12]))
+
+		switch tag {
+		case rpmTagName, rpmTagVersion, rpmTagRelease, rpmTagEpoch, rpmTagLicense, rpmTagArch:
+		default:
+			continue
+		}
+
+		if offset < 0 || offset >= len(store) {
+			return nil, fmt.Errorf("the data of tag %d is out of bounds", tag)
+		}
+
+		switch dataType {
+		case rpmTypeString, rpmTypeI18NString:
+			value := store[offset:]
+			if end := bytes.IndexByte(value, 0); end >= 0 {
+				value = value[:end]
+			}
+			tags[tag] = string(value)
+
+		case rpmTypeInt32:
+			if offset+4 > len(store) {
+				return nil, fmt.Errorf("the data of tag %d is out of bounds", tag)
+			}
+			tags[tag] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(store[offset:offset+4])), 10)
+		}
+	}
+
+	return tags, nil
+}
+
+// readNDB returns the blobs of the packages of an rpm NDB database. The file starts with pages
+// of slots that locate the blobs, each blob is stored in blocks of 16 bytes. All numbers are
+// in little endian.
+func readNDB(data []byte) ([][]byte, error) {
+	const (
+		headerMagic  = 'R' | 'p'<<8 | 'm'<<16 | 'P'<<24
+		slotMagic    = 'S' | 'l'<<8 | 'o'<<16 | 't'<<24
+		blobMagic    = 'B' | 'l'<<8 | 'b'<<16 | 'S'<<24
+		headerSize   = 32
+		slotSize     = 16
+		slotPageSize = 4096
+		blockSize    = 16
+		blobHeadSize = 16
+	)
+
+	if len(data) < headerSize || binary.LittleEndian.Uint32(data[0:4]) != headerMagic {
+		return nil, errors.New("the file is not an NDB database")
+	}
+
+	// the header takes the space of two slots
+	slotPages := int(binary.LittleEndian.Uint32(data[12:16]))
+	slotCount := slotPages*slotPageSize/slotSize - 2
+	if slotPages <= 0 || headerSize+slotCount*slotSize > len(data) {
+		return nil, errors.New("the slots are out of bounds")
+	}
+
+	var blobs [][]byte
+	for i := 0; i < slotCount; i++ {
+		slot := data[headerSize+i*slotSize : headerSize+(i+1)*slotSize]
+		if binary.LittleEndian.Uint32(slot[0:4]) != slotMagic {
+			return nil, fmt.Errorf("slot %d is invalid", i)
+		}
+
+		packageIndex := binary.LittleEndian.Uint32(slot[4:8])
+		if packageIndex == 0 {
+			continue
+		}
+
+		offset := int(binary.LittleEndian.Uint32(slot[8:12])) * blockSize
+		if offset < 0 || offset+blobHeadSize > len(data) {
+			return nil, fmt.Errorf("the blob of package %d is out of bounds", packageIndex)
+		}
+
+		blob := data[offset:]
+		if binary.LittleEndian.Uint32(blob[0:4]) != blobMagic || binary.LittleEndian.Uint32(blob[4:8]) != packageIndex {
+			return nil, fmt.Errorf("the blob of package %d is invalid", packageIndex)
+		}
+
+		length := int(binary.LittleEndian.Uint32(blob[12:16]))
+		if length < 0 || blobHeadSize+length > len(blob) {
+			return nil, fmt.Errorf("the blob of package %d is out of bounds", packageIndex)
+		}
+
+		blobs = append(blobs, blob[blobHeadSize:blobHeadSize+length])
+	}
+
+	return blobs, nil
+}
+
+// readBerkeleyDB returns the values of a Berkeley DB hash database. Each package of rpm is larger
+// than a page, therefore it is stored on a chain of overflow pages that a hash page references.
+// The byte order of the numbers is the one of the machine that wrote the database.
+func readBerkeleyDB(data []byte) ([][]byte, error) {
+	const (
+		hashMagic        = 0x061561
+		pageHeaderSize   = 26
+		pageTypeHash     = 13
+		pageTypeUnsorted = 2
+		pageTypeOverflow = 7
+		itemTypeOffPage  = 3
+	)
+
+	if len(data) < 72 {
+		return nil, errors.New("the file is not a Berkeley DB database")
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(data[12:16]) != hashMagic {
+		order = binary.BigEndian
+		if order.Uint32(data[12:16]) != hashMagic {
+			return nil, errors.New("the file is not a Berkeley DB hash database")
+		}
+	}
+
+	if data[24] != 0 {
+		return nil, errors.New("the database is encrypted")
+	}
+
+	pageSize := int(order.Uint32(data[20:24]))
+	if pageSize < 512 || len(data)%pageSize != 0 {
+		return nil, fmt.Errorf("the page size %d is invalid", pageSize)
+	}
+	pageCount := len(data) / pageSize
+
+	page := func(number int) []byte {
+		return data[number*pageSize : (number+1)*pageSize]
+	}
+
+	var values [][]byte
+	for number := 1; number < pageCount; number++ {
+		hashPage := page(number)
+		if pageType := hashPage[25]; pageType != pageTypeHash && pageType != pageTypeUnsorted {
+			continue
+		}
+
+		// the entries are pairs of a key and a value, the package number is the key
+		entries := int(order.Uint16(hashPage[20:22]))
+		if pageHeaderSize+2*entries > pageSize {
+			return nil, fmt.Errorf("the entries of page %d are out of bounds", number)
+		}
+
+		for i := 1; i < entries; i += 2 {
+			offset := int(order.Uint16(hashPage[pageHeaderSize+2*i:]))
+			if offset >= pageSize {
+				return nil, fmt.Errorf("entry %d of page %d is out of bounds", i, number)
+			}
+
+			// small values are stored on the hash page, these are no packages
+			item := hashPage[offset:]
+			if item[0] != itemTypeOffPage {
+				continue
+			}
+			if len(item) < 12 {
+				return nil, fmt.Errorf("entry %d of page %d is out of bounds", i, number)
+			}
+
+			next := int(order.Uint32(item[4:8]))
+			length := int(order.Uint32(item[8:12]))
+
+			var value []byte
+			for visited := 0; next != 0 && len(value) < length; visited++ {
+				if next >= pageCount || visited >= pageCount {
+					return nil, fmt.Errorf("the overflow page %d is out of bounds", next)
+				}
+
+				overflowPage := page(next)
+				if overflowPage[25] != pageTypeOverflow {
+					return nil, fmt.Errorf("page %d is not an overflow page", next)
+				}
+
+				// only the last page of the chain states the size of its data
+				content := overflowPage[pageHeaderSize:]
+				next = int(order.Uint32(overflowPage[16:20]))
+				if next == 0 {
+					size := int(order.Uint16(overflowPage[22:24]))
+					if size > len(content) {
+						return nil, errors.New("the data of the last overflow page is out of bounds")
+					}
+					content = content[:size]
+				}
+
+				value = append(value, content...)
+			}
+
+			if len(value) < length {
+				return nil, fmt.Errorf("the value of entry %d of page %d is truncated", i, number)
+			}
+
+			values = append(values, value[:length])
+		}
+	}
+
+	return values, nil
+}
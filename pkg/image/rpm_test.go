@@ -0,0 +1,242 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+const rhelOSRelease = "ID=\"rhel\"\nVERSION_ID=\"9.2\"\n"
+
+// rpmHeader returns the header of an rpm package as the rpm database stores it, with the
+// string tags and the epoch
+func rpmHeader(epoch uint32, tags map[uint32]string) []byte {
+	var index, store []byte
+	entry := func(tag uint32, dataType uint32) {
+		for _, value := range []uint32{tag, dataType, uint32(len(store)), 1} {
+			index = appendUint32(index, value)
+		}
+	}
+
+	if epoch > 0 {
+		entry(1003, 4)
+		store = appendUint32(store, epoch)
+	}
+
+	var keys []int
+	for tag := range tags {
+		keys = append(keys, int(tag))
+	}
+	sort.Ints(keys)
+	for _, tag := range keys {
+		entry(uint32(tag), 6)
+		store = append(store, tags[uint32(tag)]+"\x00"...)
+	}
+
+	header := appendUint32(appendUint32(nil, uint32(len(index)/16)), uint32(len(store)))
+	return append(append(header, index...), store...)
+}
+
+func appendUint32(data []byte, value uint32) []byte {
+	var number [4]byte
+	binary.BigEndian.PutUint32(number[:], value)
+	return append(data, number[:]...)
+}
+
+func rpmPackage(name string, version string, release string, arch string, license string) []byte {
+	return rpmHeader(0, map[uint32]string{1000: name, 1001: version, 1002: release, 1014: license, 1022: arch})
+}
+
+// ndbDatabase returns an rpm NDB database with one page of slots, followed by the blobs
+func ndbDatabase(headers ...[]byte) string {
+	const slotPageSize, blockSize = 4096, 16
+
+	database := make([]byte, slotPageSize)
+	put := func(offset int, values ...uint32) {
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(database[offset+4*i:], value)
+		}
+	}
+
+	put(0, 'R'|'p'<<8|'m'<<16|'P'<<24, 0, 1, 1, uint32(len(headers)+1))
+	for offset := 32; offset < slotPageSize; offset += 16 {
+		put(offset, 'S'|'l'<<8|'o'<<16|'t'<<24)
+	}
+
+	for i, header := range headers {
+		offset := len(database)
+		blocks := (16 + len(header) + 12 + blockSize - 1) / blockSize
+		database = append(database, make([]byte, blocks*blockSize)...)
+
+		put(offset, 'B'|'l'<<8|'b'<<16|'S'<<24, uint32(i+1), 0, uint32(len(header)))
+		copy(database[offset+16:], header)
+		put(offset+16+len(header), 0, uint32(len(header)), 'A'|'l'<<8|'d'<<16|'B'<<24)
+
+		put(32+16*i, 'S'|'l'<<8|'o'<<16|'t'<<24, uint32(i+1), uint32(offset/blockSize), uint32(blocks))
+	}
+
+	return string(database)
+}
+
+// berkeleyDatabase returns an rpm Berkeley DB hash database with one hash page, which contains
+// the record of the next package number and the packages, that are stored on chains of overflow
+// pages
+func berkeleyDatabase(headers ...[]byte) string {
+	const pageSize, pageHeaderSize = 512, 26
+
+	var overflowPages [][]byte
+	var firstPages []int
+	for _, header := range headers {
+		firstPages = append(firstPages, 2+len(overflowPages))
+		for offset := 0; offset < len(header); offset += pageSize - pageHeaderSize {
+			page := make([]byte, pageSize)
+			end := offset + pageSize - pageHeaderSize
+			if end >= len(header) {
+				end = len(header)
+			} else {
+				binary.LittleEndian.PutUint32(page[16:], uint32(2+len(overflowPages)+1))
+			}
+			binary.LittleEndian.PutUint16(page[22:], uint16(end-offset))
+			page[25] = 7
+			copy(page[pageHeaderSize:], header[offset:end])
+			overflowPages = append(overflowPages, page)
+		}
+	}
+
+	metadata := make([]byte, pageSize)
+	binary.LittleEndian.PutUint32(metadata[12:], 0x061561)
+	binary.LittleEndian.PutUint32(metadata[16:], 9)
+	binary.LittleEndian.PutUint32(metadata[20:], pageSize)
+	metadata[25] = 8
+	binary.LittleEndian.PutUint32(metadata[32:], uint32(1+len(overflowPages)))
+
+	// the items of the hash page are stored from its end, the keys are the package numbers
+	hashPage := make([]byte, pageSize)
+	hashPage[25] = 13
+	end := pageSize
+	var offsets []uint16
+	addItem := func(item []byte) {
+		end -= len(item)
+		copy(hashPage[end:], item)
+		offsets = append(offsets, uint16(end))
+	}
+
+	addItem([]byte{1, 0, 0, 0, 0})
+	addItem([]byte{1, byte(len(headers) + 1), 0, 0, 0})
+	for i, header := range headers {
+		addItem([]byte{1, byte(i + 1), 0, 0, 0})
+		item := make([]byte, 12)
+		item[0] = 3
+		binary.LittleEndian.PutUint32(item[4:], uint32(firstPages[i]))
+		binary.LittleEndian.PutUint32(item[8:], uint32(len(header)))
+		addItem(item)
+	}
+
+	binary.LittleEndian.PutUint16(hashPage[20:], uint16(len(offsets)))
+	binary.LittleEndian.PutUint16(hashPage[22:], uint16(end))
+	for i, offset := range offsets {
+		binary.LittleEndian.PutUint16(hashPage[pageHeaderSize+2*i:], offset)
+	}
+
+	database := append(metadata, hashPage...)
+	for _, page := range overflowPages {
+		database = append(database, page...)
+	}
+	return string(database)
+}
+
+var _ = Describe("rpm databases", func() {
+	var (
+		bash    = rpmPackage("bash", "5.1.8", "6.el9_1", "x86_64", "GPLv3+")
+		tzdata  = rpmPackage("tzdata", "2023c", "1.el9", "noarch", "Public Domain")
+		openssl = rpmHeader(1, map[uint32]string{1000: "openssl-libs", 1001: "3.0.7", 1002: "6.el9_2", 1005: strings.Repeat("A toolkit for TLS. ", 100), 1014: "ASL 2.0", 1022: "x86_64"})
+		pubkey  = rpmPackage("gpg-pubkey", "fd431d51", "4ae0493b", "", "pubkey")
+
+		expected = []image.Package{
+			{Name: "bash", Version: "5.1.8-6.el9_1", Architecture: "x86_64", License: "GPLv3+", PURL: "pkg:rpm/rhel/bash@5.1.8-6.el9_1?arch=x86_64&distro=rhel-9.2"},
+			{Name: "openssl-libs", Version: "1:3.0.7-6.el9_2", Architecture: "x86_64", License: "ASL 2.0", PURL: "pkg:rpm/rhel/openssl-libs@3.0.7-6.el9_2?arch=x86_64&distro=rhel-9.2&epoch=1"},
+			{Name: "tzdata", Version: "2023c-1.el9", Architecture: "noarch", License: "Public Domain", PURL: "pkg:rpm/rhel/tzdata@2023c-1.el9?arch=noarch&distro=rhel-9.2"},
+		}
+	)
+
+	var packages = func(files map[string]string) []image.Package {
+		packages, complete, err := image.Packages(newImage(files))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(complete).To(BeTrue())
+		return packages
+	}
+
+	It("lists the packages of an SQLite database", func() {
+		// the packages of the rpm files that rpmbuild created, in a database with small pages
+		// to store them on overflow pages, and more rows than a page holds
+		database, err := ioutil.ReadFile("testdata/rpmdb.sqlite")
+		Expect(err).ToNot(HaveOccurred())
+
+		sqlitePackages := packages(map[string]string{
+			"etc/os-release":           rhelOSRelease,
+			"var/lib/rpm/rpmdb.sqlite": string(database),
+		})
+
+		Expect(sqlitePackages).To(HaveLen(11))
+		Expect(sqlitePackages).To(ContainElement(image.Package{
+			Name:         "one-epoch",
+			Version:      "1:0.1-1",
+			Architecture: "x86_64",
+			License:      "Public Domain",
+			PURL:         "pkg:rpm/rhel/one-epoch@0.1-1?arch=x86_64&distro=rhel-9.2&epoch=1",
+		}))
+		Expect(sqlitePackages).To(ContainElement(image.Package{
+			Name:         "simple",
+			Version:      "1.0.1-1",
+			Architecture: "i386",
+			License:      "something",
+			PURL:         "pkg:rpm/rhel/simple@1.0.1-1?arch=i386&distro=rhel-9.2",
+		}))
+	})
+
+	It("lists the packages of an NDB database", func() {
+		Expect(packages(map[string]string{
+			"etc/os-release":                   rhelOSRelease,
+			"usr/lib/sysimage/rpm/Packages.db": ndbDatabase(bash, pubkey, openssl, tzdata),
+		})).To(Equal(expected))
+	})
+
+	It("lists the packages of a Berkeley DB database", func() {
+		Expect(packages(map[string]string{
+			"etc/os-release":       rhelOSRelease,
+			"var/lib/rpm/Packages": berkeleyDatabase(bash, pubkey, openssl, tzdata),
+		})).To(Equal(expected))
+	})
+
+	It("uses the namespace redhat without an os-release file", func() {
+		Expect(packages(map[string]string{
+			"var/lib/rpm/Packages.db": ndbDatabase(tzdata),
+		})).To(Equal([]image.Package{
+			{Name: "tzdata", Version: "2023c-1.el9", Architecture: "noarch", License: "Public Domain", PURL: "pkg:rpm/redhat/tzdata@2023c-1.el9?arch=noarch"},
+		}))
+	})
+
+	It("reports that an image with a corrupt database is not complete, and lists the other packages", func() {
+		corrupt := ndbDatabase(bash, tzdata)
+		corrupt = corrupt[:len(corrupt)-100]
+
+		packages, complete, err := image.Packages(newImage(map[string]string{
+			"lib/apk/db/installed":     apkInstalled,
+			"var/lib/rpm/Packages.db":  corrupt,
+			"var/lib/rpm/rpmdb.sqlite": "no database",
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(complete).To(BeFalse())
+		Expect(packages).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,634 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"archive/tar"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/shipwright-io/build/pkg/archive"
+)
+
+// SBOMFormat is the format of a software bill of materials
+type SBOMFormat string
+
+const (
+	// SBOMFormatSPDX is the JSON format of SPDX 2.2
+	SBOMFormatSPDX SBOMFormat = "SPDX"
+
+	// SBOMFormatCycloneDX is the JSON format of CycloneDX 1.4
+	SBOMFormatCycloneDX SBOMFormat = "CycloneDX"
+)
+
+const (
+	// SPDXMediaType is the media type of SBOMs in the SPDX format
+	SPDXMediaType types.MediaType = "application/spdx+json"
+
+	// CycloneDXMediaType is the media type of SBOMs in the CycloneDX format
+	CycloneDXMediaType types.MediaType = "application/vnd.cyclonedx+json"
+)
+
+// SBOMIncompleteAnnotation is the annotation of an SBOM artifact for an image whose packages are
+// not known, because it has no package database, like a static distroless image, or because its
+// package database cannot be read
+const SBOMIncompleteAnnotation = "io.shipwright.build.sbom.incomplete"
+
+const (
+	sbomToolName      = "shipwright-image-processing"
+	incompleteComment = "The image contains no package database of dpkg, apk or rpm that can be read, its packages are unknown."
+
+	dpkgStatusFile    = "var/lib/dpkg/status"
+	dpkgStatusDir     = "var/lib/dpkg/status.d"
+	apkInstalledFile  = "lib/apk/db/installed"
+	osReleaseFile     = "etc/os-release"
+	osReleaseFallback = "usr/lib/os-release"
+)
+
+// Package is an operating system package that is installed in an image
+type Package struct {
+	Name         string
+	Version      string
+	Architecture string
+	License      string
+	PURL         string
+}
+
+// ParseSBOMFormat returns the SBOM format with the given name, which is case insensitive
+func ParseSBOMFormat(value string) (SBOMFormat, error) {
+	for _, format := range []SBOMFormat{SBOMFormatSPDX, SBOMFormatCycloneDX} {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported SBOM format %q, supported are %s and %s", value, SBOMFormatSPDX, SBOMFormatCycloneDX)
+}
+
+// AttachSBOM inspects the layers of the image, generates an SBOM in the format and attaches
+// it to the image as a referrer artifact. It returns the digest reference of the artifact.
+func AttachSBOM(digest name.Digest, format SBOMFormat, options ...remote.Option) (name.Digest, error) {
+	descriptor, err := remote.Get(digest, options...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	var packages []Package
+	var complete bool
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return name.Digest{}, err
+		}
+		packages, complete, err = IndexPackages(index)
+		if err != nil {
+			return name.Digest{}, err
+		}
+	} else {
+		image, err := descriptor.Image()
+		if err != nil {
+			return name.Digest{}, err
+		}
+		packages, complete, err = Packages(image)
+		if err != nil {
+			return name.Digest{}, err
+		}
+	}
+
+	data, mediaType, err := GenerateSBOM(format, digest, packages, complete, time.Now())
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	layer, err := newDataLayer(data, mediaType)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	artifact, err := mutate.AppendLayers(mutate.MediaType(empty.Image, types.OCIManifestSchema1), layer)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	if !complete {
+		artifact = &annotatedImage{Image: artifact, annotations: map[string]string{SBOMIncompleteAnnotation: "true"}}
+	}
+
+	referrer := &referrerImage{Image: artifact, artifactType: string(mediaType), subject: descriptor.Descriptor}
+	ref, err := artifactDigest(digest.Context(), referrer)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	if err := remote.Write(ref, referrer, options...); err != nil {
+		return name.Digest{}, err
+	}
+
+	if err := addReferrer(digest, referrer, options...); err != nil {
+		return name.Digest{}, err
+	}
+
+	return ref, nil
+}
+
+// IndexPackages lists the packages of all images of the index, and whether all
+// images contain a package database
+func IndexPackages(index v1.ImageIndex) ([]Package, bool, error) {
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var packages []Package
+	complete := len(indexManifest.Manifests) > 0
+	for _, descriptor := range indexManifest.Manifests {
+		var childPackages []Package
+		var childComplete bool
+
+		if descriptor.MediaType.IsIndex() {
+			child, err := index.ImageIndex(descriptor.Digest)
+			if err != nil {
+				return nil, false, err
+			}
+			if childPackages, childComplete, err = IndexPackages(child); err != nil {
+				return nil, false, err
+			}
+		} else {
+			child, err := index.Image(descriptor.Digest)
+			if err != nil {
+				return nil, false, err
+			}
+			if childPackages, childComplete, err = Packages(child); err != nil {
+				return nil, false, err
+			}
+		}
+
+		packages = append(packages, childPackages...)
+		complete = complete && childComplete
+	}
+
+	return uniquePackages(packages), complete, nil
+}
+
+// Packages lists the packages that the package databases of dpkg, apk and rpm in the image
+// contain, and whether all packages are known. They are not known for images without a package
+// database, like static distroless images, and for images with an rpm database that cannot be
+// read, for example because it is corrupt.
+func Packages(image v1.Image) ([]Package, bool, error) {
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the package databases as they are in the file system of the image, after applying all layers
+	files := map[string][]byte{}
+	for _, layer := range layers {
+		if err := readLayer(layer, files); err != nil {
+			return nil, false, err
+		}
+	}
+
+	distro, distroVersion := parseOSRelease(files)
+
+	var packages []Package
+	var found, unreadable bool
+	for filename, data := range files {
+		switch {
+		case isRPMDatabase(filename):
+			if distro == "" {
+				distro = "redhat"
+			}
+			rpmPackages, err := parseRPMDatabase(filename, data, distro, distroVersion)
+			if err != nil {
+				// the packages of the database are unknown, which the SBOM states
+				unreadable = true
+				continue
+			}
+			packages = append(packages, rpmPackages...)
+			found = true
+
+		case filename == dpkgStatusFile || path.Dir(filename) == dpkgStatusDir:
+			if distro == "" {
+				distro = "debian"
+			}
+			packages = append(packages, parseDpkgStatus(data, distro, distroVersion)...)
+			found = true
+
+		case filename == apkInstalledFile:
+			if distro == "" {
+				distro = "alpine"
+			}
+			packages = append(packages, parseApkInstalled(data, distro, distroVersion)...)
+			found = true
+		}
+	}
+
+	return uniquePackages(packages), found && !unreadable, nil
+}
+
+// readLayer applies the files of the layer that are relevant to find packages, as well as
+// the whiteouts of the layer, to the files
+func readLayer(layer v1.Layer, files map[string][]byte) error {
+	digest, err := layer.Digest()
+	if err != nil {
+		return err
+	}
+
+	content, err := layer.Uncompressed()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	tarReader := tar.NewReader(content)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read layer %s: %w", digest.String(), err)
+		}
+
+		filename := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		dir, base := path.Split(filename)
+
+		switch {
+		case base == archive.OpaqueWhiteout:
+			removeFiles(files, strings.TrimSuffix(dir, "/"))
+
+		case strings.HasPrefix(base, archive.WhiteoutPrefix):
+			removeFiles(files, dir+strings.TrimPrefix(base, archive.WhiteoutPrefix))
+
+		case header.Typeflag == tar.TypeReg && isPackageFile(filename):
+			data, err := ioutil.ReadAll(tarReader)
+			if err != nil {
+				return err
+			}
+			files[filename] = data
+		}
+	}
+}
+
+func isPackageFile(filename string) bool {
+	switch filename {
+	case dpkgStatusFile, apkInstalledFile, osReleaseFile, osReleaseFallback:
+		return true
+	}
+	return path.Dir(filename) == dpkgStatusDir || isRPMDatabase(filename)
+}
+
+// removeFiles removes the file or the content of the directory with the given path
+func removeFiles(files map[string][]byte, filename string) {
+	for existing := range files {
+		if existing == filename || strings.HasPrefix(existing, filename+"/") {
+			delete(files, existing)
+		}
+	}
+}
+
+// parseOSRelease returns the ID and VERSION_ID of the os-release file
+func parseOSRelease(files map[string][]byte) (string, string) {
+	data, ok := files[osReleaseFile]
+	if !ok {
+		data = files[osReleaseFallback]
+	}
+
+	values := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = strings.Trim(parts[1], `"'`)
+		}
+	}
+
+	return values["ID"], values["VERSION_ID"]
+}
+
+// parseDpkgStatus returns the installed packages of a dpkg status file, which consists of
+// paragraphs of fields. Distroless images store one file per package without status field.
+func parseDpkgStatus(data []byte, distro string, distroVersion string) []Package {
+	var packages []Package
+	for _, paragraph := range parseParagraphs(data, ": ") {
+		if status, ok := paragraph["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		if paragraph["Package"] == "" {
+			continue
+		}
+
+		packages = append(packages, newPackage("deb", distro, distroVersion, paragraph["Package"], paragraph["Version"], paragraph["Architecture"], "", nil))
+	}
+	return packages
+}
+
+// parseApkInstalled returns the packages of the apk database, in which each field of a
+// package is a line that starts with a single letter key
+func parseApkInstalled(data []byte, distro string, distroVersion string) []Package {
+	var packages []Package
+	for _, paragraph := range parseParagraphs(data, ":") {
+		if paragraph["P"] == "" {
+			continue
+		}
+
+		packages = append(packages, newPackage("apk", distro, distroVersion, paragraph["P"], paragraph["V"], paragraph["A"], paragraph["L"], nil))
+	}
+	return packages
+}
+
+// parseParagraphs splits the data into paragraphs that are separated by empty lines, and the
+// lines of a paragraph into fields. Lines that start with a space continue the previous field.
+func parseParagraphs(data []byte, separator string) []map[string]string {
+	var paragraphs []map[string]string
+	paragraph := map[string]string{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, paragraph)
+				paragraph = map[string]string{}
+			}
+
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			continue
+
+		default:
+			parts := strings.SplitN(line, separator, 2)
+			if len(parts) == 2 {
+				paragraph[parts[0]] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, paragraph)
+	}
+
+	return paragraphs
+}
+
+func newPackage(purlType string, distro string, distroVersion string, packageName string, version string, architecture string, license string, extraQualifiers map[string]string) Package {
+	qualifiers := url.Values{}
+	for key, value := range extraQualifiers {
+		qualifiers.Set(key, value)
+	}
+	if architecture != "" {
+		qualifiers.Set("arch", architecture)
+	}
+	if distroVersion != "" {
+		qualifiers.Set("distro", distro+"-"+distroVersion)
+	}
+
+	purl := fmt.Sprintf("pkg:%s/%s/%s", purlType, url.PathEscape(distro), url.PathEscape(packageName))
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+
+	return Package{
+		Name:         packageName,
+		Version:      version,
+		Architecture: architecture,
+		License:      license,
+		PURL:         purl,
+	}
+}
+
+// uniquePackages sorts the packages by their package URL and removes duplicates, which
+// occur for architecture independent packages of the images of an index
+func uniquePackages(packages []Package) []Package {
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].PURL < packages[j].PURL
+	})
+
+	var result []Package
+	for i, pkg := range packages {
+		if i > 0 && packages[i-1].PURL == pkg.PURL {
+			continue
+		}
+		result = append(result, pkg)
+	}
+	return result
+}
+
+// GenerateSBOM returns the SBOM of the image with the packages in the format, and its media type.
+// An SBOM that is not complete, because the image has no package database that can be read, states that the
+// packages of the image are unknown.
+func GenerateSBOM(format SBOMFormat, digest name.Digest, packages []Package, complete bool, created time.Time) ([]byte, types.MediaType, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var document interface{}
+	var mediaType types.MediaType
+
+	switch format {
+	case SBOMFormatSPDX:
+		document, mediaType = spdxDocument(digest, packages, complete, created, serial), SPDXMediaType
+
+	case SBOMFormatCycloneDX:
+		document, mediaType = cycloneDXDocument(digest, packages, complete, created, serial), CycloneDXMediaType
+
+	default:
+		return nil, "", fmt.Errorf("unsupported SBOM format %q", format)
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, mediaType, nil
+}
+
+// imagePURL returns the package URL of the image
+func imagePURL(digest name.Digest) string {
+	repository := digest.Context()
+	return fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s",
+		path.Base(repository.RepositoryStr()),
+		url.PathEscape(digest.DigestStr()),
+		url.QueryEscape(repository.Name()))
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func spdxDocument(digest name.Digest, packages []Package, complete bool, created time.Time, serial string) interface{} {
+	const noAssertion = "NOASSERTION"
+
+	imagePackage := spdxPackage{
+		Name:             digest.Context().Name(),
+		SPDXID:           "SPDXRef-Image",
+		VersionInfo:      digest.DigestStr(),
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+		ExternalRefs: []spdxExternalRef{
+			{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: imagePURL(digest)},
+		},
+	}
+
+	spdxPackages := []spdxPackage{imagePackage}
+	relationships := []spdxRelationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: imagePackage.SPDXID},
+	}
+
+	for i, pkg := range packages {
+		license := noAssertion
+		if pkg.License != "" {
+			license = pkg.License
+		}
+
+		spdxPackage := spdxPackage{
+			Name:             pkg.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      pkg.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    noAssertion,
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.PURL},
+			},
+		}
+
+		spdxPackages = append(spdxPackages, spdxPackage)
+		relationships = append(relationships, spdxRelationship{
+			SPDXElementID:      imagePackage.SPDXID,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: spdxPackage.SPDXID,
+		})
+	}
+
+	creationInfo := map[string]interface{}{
+		"created":  created.UTC().Format(time.RFC3339),
+		"creators": []string{"Tool: " + sbomToolName},
+	}
+	if !complete {
+		creationInfo["comment"] = incompleteComment
+	}
+
+	return map[string]interface{}{
+		"spdxVersion":       "SPDX-2.2",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              digest.String(),
+		"documentNamespace": fmt.Sprintf("https://shipwright.io/spdx/%s-%s", digest.Context().RepositoryStr(), serial),
+		"creationInfo":      creationInfo,
+		"packages":          spdxPackages,
+		"relationships":     relationships,
+	}
+}
+
+type cycloneDXComponent struct {
+	Type     string             `json:"type"`
+	BOMRef   string             `json:"bom-ref,omitempty"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	PURL     string             `json:"purl,omitempty"`
+	Licenses []cycloneDXLicense `json:"licenses,omitempty"`
+}
+
+type cycloneDXLicense struct {
+	Expression string `json:"expression"`
+}
+
+func cycloneDXDocument(digest name.Digest, packages []Package, complete bool, created time.Time, serial string) interface{} {
+	components := []cycloneDXComponent{}
+	for _, pkg := range packages {
+		component := cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  pkg.PURL,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    pkg.PURL,
+		}
+		if pkg.License != "" {
+			component.Licenses = []cycloneDXLicense{{Expression: pkg.License}}
+		}
+		components = append(components, component)
+	}
+
+	document := map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.4",
+		"serialNumber": "urn:uuid:" + serial,
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": created.UTC().Format(time.RFC3339),
+			"tools":     []map[string]string{{"name": sbomToolName}},
+			"component": cycloneDXComponent{
+				Type:    "container",
+				BOMRef:  imagePURL(digest),
+				Name:    digest.Context().Name(),
+				Version: digest.DigestStr(),
+				PURL:    imagePURL(digest),
+			},
+		},
+		"components": components,
+	}
+
+	// the composition of the image states that its packages are unknown
+	if !complete {
+		document["compositions"] = []map[string]interface{}{
+			{"aggregate": "unknown", "assemblies": []string{imagePURL(digest)}},
+		}
+	}
+
+	return document
+}
+
+// newUUID returns a random UUID in version 4
+func newUUID() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", err
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
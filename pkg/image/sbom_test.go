@@ -0,0 +1,294 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+const dpkgStatus = `Package: base-files
+Status: install ok installed
+Architecture: amd64
+Version: 11.1+deb11u1
+Description: Debian base system miscellaneous files
+ This package contains the basic filesystem hierarchy.
+
+Package: tzdata
+Status: install ok installed
+Architecture: all
+Version: 2021a-1+deb11u1
+
+Package: vim
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 2:8.2.2434-3
+`
+
+const apkInstalled = `C:Q1KaFk7eT4cUrHEZNtBZNaO7xcI+s=
+P:musl
+V:1.2.2-r3
+A:x86_64
+L:MIT
+
+C:Q1L7wMmy0ZQ+bqPV+T6Vl5d5bqtlU=
+P:busybox
+V:1.33.1-r3
+A:x86_64
+L:GPL-2.0-only
+`
+
+var _ = Describe("SBOM", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	var packages = func(img v1.Image) []image.Package {
+		packages, complete, err := image.Packages(img)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(complete).To(BeTrue())
+		return packages
+	}
+
+	BeforeEach(func() {
+		var host string
+		server, host = newRegistry()
+
+		var err error
+		ref, err = name.ParseReference(host + "/shipwright/sample:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("listing the packages of an image", func() {
+		It("lists the installed packages of the dpkg database", func() {
+			img := newImage(map[string]string{
+				"etc/os-release":      "ID=debian\nVERSION_ID=\"11\"\n",
+				"var/lib/dpkg/status": dpkgStatus,
+			})
+
+			Expect(packages(img)).To(Equal([]image.Package{
+				{Name: "base-files", Version: "11.1+deb11u1", Architecture: "amd64", PURL: "pkg:deb/debian/base-files@11.1+deb11u1?arch=amd64&distro=debian-11"},
+				{Name: "tzdata", Version: "2021a-1+deb11u1", Architecture: "all", PURL: "pkg:deb/debian/tzdata@2021a-1+deb11u1?arch=all&distro=debian-11"},
+			}))
+		})
+
+		It("lists the packages of the apk database", func() {
+			img := newImage(map[string]string{
+				"lib/apk/db/installed": apkInstalled,
+			})
+
+			Expect(packages(img)).To(Equal([]image.Package{
+				{Name: "busybox", Version: "1.33.1-r3", Architecture: "x86_64", License: "GPL-2.0-only", PURL: "pkg:apk/alpine/busybox@1.33.1-r3?arch=x86_64"},
+				{Name: "musl", Version: "1.2.2-r3", Architecture: "x86_64", License: "MIT", PURL: "pkg:apk/alpine/musl@1.2.2-r3?arch=x86_64"},
+			}))
+		})
+
+		It("ignores package databases that a later layer removed", func() {
+			img := newImage(
+				map[string]string{"lib/apk/db/installed": apkInstalled},
+				map[string]string{"lib/apk/db/.wh.installed": ""},
+			)
+
+			packages, complete, err := image.Packages(img)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(packages).To(BeEmpty())
+			Expect(complete).To(BeFalse())
+		})
+
+		It("reports that an image without a package database is not complete", func() {
+			packages, complete, err := image.Packages(newImage(map[string]string{"app/server": "binary"}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(packages).To(BeEmpty())
+			Expect(complete).To(BeFalse())
+		})
+
+		It("reports that an image with an rpm database that cannot be read is not complete", func() {
+			packages, complete, err := image.Packages(newImage(map[string]string{
+				"etc/os-release":       "ID=\"rhel\"\nVERSION_ID=\"8.4\"\n",
+				"var/lib/rpm/Packages": "berkeley db",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(packages).To(BeEmpty())
+			Expect(complete).To(BeFalse())
+		})
+
+		It("lists the packages of all images of an index", func() {
+			index := newIndex(
+				newImage(map[string]string{"var/lib/dpkg/status": dpkgStatus}),
+				newImage(map[string]string{"lib/apk/db/installed": apkInstalled}),
+			)
+
+			indexPackages, complete, err := image.IndexPackages(index)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexPackages).To(HaveLen(4))
+			Expect(complete).To(BeTrue())
+		})
+	})
+
+	Context("generating an SBOM", func() {
+		var digest name.Digest
+		var created = time.Date(2021, time.June, 3, 14, 5, 9, 0, time.UTC)
+		var pkgs = []image.Package{
+			{Name: "musl", Version: "1.2.2-r3", Architecture: "x86_64", License: "MIT", PURL: "pkg:apk/alpine/musl@1.2.2-r3?arch=x86_64"},
+		}
+
+		BeforeEach(func() {
+			digest = ref.Context().Digest("sha256:6f7e9a1d7ab0e4a5c1b3e9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8")
+		})
+
+		It("generates an SPDX document", func() {
+			data, mediaType, err := image.GenerateSBOM(image.SBOMFormatSPDX, digest, pkgs, true, created)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mediaType).To(Equal(image.SPDXMediaType))
+
+			var document struct {
+				SPDXVersion  string `json:"spdxVersion"`
+				CreationInfo struct {
+					Created string `json:"created"`
+				} `json:"creationInfo"`
+				Packages []struct {
+					Name            string `json:"name"`
+					LicenseDeclared string `json:"licenseDeclared"`
+				} `json:"packages"`
+			}
+			Expect(json.Unmarshal(data, &document)).To(Succeed())
+			Expect(document.SPDXVersion).To(Equal("SPDX-2.2"))
+			Expect(document.CreationInfo.Created).To(Equal("2021-06-03T14:05:09Z"))
+			Expect(document.Packages).To(HaveLen(2))
+			Expect(document.Packages[0].Name).To(Equal(ref.Context().Name()))
+			Expect(document.Packages[1].Name).To(Equal("musl"))
+			Expect(document.Packages[1].LicenseDeclared).To(Equal("MIT"))
+		})
+
+		It("generates a CycloneDX document", func() {
+			data, mediaType, err := image.GenerateSBOM(image.SBOMFormatCycloneDX, digest, pkgs, true, created)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mediaType).To(Equal(image.CycloneDXMediaType))
+
+			var document struct {
+				BOMFormat  string `json:"bomFormat"`
+				Components []struct {
+					Name string `json:"name"`
+					PURL string `json:"purl"`
+				} `json:"components"`
+			}
+			Expect(json.Unmarshal(data, &document)).To(Succeed())
+			Expect(document.BOMFormat).To(Equal("CycloneDX"))
+			Expect(document.Components).To(HaveLen(1))
+			Expect(document.Components[0].PURL).To(Equal("pkg:apk/alpine/musl@1.2.2-r3?arch=x86_64"))
+		})
+
+		It("states that the packages are unknown in an SBOM that is not complete", func() {
+			data, _, err := image.GenerateSBOM(image.SBOMFormatCycloneDX, digest, nil, false, created)
+			Expect(err).ToNot(HaveOccurred())
+
+			var cycloneDX struct {
+				Compositions []struct {
+					Aggregate string `json:"aggregate"`
+				} `json:"compositions"`
+			}
+			Expect(json.Unmarshal(data, &cycloneDX)).To(Succeed())
+			Expect(cycloneDX.Compositions).To(HaveLen(1))
+			Expect(cycloneDX.Compositions[0].Aggregate).To(Equal("unknown"))
+
+			data, _, err = image.GenerateSBOM(image.SBOMFormatSPDX, digest, nil, false, created)
+			Expect(err).ToNot(HaveOccurred())
+
+			var spdx struct {
+				CreationInfo struct {
+					Comment string `json:"comment"`
+				} `json:"creationInfo"`
+			}
+			Expect(json.Unmarshal(data, &spdx)).To(Succeed())
+			Expect(spdx.CreationInfo.Comment).ToNot(BeEmpty())
+		})
+
+		It("parses the format case insensitive", func() {
+			Expect(image.ParseSBOMFormat("spdx")).To(Equal(image.SBOMFormatSPDX))
+			Expect(image.ParseSBOMFormat("CycloneDX")).To(Equal(image.SBOMFormatCycloneDX))
+
+			_, err := image.ParseSBOMFormat("syft")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("attaching an SBOM", func() {
+		It("pushes the SBOM as a referrer of the image", func() {
+			pushed := digestOf(pushImage(ref, map[string]string{"lib/apk/db/installed": apkInstalled}))
+			digest := ref.Context().Digest(pushed.String())
+
+			sbom, err := image.AttachSBOM(digest, image.SBOMFormatCycloneDX)
+			Expect(err).ToNot(HaveOccurred())
+
+			var manifest struct {
+				ArtifactType string          `json:"artifactType"`
+				Subject      v1.Descriptor   `json:"subject"`
+				Layers       []v1.Descriptor `json:"layers"`
+			}
+			Expect(json.Unmarshal(get(sbom).Manifest, &manifest)).To(Succeed())
+			Expect(manifest.ArtifactType).To(Equal(string(image.CycloneDXMediaType)))
+			Expect(manifest.Subject.Digest).To(Equal(pushed))
+			Expect(manifest.Layers).To(HaveLen(1))
+
+			layer, err := remote.Layer(ref.Context().Digest(manifest.Layers[0].Digest.String()))
+			Expect(err).ToNot(HaveOccurred())
+			blob, err := layer.Compressed()
+			Expect(err).ToNot(HaveOccurred())
+			defer blob.Close()
+			data, err := ioutil.ReadAll(blob)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("pkg:apk/alpine/busybox@1.33.1-r3?arch=x86_64"))
+
+			var index struct {
+				Manifests []struct {
+					Digest       string `json:"digest"`
+					ArtifactType string `json:"artifactType"`
+				} `json:"manifests"`
+			}
+			Expect(json.Unmarshal(get(ref.Context().Tag("sha256-"+pushed.Hex)).Manifest, &index)).To(Succeed())
+			Expect(index.Manifests).To(HaveLen(1))
+			Expect(index.Manifests[0].Digest).To(Equal(sbom.DigestStr()))
+			Expect(index.Manifests[0].ArtifactType).To(Equal(string(image.CycloneDXMediaType)))
+		})
+
+		It("annotates the SBOM of an image without a package database", func() {
+			pushed := digestOf(pushImage(ref, map[string]string{"app/server": "binary"}))
+
+			sbom, err := image.AttachSBOM(ref.Context().Digest(pushed.String()), image.SBOMFormatSPDX)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest, err := v1.ParseManifest(bytes.NewReader(get(sbom).Manifest))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest.Annotations).To(HaveKeyWithValue(image.SBOMIncompleteAnnotation, "true"))
+		})
+
+		It("does not annotate the SBOM of an image with a package database", func() {
+			pushed := digestOf(pushImage(ref, map[string]string{"lib/apk/db/installed": apkInstalled}))
+
+			sbom, err := image.AttachSBOM(ref.Context().Digest(pushed.String()), image.SBOMFormatSPDX)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest, err := v1.ParseManifest(bytes.NewReader(get(sbom).Manifest))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest.Annotations).ToNot(HaveKey(image.SBOMIncompleteAnnotation))
+		})
+	})
+})
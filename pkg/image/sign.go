@@ -0,0 +1,164 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	// SignatureMediaType is the media type of the layers of a signature artifact, which
+	// contain the signed payload in the simple signing format
+	SignatureMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// SignatureAnnotation is the annotation of the layers of a signature artifact that
+	// holds the base64 encoded signature of the payload
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// SignaturePayload is the payload that is signed for an image, it follows the simple
+// signing format, so that the signatures can be verified with cosign
+type SignaturePayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+// LoadPrivateKey parses an unencrypted ECDSA, RSA or Ed25519 private key in PEM format
+func LoadPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("the private key is not in PEM format")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type %s, the key must not be encrypted", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return key.(crypto.Signer), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key %T", key)
+	}
+}
+
+// SignatureTag returns the tag under which the signatures of an image are stored,
+// which is derived from the digest of the image
+func SignatureTag(digest name.Digest) name.Tag {
+	return digestTag(digest, ".sig")
+}
+
+// digestTag returns a tag that is derived from the digest of an image, like sha256-<hex><suffix>
+func digestTag(digest name.Digest, suffix string) name.Tag {
+	return digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + suffix)
+}
+
+// Sign signs the digest of an image with the private key, and pushes the signature as an
+// artifact next to the image under its signature tag. If the image already has signatures,
+// the signature is added to them. It returns the digest reference of the signature artifact.
+func Sign(digest name.Digest, signer crypto.Signer, options ...remote.Option) (name.Digest, error) {
+	var payload SignaturePayload
+	payload.Critical.Identity.DockerReference = digest.Context().Name()
+	payload.Critical.Image.DockerManifestDigest = digest.DigestStr()
+	payload.Critical.Type = "cosign container image signature"
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	signature, err := signPayload(signer, data)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	layer, err := newDataLayer(data, SignatureMediaType)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	tag := SignatureTag(digest)
+	signatures, err := remote.Image(tag, options...)
+	switch {
+	case IsNotFound(err):
+		signatures = mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	case err != nil:
+		return name.Digest{}, err
+	}
+
+	signatures, err = mutate.Append(signatures, mutate.Addendum{
+		Layer: layer,
+		Annotations: map[string]string{
+			SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+		},
+	})
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	if err := remote.Write(tag, signatures, options...); err != nil {
+		return name.Digest{}, err
+	}
+
+	return artifactDigest(digest.Context(), signatures)
+}
+
+// artifactDigest returns the digest reference of the artifact in the repository
+func artifactDigest(repository name.Repository, artifact v1.Image) (name.Digest, error) {
+	digest, err := artifact.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return repository.Digest(digest.String()), nil
+}
+
+// signPayload signs the SHA-256 hash of the payload, Ed25519 keys sign the payload itself
+func signPayload(signer crypto.Signer, payload []byte) ([]byte, error) {
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		return signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+
+	hash := sha256.Sum256(payload)
+	return signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
@@ -0,0 +1,153 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http/httptest"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("Sign", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	// signatureLayers returns the payloads and signatures of the signature artifact
+	var signatureLayers = func(signature name.Digest) ([][]byte, [][]byte) {
+		artifact, err := remote.Image(signature)
+		Expect(err).ToNot(HaveOccurred())
+
+		manifest, err := artifact.Manifest()
+		Expect(err).ToNot(HaveOccurred())
+
+		var payloads, signatures [][]byte
+		for _, layer := range manifest.Layers {
+			Expect(layer.MediaType).To(Equal(image.SignatureMediaType))
+
+			blobLayer, err := artifact.LayerByDigest(layer.Digest)
+			Expect(err).ToNot(HaveOccurred())
+			blob, err := blobLayer.Compressed()
+			Expect(err).ToNot(HaveOccurred())
+			payload, err := ioutil.ReadAll(blob)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blob.Close()).To(Succeed())
+
+			decoded, err := base64.StdEncoding.DecodeString(layer.Annotations[image.SignatureAnnotation])
+			Expect(err).ToNot(HaveOccurred())
+
+			payloads = append(payloads, payload)
+			signatures = append(signatures, decoded)
+		}
+
+		return payloads, signatures
+	}
+
+	BeforeEach(func() {
+		var host string
+		server, host = newRegistry()
+
+		var err error
+		ref, err = name.ParseReference(host + "/shipwright/sample:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("pushes a signature that can be verified with the public key next to the image", func() {
+		pushed := digestOf(pushImage(ref, map[string]string{"file": "content"}))
+		digest := ref.Context().Digest(pushed.String())
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		signature, err := image.Sign(digest, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(signature.Context()).To(Equal(ref.Context()))
+		Expect(get(image.SignatureTag(digest)).Digest.String()).To(Equal(signature.DigestStr()))
+
+		payloads, signatures := signatureLayers(signature)
+		Expect(payloads).To(HaveLen(1))
+
+		var payload image.SignaturePayload
+		Expect(json.Unmarshal(payloads[0], &payload)).To(Succeed())
+		Expect(payload.Critical.Identity.DockerReference).To(Equal(ref.Context().Name()))
+		Expect(payload.Critical.Image.DockerManifestDigest).To(Equal(pushed.String()))
+
+		hash := sha256.Sum256(payloads[0])
+		Expect(ecdsa.VerifyASN1(&key.PublicKey, hash[:], signatures[0])).To(BeTrue())
+	})
+
+	It("appends the signature to existing signatures of the image", func() {
+		digest := ref.Context().Digest(digestOf(pushImage(ref, map[string]string{"file": "content"})).String())
+
+		_, first, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		public, second, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = image.Sign(digest, first)
+		Expect(err).ToNot(HaveOccurred())
+		signature, err := image.Sign(digest, second)
+		Expect(err).ToNot(HaveOccurred())
+
+		payloads, signatures := signatureLayers(signature)
+		Expect(payloads).To(HaveLen(2))
+		Expect(ed25519.Verify(public, payloads[1], signatures[1])).To(BeTrue())
+	})
+})
+
+var _ = Describe("LoadPrivateKey", func() {
+	It("loads an EC private key", func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalECPrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+
+		signer, err := image.LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(signer.Public().(*ecdsa.PublicKey).Equal(&key.PublicKey)).To(BeTrue())
+	})
+
+	It("loads a PKCS #8 private key", func() {
+		public, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+
+		signer, err := image.LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(signer.Public()).To(Equal(crypto.PublicKey(public)))
+	})
+
+	It("fails for data that is not a PEM encoded key", func() {
+		_, err := image.LoadPrivateKey([]byte("not a key"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails for encrypted keys", func() {
+		_, err := image.LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("data")}))
+		Expect(err).To(HaveOccurred())
+	})
+})
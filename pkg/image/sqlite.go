@@ -0,0 +1,320 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	sqliteHeader         = "SQLite format 3\x00"
+	sqliteHeaderSize     = 100
+	sqliteInteriorTable  = 0x05
+	sqliteLeafTable      = 0x0d
+	sqliteMaxTreeDepth   = 64
+	sqliteSchemaRootPage = 1
+)
+
+// sqliteDatabase reads the tables of an SQLite database file, as described in
+// https://www.sqlite.org/fileformat.html, without the write-ahead log
+type sqliteDatabase struct {
+	data       []byte
+	pageSize   int
+	usableSize int
+}
+
+// readSQLiteTable returns the values of the column of all rows of the table in the SQLite
+// database, the column is the index in the definition of the table
+func readSQLiteTable(data []byte, table string, column int) ([][]byte, error) {
+	if len(data) < sqliteHeaderSize || string(data[:len(sqliteHeader)]) != sqliteHeader {
+		return nil, errors.New("the file is not an SQLite database")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("the page size %d is invalid", pageSize)
+	}
+
+	db := &sqliteDatabase{
+		data:       data,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(data[20]),
+	}
+
+	// the schema table lists the type, name, table name, root page and SQL of each table
+	var rootPage int
+	err := db.walk(sqliteSchemaRootPage, 0, func(record []interface{}) error {
+		if len(record) > 3 && record[0] == "table" && record[1] == table {
+			if page, ok := record[3].(int64); ok {
+				rootPage = int(page)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rootPage == 0 {
+		return nil, fmt.Errorf("the database has no table %s", table)
+	}
+
+	var values [][]byte
+	err = db.walk(rootPage, 0, func(record []interface{}) error {
+		if len(record) <= column {
+			return fmt.Errorf("a row of table %s has no column %d", table, column)
+		}
+		switch value := record[column].(type) {
+		case []byte:
+			values = append(values, value)
+		case string:
+			values = append(values, []byte(value))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// page returns the page with the number, the first page has the number 1
+func (db *sqliteDatabase) page(number int) ([]byte, error) {
+	if number < 1 || number*db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("page %d is out of bounds", number)
+	}
+	return db.data[(number-1)*db.pageSize : number*db.pageSize], nil
+}
+
+// walk calls the function with the record of each row of the table b-tree with the root page
+func (db *sqliteDatabase) walk(number int, depth int, fn func(record []interface{}) error) error {
+	if depth > sqliteMaxTreeDepth {
+		return errors.New("the table is nested too deeply")
+	}
+
+	page, err := db.page(number)
+	if err != nil {
+		return err
+	}
+
+	// the header of the first page follows the header of the database file
+	headerOffset := 0
+	if number == 1 {
+		headerOffset = sqliteHeaderSize
+	}
+
+	header := page[headerOffset:]
+	if len(header) < 12 {
+		return fmt.Errorf("the header of page %d is out of bounds", number)
+	}
+	pageType := header[0]
+	cellCount := int(binary.BigEndian.Uint16(header[3:5]))
+
+	cellPointers := header[8:]
+	if pageType == sqliteInteriorTable {
+		cellPointers = header[12:]
+	}
+	if 2*cellCount > len(cellPointers) {
+		return fmt.Errorf("the cells of page %d are out of bounds", number)
+	}
+
+	for i := 0; i < cellCount; i++ {
+		offset := int(binary.BigEndian.Uint16(cellPointers[2*i:]))
+		if offset >= db.usableSize {
+			return fmt.Errorf("cell %d of page %d is out of bounds", i, number)
+		}
+		cell := page[offset:db.usableSize]
+
+		switch pageType {
+		case sqliteInteriorTable:
+			if len(cell) < 4 {
+				return fmt.Errorf("cell %d of page %d is out of bounds", i, number)
+			}
+			if err := db.walk(int(binary.BigEndian.Uint32(cell[0:4])), depth+1, fn); err != nil {
+				return err
+			}
+
+		case sqliteLeafTable:
+			payload, err := db.payload(cell)
+			if err != nil {
+				return fmt.Errorf("cell %d of page %d is invalid: %w", i, number, err)
+			}
+			record, err := parseSQLiteRecord(payload)
+			if err != nil {
+				return fmt.Errorf("cell %d of page %d is invalid: %w", i, number, err)
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("page %d is no table page", number)
+		}
+	}
+
+	// the right-most child of an interior page follows its header
+	if pageType == sqliteInteriorTable {
+		return db.walk(int(binary.BigEndian.Uint32(header[8:12])), depth+1, fn)
+	}
+
+	return nil
+}
+
+// payload returns the payload of a cell of a table leaf page, the part of the payload that
+// does not fit on the page is stored on a chain of overflow pages
+func (db *sqliteDatabase) payload(cell []byte) ([]byte, error) {
+	size, n := sqliteVarint(cell)
+	if n == 0 {
+		return nil, errors.New("the payload size is invalid")
+	}
+	cell = cell[n:]
+
+	// the row ID is not needed
+	_, n = sqliteVarint(cell)
+	if n == 0 {
+		return nil, errors.New("the row ID is invalid")
+	}
+	cell = cell[n:]
+
+	payloadSize := int(size)
+	if size > uint64(len(db.data)) {
+		return nil, errors.New("the payload size is out of bounds")
+	}
+
+	maxLocal := db.usableSize - 35
+	localSize := payloadSize
+	if payloadSize > maxLocal {
+		minLocal := (db.usableSize-12)*32/255 - 23
+		localSize = minLocal + (payloadSize-minLocal)%(db.usableSize-4)
+		if localSize > maxLocal {
+			localSize = minLocal
+		}
+	}
+
+	if localSize > len(cell) {
+		return nil, errors.New("the payload is out of bounds")
+	}
+
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, cell[:localSize]...)
+	if localSize == payloadSize {
+		return payload, nil
+	}
+
+	if localSize+4 > len(cell) {
+		return nil, errors.New("the overflow page is out of bounds")
+	}
+
+	next := int(binary.BigEndian.Uint32(cell[localSize:]))
+	for len(payload) < payloadSize {
+		page, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+
+		content := page[4:db.usableSize]
+		if remaining := payloadSize - len(payload); remaining < len(content) {
+			content = content[:remaining]
+		}
+
+		payload = append(payload, content...)
+		next = int(binary.BigEndian.Uint32(page[0:4]))
+	}
+
+	return payload, nil
+}
+
+// parseSQLiteRecord returns the values of a record, which are nil, int64, float64,
+// string or []byte depending on their serial type in the header of the record
+func parseSQLiteRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := sqliteVarint(payload)
+	if n == 0 || headerSize < uint64(n) || headerSize > uint64(len(payload)) {
+		return nil, errors.New("the record header is out of bounds")
+	}
+
+	header := payload[n:headerSize]
+	body := payload[headerSize:]
+
+	var record []interface{}
+	for len(header) > 0 {
+		serialType, n := sqliteVarint(header)
+		if n == 0 {
+			return nil, errors.New("the serial type is invalid")
+		}
+		header = header[n:]
+
+		var size int
+		switch {
+		case serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6 || serialType == 7:
+			size = 8
+		case serialType == 8 || serialType == 9:
+			size = 0
+		case serialType >= 12:
+			size = int((serialType - 12) / 2)
+		default:
+			return nil, fmt.Errorf("the serial type %d is invalid", serialType)
+		}
+
+		if size < 0 || size > len(body) {
+			return nil, errors.New("the record body is out of bounds")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			record = append(record, nil)
+		case serialType == 8:
+			record = append(record, int64(0))
+		case serialType == 9:
+			record = append(record, int64(1))
+		case serialType == 7:
+			record = append(record, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType <= 6:
+			// integers are big endian two's complement numbers
+			var number int64
+			for i, b := range value {
+				if i == 0 {
+					number = int64(int8(b))
+				} else {
+					number = number<<8 | int64(b)
+				}
+			}
+			record = append(record, number)
+		case serialType%2 == 0:
+			record = append(record, value)
+		default:
+			record = append(record, string(value))
+		}
+	}
+
+	return record, nil
+}
+
+// sqliteVarint returns the value of the variable length integer at the start of the data and its
+// length, which is zero if the data ends before the integer
+func sqliteVarint(data []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < 9 && i < len(data); i++ {
+		if i == 8 {
+			return value<<8 | uint64(data[i]), 9
+		}
+		value = value<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,90 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// Placeholders that can be used in the additional tags of an output image
+const (
+	PlaceholderCommitSha      = "$(commit-sha)"
+	PlaceholderCommitShaShort = "$(commit-sha-short)"
+	PlaceholderBuildRunName   = "$(buildrun-name)"
+	PlaceholderTimestamp      = "$(timestamp)"
+)
+
+// maxTagLength is the maximum length of a tag in a container registry
+const maxTagLength = 128
+
+// timestampFormat is the format of the timestamp placeholder, it only
+// consists of characters that are valid in a tag
+const timestampFormat = "20060102150405"
+
+// TagValues are the values of the placeholders in tag templates
+type TagValues struct {
+	CommitSha    string
+	BuildRunName string
+	Timestamp    time.Time
+}
+
+// ResolveTag replaces the placeholders in the tag template and verifies that
+// the result is a valid tag. A BuildRun name can be longer than a tag, which
+// is why it is shortened as far as needed for the tag to fit the maximum length.
+func ResolveTag(template string, values TagValues) (string, error) {
+	if (strings.Contains(template, PlaceholderCommitSha) || strings.Contains(template, PlaceholderCommitShaShort)) && values.CommitSha == "" {
+		return "", fmt.Errorf("the tag %s requires the commit SHA of the source, which is not available", template)
+	}
+
+	commitShaShort := values.CommitSha
+	if len(commitShaShort) > 7 {
+		commitShaShort = commitShaShort[:7]
+	}
+
+	tag := strings.NewReplacer(
+		PlaceholderCommitSha, values.CommitSha,
+		PlaceholderCommitShaShort, commitShaShort,
+		PlaceholderTimestamp, values.Timestamp.UTC().Format(timestampFormat),
+	).Replace(template)
+
+	buildRunName := values.BuildRunName
+	if occurrences := strings.Count(tag, PlaceholderBuildRunName); occurrences > 0 {
+		available := (maxTagLength - len(tag) + occurrences*len(PlaceholderBuildRunName)) / occurrences
+		if available < 0 {
+			available = 0
+		}
+		if len(buildRunName) > available {
+			buildRunName = buildRunName[:available]
+		}
+	}
+	tag = strings.ReplaceAll(tag, PlaceholderBuildRunName, buildRunName)
+
+	if _, err := name.NewTag("registry.invalid/repository:"+tag, name.StrictValidation); err != nil {
+		return "", fmt.Errorf("the tag %s is invalid: %w", template, err)
+	}
+
+	return tag, nil
+}
+
+// Tag pushes the manifest of the digest reference under the given tags of the same repository
+func Tag(digest name.Digest, tags []string, options ...remote.Option) error {
+	descriptor, err := remote.Get(digest, options...)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err := remote.Tag(digest.Context().Tag(tag), descriptor, options...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("ResolveTag", func() {
+	values := image.TagValues{
+		CommitSha:    "0e0583421a5e4bf562ffe33f3651e16ba0c78591",
+		BuildRunName: "sample-go-buildrun-x7k2p",
+		Timestamp:    time.Date(2021, time.June, 3, 14, 5, 9, 0, time.UTC),
+	}
+
+	DescribeTable("resolves the placeholders",
+		func(template string, expected string) {
+			Expect(image.ResolveTag(template, values)).To(Equal(expected))
+		},
+		Entry("static tag", "latest", "latest"),
+		Entry("commit SHA", "$(commit-sha)", "0e0583421a5e4bf562ffe33f3651e16ba0c78591"),
+		Entry("short commit SHA", "v1-$(commit-sha-short)", "v1-0e05834"),
+		Entry("BuildRun name", "$(buildrun-name)", "sample-go-buildrun-x7k2p"),
+		Entry("timestamp", "build-$(timestamp)", "build-20210603140509"),
+	)
+
+	It("shortens a long BuildRun name to the maximum length of a tag", func() {
+		longValues := values
+		longValues.BuildRunName = strings.Repeat("a", 253)
+
+		Expect(image.ResolveTag("$(buildrun-name)", longValues)).To(Equal(strings.Repeat("a", 128)))
+		Expect(image.ResolveTag("v1-$(buildrun-name)-$(commit-sha-short)", longValues)).To(Equal("v1-" + strings.Repeat("a", 117) + "-0e05834"))
+		Expect(image.ResolveTag("$(buildrun-name)-$(buildrun-name)", longValues)).To(Equal(strings.Repeat("a", 63) + "-" + strings.Repeat("a", 63)))
+	})
+
+	DescribeTable("fails for invalid tags",
+		func(template string, values image.TagValues) {
+			_, err := image.ResolveTag(template, values)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("invalid characters", "feature/branch", values),
+		Entry("empty tag", "", values),
+		Entry("missing commit SHA", "$(commit-sha-short)", image.TagValues{BuildRunName: "buildrun"}),
+		Entry("too long tag", strings.Repeat("v", 129), values),
+	)
+})
+
+var _ = Describe("Tag", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+	)
+
+	BeforeEach(func() {
+		var host string
+		server, host = newRegistry()
+
+		var err error
+		ref, err = name.ParseReference(host + "/shipwright/sample:latest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("pushes the manifest under all tags", func() {
+		pushed := pushImage(ref, map[string]string{"file": "content"})
+
+		Expect(image.Tag(ref.Context().Digest(digestOf(pushed).String()), []string{"v1", "0e05834"})).To(Succeed())
+
+		for _, tag := range []string{"v1", "0e05834"} {
+			Expect(get(ref.Context().Tag(tag)).Digest).To(Equal(digestOf(pushed)))
+		}
+	})
+})
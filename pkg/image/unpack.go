@@ -0,0 +1,102 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/shipwright-io/build/pkg/archive"
+)
+
+// PullAndUnpack extracts the layers of the image that the reference points to into the
+// target directory, and returns the digest of the image. For an image index, the image
+// of the platform of the current process is extracted, or the first image of the index
+// if the index does not contain one for this platform.
+func PullAndUnpack(ref name.Reference, target string, options ...remote.Option) (v1.Hash, error) {
+	descriptor, err := remote.Get(ref, options...)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	var image v1.Image
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return v1.Hash{}, err
+		}
+
+		if image, err = platformImage(index); err != nil {
+			return v1.Hash{}, err
+		}
+	} else if image, err = descriptor.Image(); err != nil {
+		return v1.Hash{}, err
+	}
+
+	layers, err := image.Layers()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	for _, layer := range layers {
+		if err := unpackLayer(layer, target); err != nil {
+			return v1.Hash{}, err
+		}
+	}
+
+	return descriptor.Digest, nil
+}
+
+// platformImage returns the image of the index for the platform of the current
+// process, or the first image of the index
+func platformImage(index v1.ImageIndex) (v1.Image, error) {
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := index.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indexManifest.Manifests) == 0 {
+		return nil, fmt.Errorf("the image index %s does not contain any image", digest.String())
+	}
+
+	selected := indexManifest.Manifests[0]
+	for _, descriptor := range indexManifest.Manifests {
+		if descriptor.Platform != nil && descriptor.Platform.OS == runtime.GOOS && descriptor.Platform.Architecture == runtime.GOARCH {
+			selected = descriptor
+			break
+		}
+	}
+
+	return index.Image(selected.Digest)
+}
+
+// unpackLayer extracts the files of the layer into the target directory and applies
+// the whiteouts of the layer to the files that previous layers extracted
+func unpackLayer(layer v1.Layer, target string) error {
+	digest, err := layer.Digest()
+	if err != nil {
+		return err
+	}
+
+	content, err := layer.Uncompressed()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if err := archive.ExtractLayer(content, target); err != nil {
+		return fmt.Errorf("failed to extract layer %s: %w", digest.String(), err)
+	}
+	return nil
+}
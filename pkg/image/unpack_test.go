@@ -0,0 +1,87 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/build/pkg/image"
+)
+
+var _ = Describe("PullAndUnpack", func() {
+	var (
+		server *httptest.Server
+		ref    name.Reference
+		target string
+	)
+
+	var filecontent = func(path string) string {
+		data, err := ioutil.ReadFile(filepath.Join(target, path))
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		var host string
+		server, host = newRegistry()
+
+		var err error
+		ref, err = name.ParseReference(host + "/shipwright/source-bundle:latest")
+		Expect(err).ToNot(HaveOccurred())
+
+		target, err = ioutil.TempDir("", "source-bundle")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(target)
+	})
+
+	It("extracts the layers of the image into the target directory", func() {
+		pushed := pushLayers(ref,
+			map[string]string{"main.go": "package main", "docs/README.md": "# Sample"},
+			map[string]string{"docs/.wh.README.md": "", "go.mod": "module sample"},
+		)
+
+		digest, err := image.PullAndUnpack(ref, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(digestOf(pushed)))
+
+		Expect(filecontent("main.go")).To(Equal("package main"))
+		Expect(filecontent("go.mod")).To(Equal("module sample"))
+		Expect(filepath.Join(target, "docs", "README.md")).ToNot(BeAnExistingFile())
+	})
+
+	It("extracts the first image of an index", func() {
+		index := pushIndex(ref, newImage(map[string]string{"main.go": "package main"}))
+
+		digest, err := image.PullAndUnpack(ref, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(digestOf(index)))
+		Expect(filecontent("main.go")).To(Equal("package main"))
+	})
+
+	It("does not extract files outside of the target directory", func() {
+		pushImage(ref, map[string]string{"../../escaped.txt": "content"})
+
+		_, err := image.PullAndUnpack(ref, target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filecontent("escaped.txt")).To(Equal("content"))
+	})
+
+	It("fails in case the image does not exist", func() {
+		_, err := image.PullAndUnpack(ref, target)
+		Expect(err).To(HaveOccurred())
+		Expect(image.IsNotFound(err)).To(BeTrue())
+	})
+})
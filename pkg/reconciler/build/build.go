@@ -6,9 +6,11 @@ package build
 
 import (
 	"context"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -61,10 +63,6 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 		return reconcile.Result{}, nil
 	}
 
-	// Populate the status struct with default values
-	b.Status.Registered = corev1.ConditionFalse
-	b.Status.Reason = build.SucceedStatus
-
 	// build a list of current validation types
 	validationTypes := []string{
 		validate.OwnerReferences,
@@ -73,9 +71,13 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 		validate.Strategies,
 		validate.Runtime,
 		validate.Sources,
+		validate.ParamValues,
+		validate.Output,
 	}
 
-	// trigger all current validations
+	// trigger all current validations, and collect their failures instead
+	// of stopping at the first one
+	var failures []validationFailure
 	for _, validationType := range validationTypes {
 		v, err := validate.NewValidation(validationType, b, r.client, r.scheme)
 		if err != nil {
@@ -83,10 +85,13 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 			return reconcile.Result{}, err
 		}
 
+		b.Status.Reason = build.SucceedStatus
+		b.Status.Message = ""
+
 		if err := v.ValidatePath(ctx); err != nil {
 			// We enqueue another reconcile here. This is done only for validation
 			// types where the error can be produced from a failed API call.
-			if validationType == validate.Secrets || validationType == validate.Strategies {
+			if validationType == validate.Secrets || validationType == validate.Strategies || validationType == validate.ParamValues {
 				return reconcile.Result{}, err
 			}
 			if validationType == validate.OwnerReferences {
@@ -97,17 +102,24 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 			}
 		}
 		if b.Status.Reason != build.SucceedStatus {
-			return r.UpdateBuildStatusAndRetreat(ctx, b)
+			failures = append(failures, validationFailure{
+				validationType: validationType,
+				reason:         b.Status.Reason,
+				message:        b.Status.Message,
+			})
 		}
 	}
 
-	b.Status.Registered = corev1.ConditionTrue
-	b.Status.Message = build.AllValidationsSucceeded
-	err = r.client.Status().Update(ctx, b)
-	if err != nil {
+	ready := updateBuildConditions(b, failures)
+	if err := r.client.Status().Update(ctx, b); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if ready.Status != corev1.ConditionTrue {
+		ctxlog.Debug(ctx, "finishing reconciling Build, validations failed", namespace, request.Namespace, name, request.Name, "reason", ready.Reason)
+		return reconcile.Result{}, nil
+	}
+
 	// Increase Build count in metrics
 	buildmetrics.BuildCountInc(b.Spec.Strategy.Name, b.Namespace, b.Name)
 
@@ -115,12 +127,79 @@ func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result,
 	return reconcile.Result{}, nil
 }
 
-// UpdateBuildStatusAndRetreat returns an error if an update fails, this should force
-// a new reconcile until the API call succeeds. If return is nil, no further reconciliations
-// will take place
-func (r *ReconcileBuild) UpdateBuildStatusAndRetreat(ctx context.Context, b *build.Build) (reconcile.Result, error) {
-	if err := r.client.Status().Update(ctx, b); err != nil {
-		return reconcile.Result{}, err
+// validationFailure holds the result of a failed validation
+type validationFailure struct {
+	validationType string
+	reason         build.BuildReason
+	message        string
+}
+
+// validationConditions maps the validation types to the condition that reflects
+// their result, validations without a condition only contribute to the Ready condition
+var validationConditions = map[string]build.Type{
+	validate.SourceURL:   build.BuildSourceValid,
+	validate.Sources:     build.BuildSourceValid,
+	validate.Secrets:     build.BuildCredentialsValid,
+	validate.Strategies:  build.BuildStrategyValid,
+	validate.ParamValues: build.BuildStrategyValid,
+	validate.Runtime:     build.BuildRuntimeValid,
+}
+
+// updateBuildConditions sets the per-area conditions and the Ready condition of
+// the Build based on the validation failures, and returns the Ready condition.
+// The deprecated Registered, Reason and Message fields mirror the Ready condition.
+func updateBuildConditions(b *build.Build, failures []validationFailure) *build.Condition {
+	now := metav1.Now()
+
+	for _, conditionType := range []build.Type{
+		build.BuildSourceValid,
+		build.BuildStrategyValid,
+		build.BuildCredentialsValid,
+		build.BuildRuntimeValid,
+	} {
+		var areaFailures []validationFailure
+		for _, failure := range failures {
+			if validationConditions[failure.validationType] == conditionType {
+				areaFailures = append(areaFailures, failure)
+			}
+		}
+		b.Status.SetCondition(newBuildCondition(conditionType, areaFailures, now))
+	}
+
+	ready := newBuildCondition(build.BuildReady, failures, now)
+	b.Status.SetCondition(ready)
+
+	b.Status.Registered = ready.Status
+	b.Status.Reason = build.BuildReason(ready.Reason)
+	b.Status.Message = ready.Message
+	b.Status.ObservedGeneration = b.Generation
+
+	return ready
+}
+
+// newBuildCondition returns a True condition when there are no failures, otherwise a False
+// condition with the reason of the first failure and the messages of all failures
+func newBuildCondition(conditionType build.Type, failures []validationFailure, now metav1.Time) *build.Condition {
+	if len(failures) == 0 {
+		return &build.Condition{
+			Type:               conditionType,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+			Reason:             string(build.SucceedStatus),
+			Message:            build.AllValidationsSucceeded,
+		}
+	}
+
+	messages := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		messages = append(messages, failure.message)
+	}
+
+	return &build.Condition{
+		Type:               conditionType,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: now,
+		Reason:             string(failures[0].reason),
+		Message:            strings.Join(messages, "; "),
 	}
-	return reconcile.Result{}, nil
 }
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -216,6 +217,25 @@ var _ = Describe("Reconcile Build", func() {
 			})
 		})
 
+		Context("when spec output signing secret is specified", func() {
+			It("fails when the secret does not exist", func() {
+				buildSample.Spec.Output.Credentials = nil
+				buildSample.Spec.Output.Signing = &build.ImageSigning{
+					SecretRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "non-existing"},
+						Key:                  "cosign.key",
+					},
+				}
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecOutputSigningSecretRefNotFound, "referenced secret non-existing not found")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when source secret and output secret are specified", func() {
 			It("fails when both secrets do not exist", func() {
 				buildSample.Spec.Source.Credentials = &corev1.LocalObjectReference{
@@ -369,6 +389,189 @@ var _ = Describe("Reconcile Build", func() {
 			})
 		})
 
+		Context("when source bundle container is specified", func() {
+			It("succeeds without a source URL", func() {
+				buildSample.Spec.Source.URL = ""
+				buildSample.Spec.Source.BundleContainer = &build.BundleContainer{Image: "quay.io/shipwright/source-bundle:latest"}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, "all validations succeeded")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a source URL is specified as well", func() {
+				buildSample.Spec.Source.BundleContainer = &build.BundleContainer{Image: "quay.io/shipwright/source-bundle:latest"}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceInvalid, "the source must not define both a url and a bundleContainer")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when neither a source URL nor a bundle container is specified", func() {
+				buildSample.Spec.Source.URL = ""
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceInvalid, "the source must define either a url or a bundleContainer")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when git settings are specified", func() {
+			It("succeeds with a depth and sparse directories", func() {
+				depth := 10
+				buildSample.Spec.Source.Git = &build.GitSource{Depth: &depth, Sparse: &[]string{"cmd", "pkg/git"}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, "all validations succeeded")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the secret of the submodule credentials does not exist", func() {
+				submodules := build.GitSubmodulesShallow
+				buildSample.Spec.Source.Git = &build.GitSource{
+					Submodules:           &submodules,
+					SubmoduleCredentials: &corev1.LocalObjectReference{Name: "non-existing"},
+				}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceSecretRefNotFound, "referenced secret non-existing not found")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the verification references neither a secret nor a configmap", func() {
+				buildSample.Spec.Source.Git = &build.GitSource{Verify: &build.GitVerification{}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceInvalid, "the git verification must reference either a secretRef or a configMapRef")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the configmap of the verification does not exist", func() {
+				buildSample.Spec.Source.Git = &build.GitSource{Verify: &build.GitVerification{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "trusted-signers"},
+				}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceConfigMapRefNotFound, "referenced configMap trusted-signers not found")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the configmap of the verification contains no trusted keys", func() {
+				buildSample.Spec.Source.Git = &build.GitSource{Verify: &build.GitVerification{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "trusted-signers"},
+				}}
+				buildSample.Spec.Output.Credentials = nil
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.ConfigMap:
+						object.Data = map[string]string{"signers": "developer@example.com ssh-ed25519 AAAA"}
+					default:
+						return errors.NewNotFound(schema.GroupResource{}, "schema not found")
+					}
+					return nil
+				})
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceConfigMapRefNotFound, "referenced configMap trusted-signers contains neither a gpg-public-keys nor an allowed-signers key")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a sparse directory is outside of the repository", func() {
+				buildSample.Spec.Source.Git = &build.GitSource{Sparse: &[]string{"../other"}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceInvalid, "the git depth must not be negative, and the sparse directories must be inside of the repository")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when they are specified for a source bundle container", func() {
+				buildSample.Spec.Source.URL = ""
+				buildSample.Spec.Source.BundleContainer = &build.BundleContainer{Image: "quay.io/shipwright/source-bundle:latest"}
+				buildSample.Spec.Source.Git = &build.GitSource{Sparse: &[]string{}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceInvalid, "the source must not define git settings for a bundleContainer")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when sources are specified", func() {
+			It("fails when the secret of a source does not exist", func() {
+				buildSample.Spec.Sources = &[]build.BuildSource{{
+					Name:        "logo",
+					URL:         "https://shipwright.io/icons/logo.svg",
+					Credentials: &corev1.LocalObjectReference{Name: "non-existing"},
+				}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourceSecretRefNotFound, "referenced secret non-existing not found")
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the name of a source is reserved", func() {
+				buildSample.Spec.Sources = &[]build.BuildSource{{
+					Name: "default",
+					URL:  "https://shipwright.io/icons/logo.svg",
+				}}
+				buildSample.Spec.Output.Credentials = nil
+
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.SpecSourcesInvalid, `source "default" is not valid: name "default" is reserved for spec.source`)
+				statusWriter.UpdateCalls(statusCall)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).To(BeNil())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when source URL is specified", func() {
 			// validate file protocol
 			It("fails when source URL is invalid", func() {
@@ -376,7 +579,7 @@ var _ = Describe("Reconcile Build", func() {
 				buildSample.SetAnnotations(map[string]string{
 					build.AnnotationBuildVerifyRepository: "true",
 				})
-				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.RemoteRepositoryUnreachable, "invalid source url")
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.RemoteRepositoryUnreachable, fmt.Sprintf("invalid source url; referenced secret %s not found", registrySecret))
 				statusWriter.UpdateCalls(statusCall)
 
 				_, err := reconciler.Reconcile(request)
@@ -391,7 +594,7 @@ var _ = Describe("Reconcile Build", func() {
 					build.AnnotationBuildVerifyRepository: "true",
 				})
 
-				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.RemoteRepositoryUnreachable, "remote repository unreachable")
+				statusCall := ctl.StubFunc(corev1.ConditionFalse, build.RemoteRepositoryUnreachable, fmt.Sprintf("remote repository unreachable; referenced secret %s not found", registrySecret))
 				statusWriter.UpdateCalls(statusCall)
 
 				_, err := reconciler.Reconcile(request)
@@ -477,5 +680,327 @@ var _ = Describe("Reconcile Build", func() {
 				Expect(reconcile.Result{}).To(Equal(result))
 			})
 		})
+
+		Context("when param values are specified", func() {
+			JustBeforeEach(func() {
+				clusterBuildStrategySample.Spec.Parameters = []build.Parameter{
+					{Name: "build-args", Type: build.ParameterTypeArray},
+					{Name: "storage-driver", AllowedValues: []string{"overlay", "vfs"}},
+				}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.Secret:
+						secretSample = ctl.SecretWithoutAnnotation("existing", namespace)
+						secretSample.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("succeeds when the values match the strategy parameters", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{
+					{Name: "build-args", Values: []string{"GO_VERSION=1.16"}},
+					{Name: "storage-driver", Value: "vfs"},
+				}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, build.AllValidationsSucceeded))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a string value is used for an array parameter", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{Name: "build-args", Value: "GO_VERSION=1.16"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.WrongParameterValueType, "parameter build-args is of type array, its value must be specified in values"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a value is not allowed", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{Name: "storage-driver", Value: "btrfs"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.InvalidParameterValue, `value "btrfs" of parameter storage-driver is not one of the allowed values: overlay,vfs`))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when a strategy step uses a value from a Secret outside of its command, args and env", func() {
+				clusterBuildStrategySample.Spec.Parameters = append(clusterBuildStrategySample.Spec.Parameters, build.Parameter{Name: "token"})
+				clusterBuildStrategySample.Spec.BuildSteps = []build.BuildStep{{Container: corev1.Container{Name: "build", WorkingDir: "/workspace/$(params.token)"}}}
+				buildSample.Spec.ParamValues = []build.ParamValue{{
+					Name: "token",
+					ValueFrom: &build.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "existing"},
+						Key:                  "token",
+					}},
+				}}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.Secret:
+						secretSample = ctl.SecretWithoutAnnotation("existing", namespace)
+						secretSample.Data = map[string][]byte{"token": []byte("secret")}
+						secretSample.DeepCopyInto(object)
+					}
+					return nil
+				})
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.WrongParameterValueType, "parameter token references a secret, step build can only use it in its command, args and env"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when environment variables are specified", func() {
+			JustBeforeEach(func() {
+				clusterBuildStrategySample.Spec.ProtectedEnvVars = []string{"STORAGE_DRIVER"}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.Secret:
+						secretSample = ctl.SecretWithoutAnnotation("existing", namespace)
+						secretSample.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("succeeds when they are not protected by the strategy", func() {
+				buildSample.Spec.Env = []corev1.EnvVar{{Name: "GOFLAGS", Value: "-mod=vendor"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, build.AllValidationsSucceeded))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when they are protected by the strategy", func() {
+				buildSample.Spec.Env = []corev1.EnvVar{{Name: "GOFLAGS", Value: "-mod=vendor"}, {Name: "STORAGE_DRIVER", Value: "vfs"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.ProtectedEnvVarsInUse, "environment variables protected by the strategy in use: STORAGE_DRIVER"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when step resources and caches are specified", func() {
+			JustBeforeEach(func() {
+				clusterBuildStrategySample.Spec.BuildSteps = []build.BuildStep{{Container: corev1.Container{Name: "build"}}}
+				clusterBuildStrategySample.Spec.ResourceBounds = &build.ResourceBounds{
+					Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.Secret:
+						secretSample = ctl.SecretWithoutAnnotation("existing", namespace)
+						secretSample.DeepCopyInto(object)
+					}
+					return nil
+				})
+			})
+
+			It("succeeds when they are within the resource bounds of the strategy", func() {
+				buildSample.Spec.Resources = []build.StepResources{{Step: "build", ResourceRequirements: ctl.LoadCustomResources("1", "2Gi")}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, build.AllValidationsSucceeded))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when they exceed the resource bounds of the strategy", func() {
+				buildSample.Spec.Resources = []build.StepResources{{ResourceRequirements: ctl.LoadCustomResources("1", "8Gi")}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.ResourcesOutOfBounds, "memory limit 8Gi of all steps exceeds the maximum 4Gi"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the Build binds a cache that the strategy does not declare", func() {
+				buildSample.Spec.Caches = []build.BuildCache{{Name: "go-cache"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.UndefinedCache, "caches not declared by the strategy: go-cache"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when they reference an undefined step", func() {
+				buildSample.Spec.Resources = []build.StepResources{{Step: "test", ResourceRequirements: ctl.LoadCustomResources("1", "2Gi")}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.UndefinedStep, "resources defined for step test that does not exist in the strategy"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the Build sets a toleration that the strategy does not allow", func() {
+				buildSample.Spec.Scheduling = &build.Scheduling{Tolerations: []corev1.Toleration{{Key: "app", Operator: corev1.TolerationOpExists}}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.SchedulingNotAllowed, "toleration of taint app is not allowed by the strategy"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("reports all failures of the validation against the strategy", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{Name: "storage-driver", Value: "vfs"}}
+				buildSample.Spec.Resources = []build.StepResources{{ResourceRequirements: ctl.LoadCustomResources("1", "8Gi")}}
+				buildSample.Spec.Caches = []build.BuildCache{{Name: "go-cache"}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.UndefinedParameter, "parameter not defined in the strategies: storage-driver; memory limit 8Gi of all steps exceeds the maximum 4Gi; caches not declared by the strategy: go-cache"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when param values reference ConfigMaps or Secrets", func() {
+			JustBeforeEach(func() {
+				clusterBuildStrategySample.Spec.Parameters = []build.Parameter{{Name: "http-proxy"}}
+
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					case *build.ClusterBuildStrategy:
+						clusterBuildStrategySample.DeepCopyInto(object)
+					case *corev1.Secret:
+						secretSample = ctl.SecretWithoutAnnotation("existing", namespace)
+						secretSample.DeepCopyInto(object)
+					case *corev1.ConfigMap:
+						if nn.Name != "proxy-settings" {
+							return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+						}
+						object.Data = map[string]string{"http-proxy": "http://proxy:8080"}
+					}
+					return nil
+				})
+			})
+
+			It("succeeds when the referenced key exists", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{
+					Name: "http-proxy",
+					ValueFrom: &build.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-settings"},
+						Key:                  "http-proxy",
+					}},
+				}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionTrue, build.SucceedStatus, build.AllValidationsSucceeded))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the referenced ConfigMap does not exist", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{
+					Name: "http-proxy",
+					ValueFrom: &build.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "other-settings"},
+						Key:                  "http-proxy",
+					}},
+				}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.ParamValueRefNotFound, "configMap other-settings referenced by parameter http-proxy not found"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the referenced Secret key does not exist", func() {
+				buildSample.Spec.ParamValues = []build.ParamValue{{
+					Name: "http-proxy",
+					ValueFrom: &build.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "existing"},
+						Key:                  "http-proxy",
+					}},
+				}}
+
+				statusWriter.UpdateCalls(ctl.StubFunc(corev1.ConditionFalse, build.ParamValueRefNotFound, "key http-proxy of secret existing referenced by parameter http-proxy not found"))
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when multiple validations fail", func() {
+			It("reports all failures in the conditions of the Build", func() {
+				buildSample.Generation = 3
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+					default:
+						return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+					}
+					return nil
+				})
+
+				statusWriter.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					b, ok := object.(*build.Build)
+					Expect(ok).To(BeTrue())
+					Expect(b.Status.ObservedGeneration).To(Equal(int64(3)))
+
+					Expect(b.Status.GetCondition(build.BuildReady).GetStatus()).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.GetCondition(build.BuildReady).GetReason()).To(Equal(string(build.SpecOutputSecretRefNotFound)))
+					Expect(b.Status.GetCondition(build.BuildReady).GetMessage()).To(ContainSubstring(fmt.Sprintf("clusterBuildStrategy %s does not exist", buildStrategyName)))
+
+					Expect(b.Status.GetCondition(build.BuildCredentialsValid).GetStatus()).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.GetCondition(build.BuildCredentialsValid).GetReason()).To(Equal(string(build.SpecOutputSecretRefNotFound)))
+					Expect(b.Status.GetCondition(build.BuildStrategyValid).GetStatus()).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.GetCondition(build.BuildStrategyValid).GetReason()).To(Equal(string(build.ClusterBuildStrategyNotFound)))
+					Expect(b.Status.GetCondition(build.BuildSourceValid).GetStatus()).To(Equal(corev1.ConditionTrue))
+					Expect(b.Status.GetCondition(build.BuildRuntimeValid).GetStatus()).To(Equal(corev1.ConditionTrue))
+
+					Expect(b.Status.Registered).To(Equal(corev1.ConditionFalse))
+					Expect(b.Status.Reason).To(Equal(build.SpecOutputSecretRefNotFound))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+		})
 	})
 })
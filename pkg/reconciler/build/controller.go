@@ -126,59 +126,131 @@ func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler, maxCo
 		},
 	}
 
-	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+	if err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
-
 			secret := o.Object.(*corev1.Secret)
 
-			buildList := &build.BuildList{}
+			// Only enter the Reconcile space if the secret is referenced on
+			// any Build in the same namespaces
+			return referencingBuilds(ctx, mgr.GetClient(), secret.Namespace, func(b *build.Build) bool {
+				return buildReferencesSecret(b, secret.Name)
+			})
+		}),
+	}, preSecret); err != nil {
+		return err
+	}
 
-			// List all builds in the namespace of the current secret
-			if err := mgr.GetClient().List(ctx, buildList, &client.ListOptions{Namespace: secret.Namespace}); err != nil {
-				// Avoid entering into the Reconcile space
-				ctxlog.Info(ctx, "unexpected error happened while listing builds", namespace, secret.Namespace, "error", err)
-				return []reconcile.Request{}
+	preConfigMap := predicate.Funcs{
+		// Only filter updates that add or remove keys, the validation of a Build
+		// does not depend on the values of a ConfigMap
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldConfigMap, oldOK := e.ObjectOld.(*corev1.ConfigMap)
+			newConfigMap, newOK := e.ObjectNew.(*corev1.ConfigMap)
+			if !oldOK || !newOK || len(oldConfigMap.Data) != len(newConfigMap.Data) {
+				return true
 			}
 
-			if len(buildList.Items) == 0 {
-				// Avoid entering into the Reconcile space
-				return []reconcile.Request{}
+			for key := range newConfigMap.Data {
+				if _, ok := oldConfigMap.Data[key]; !ok {
+					return true
+				}
 			}
+			return false
+		},
+	}
 
-			// Only enter the Reconcile space if the secret is referenced on
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			configMap := o.Object.(*corev1.ConfigMap)
+
+			// Only enter the Reconcile space if the configmap is referenced on
 			// any Build in the same namespaces
+			return referencingBuilds(ctx, mgr.GetClient(), configMap.Namespace, func(b *build.Build) bool {
+				return buildReferencesConfigMap(b, configMap.Name)
+			})
+		}),
+	}, preConfigMap)
+}
 
-			reconcileList := []reconcile.Request{}
-			flagReconcile := false
+// referencingBuilds returns the reconcile requests for the Builds of the namespace that reference an object
+func referencingBuilds(ctx context.Context, c client.Client, ns string, references func(b *build.Build) bool) []reconcile.Request {
+	buildList := &build.BuildList{}
 
-			for _, build := range buildList.Items {
-				if build.Spec.Source.Credentials != nil {
-					if build.Spec.Source.Credentials.Name == secret.Name {
-						flagReconcile = true
-					}
-				}
-				if build.Spec.Output.Credentials != nil {
-					if build.Spec.Output.Credentials.Name == secret.Name {
-						flagReconcile = true
-					}
-				}
-				if build.Spec.Builder != nil && build.Spec.Builder.Credentials != nil {
-					if build.Spec.Builder.Credentials.Name == secret.Name {
-						flagReconcile = true
-					}
-				}
-				if flagReconcile {
-					reconcileList = append(reconcileList, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      build.Name,
-							Namespace: build.Namespace,
-						},
-					})
-				}
+	// List all builds in the namespace of the current object
+	if err := c.List(ctx, buildList, &client.ListOptions{Namespace: ns}); err != nil {
+		// Avoid entering into the Reconcile space
+		ctxlog.Info(ctx, "unexpected error happened while listing builds", namespace, ns, "error", err)
+		return []reconcile.Request{}
+	}
+
+	reconcileList := []reconcile.Request{}
+	for i := range buildList.Items {
+		if references(&buildList.Items[i]) {
+			reconcileList = append(reconcileList, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      buildList.Items[i].Name,
+					Namespace: buildList.Items[i].Namespace,
+				},
+			})
+		}
+	}
+	return reconcileList
+}
+
+// buildReferencesSecret returns whether the Build references the secret as credentials or in a parameter value
+func buildReferencesSecret(b *build.Build, secretName string) bool {
+	var names []string
+	if b.Spec.Source.Credentials != nil {
+		names = append(names, b.Spec.Source.Credentials.Name)
+	}
+	if b.Spec.Source.Git != nil && b.Spec.Source.Git.SubmoduleCredentials != nil {
+		names = append(names, b.Spec.Source.Git.SubmoduleCredentials.Name)
+	}
+	if b.Spec.Source.Git != nil && b.Spec.Source.Git.Verify != nil && b.Spec.Source.Git.Verify.SecretRef != nil {
+		names = append(names, b.Spec.Source.Git.Verify.SecretRef.Name)
+	}
+	if b.Spec.Output.Credentials != nil {
+		names = append(names, b.Spec.Output.Credentials.Name)
+	}
+	if b.Spec.Output.Signing != nil {
+		names = append(names, b.Spec.Output.Signing.SecretRef.Name)
+	}
+	if b.Spec.Builder != nil && b.Spec.Builder.Credentials != nil {
+		names = append(names, b.Spec.Builder.Credentials.Name)
+	}
+	if b.Spec.Sources != nil {
+		for _, source := range *b.Spec.Sources {
+			if source.Credentials != nil {
+				names = append(names, source.Credentials.Name)
 			}
-			return reconcileList
-		}),
-	}, preSecret)
+		}
+	}
+	for _, paramValue := range b.Spec.ParamValues {
+		if paramValue.ValueFrom != nil && paramValue.ValueFrom.SecretKeyRef != nil {
+			names = append(names, paramValue.ValueFrom.SecretKeyRef.Name)
+		}
+	}
+
+	for _, name := range names {
+		if name == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// buildReferencesConfigMap returns whether the Build references the configmap in the
+// commit verification or in a parameter value
+func buildReferencesConfigMap(b *build.Build, configMapName string) bool {
+	if b.Spec.Source.Git != nil && b.Spec.Source.Git.Verify != nil && b.Spec.Source.Git.Verify.ConfigMapRef != nil && b.Spec.Source.Git.Verify.ConfigMapRef.Name == configMapName {
+		return true
+	}
+	for _, paramValue := range b.Spec.ParamValues {
+		if paramValue.ValueFrom != nil && paramValue.ValueFrom.ConfigMapKeyRef != nil && paramValue.ValueFrom.ConfigMapKeyRef.Name == configMapName {
+			return true
+		}
+	}
+	return false
 }
 
 func buildCredentialsAnnotationExist(annotation map[string]string) (string, bool) {
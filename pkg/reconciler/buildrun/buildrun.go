@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"knative.dev/pkg/apis"
@@ -26,6 +28,7 @@ import (
 	"github.com/shipwright-io/build/pkg/ctxlog"
 	buildmetrics "github.com/shipwright-io/build/pkg/metrics"
 	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+	"github.com/shipwright-io/build/pkg/validate"
 )
 
 const (
@@ -66,8 +69,6 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 	var buildRun *buildv1alpha1.BuildRun
 	var build *buildv1alpha1.Build
 
-	updateBuildRunRequired := false
-
 	// Set the ctx to be Background, as the top-level context for incoming requests.
 	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
 	defer cancel()
@@ -90,124 +91,79 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 				return reconcile.Result{}, nil
 			}
 
-			build = &buildv1alpha1.Build{}
-			err = resources.GetBuildObject(ctx, r.client, buildRun, build)
-			if err != nil {
-				if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
-					return reconcile.Result{}, nil
-				}
-				// system call failure, reconcile again
-				return reconcile.Result{}, err
+			// A canceled BuildRun never gets a new TaskRun, an existing one needs to be canceled
+			if buildRun.IsCanceled() {
+				return r.cancelBuildRun(ctx, buildRun)
 			}
 
-			// Validate if the Build was successfully registered
-			if build.Status.Registered == "" {
-				err := fmt.Errorf("the Build is not yet validated, build: %s", build.Name)
-				// reconcile again until it gets a registration value
-				return reconcile.Result{}, err
+			// A BuildRun either references a Build, or embeds the Build spec
+			if buildRun.Spec.BuildRef != nil && buildRun.IsStandalone() {
+				message := fmt.Sprintf("BuildRun %s defines both a buildRef and a buildSpec, only one of them is supported", buildRun.Name)
+				if err := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRunAmbiguousBuild); err != nil {
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{}, nil
 			}
 
-			if build.Status.Registered != corev1.ConditionTrue {
-				// stop reconciling and mark the BuildRun as Failed
-				// we only reconcile again if the status.Update call fails
-				message := fmt.Sprintf("the Build is not registered correctly, build: %s, registered status: %s, reason: %s", build.Name, build.Status.Registered, build.Status.Reason)
-				if updateErr := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRegistrationFailed); updateErr != nil {
-					return reconcile.Result{}, updateErr
+			if buildRun.Spec.BuildRef == nil && !buildRun.IsStandalone() {
+				message := fmt.Sprintf("BuildRun %s defines neither a buildRef nor a buildSpec", buildRun.Name)
+				if err := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRunNoRefOrSpec); err != nil {
+					return reconcile.Result{}, err
 				}
-
 				return reconcile.Result{}, nil
 			}
 
-			// Ensure the build-related labels on the BuildRun
-			if buildRun.GetLabels() == nil {
-				buildRun.Labels = make(map[string]string)
+			build = &buildv1alpha1.Build{}
+			if buildRun.IsStandalone() {
+				// The embedded Build spec is validated like a Build, failures are reported on the BuildRun
+				if valid, err := r.validateEmbeddedBuild(ctx, buildRun, build); err != nil || !valid {
+					return reconcile.Result{}, err
+				}
+			} else if registered, err := r.getRegisteredBuild(ctx, buildRun, build); err != nil || !registered {
+				return reconcile.Result{}, err
 			}
 
-			// Set OwnerReference for Build and BuildRun only when build.shipwright.io/build-run-deletion is set "true"
-			if build.GetAnnotations()[buildv1alpha1.AnnotationBuildRunDeletion] == "true" && !resources.IsOwnedByBuild(build, buildRun.OwnerReferences) {
-				if err := r.setOwnerReferenceFunc(build, buildRun, r.scheme); err != nil {
-					build.Status.Reason = buildv1alpha1.SetOwnerReferenceFailed
-					build.Status.Message = fmt.Sprintf("unexpected error when trying to set the ownerreference: %v", err)
-					if err := r.client.Status().Update(ctx, build); err != nil {
+			// The output of the BuildRun overrides the one of the Build, its additional tags are validated like the ones of a Build
+			if buildRun.Spec.Output != nil {
+				if err := validate.AdditionalTags(buildRun.Spec.Output.AdditionalTags); err != nil {
+					if err := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, err.Error(), string(buildv1alpha1.InvalidAdditionalTag)); err != nil {
 						return reconcile.Result{}, err
 					}
+					return reconcile.Result{}, nil
 				}
-				ctxlog.Info(ctx, fmt.Sprintf("updating BuildRun %s OwnerReferences, owner is Build %s", buildRun.Name, build.Name), namespace, request.Namespace, name, request.Name)
-				updateBuildRunRequired = true
-			}
-
-			buildGeneration := strconv.FormatInt(build.Generation, 10)
-			if buildRun.GetLabels()[buildv1alpha1.LabelBuild] != build.Name || buildRun.GetLabels()[buildv1alpha1.LabelBuildGeneration] != buildGeneration {
-				buildRun.Labels[buildv1alpha1.LabelBuild] = build.Name
-				buildRun.Labels[buildv1alpha1.LabelBuildGeneration] = buildGeneration
-				ctxlog.Info(ctx, "updating BuildRun labels", namespace, request.Namespace, name, request.Name)
-				updateBuildRunRequired = true
-			}
-
-			if updateBuildRunRequired {
-				if err = r.client.Update(ctx, buildRun); err != nil {
-					return reconcile.Result{}, err
-				}
-				ctxlog.Info(ctx, fmt.Sprintf("successfully updated BuildRun %s", buildRun.Name), namespace, request.Namespace, name, request.Name)
 			}
 
 			// Set the Build spec in the BuildRun status
 			buildRun.Status.BuildSpec = &build.Spec
-			ctxlog.Info(ctx, "updating BuildRun status", namespace, request.Namespace, name, request.Name)
-			if err = r.client.Status().Update(ctx, buildRun); err != nil {
-				return reconcile.Result{}, err
-			}
 
-			// Choose a service account to use
-			svcAccount, err := resources.RetrieveServiceAccount(ctx, r.client, build, buildRun)
-			if err != nil {
-				if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
-					return reconcile.Result{}, nil
-				}
-				// system call failure, reconcile again
+			// Queue the BuildRun while earlier BuildRuns of the same Build did not complete,
+			// it is reconciled again once one of them completes
+			queued, superseded, err := r.applyRunPolicy(ctx, build, buildRun)
+			if err != nil || superseded {
 				return reconcile.Result{}, err
 			}
 
-			strategy, err := r.getReferencedStrategy(ctx, build, buildRun)
-			if err != nil {
-				if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
-					return reconcile.Result{}, nil
-				}
+			ctxlog.Info(ctx, "updating BuildRun status", namespace, request.Namespace, name, request.Name)
+			if err = r.client.Status().Update(ctx, buildRun); err != nil {
 				return reconcile.Result{}, err
 			}
 
-			// Create the TaskRun, this needs to be the last step in this block to be idempotent
-			generatedTaskRun, err := r.createTaskRun(ctx, svcAccount, strategy, build, buildRun)
-			if err != nil {
-				if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
-					ctxlog.Info(ctx, "taskRun generation failed", namespace, request.Namespace, name, request.Name)
-					return reconcile.Result{}, nil
-				}
-				// system call failure, reconcile again
-				return reconcile.Result{}, err
+			if queued {
+				ctxlog.Info(ctx, "buildRun is queued by the run policy of its Build", namespace, request.Namespace, name, request.Name)
+				return reconcile.Result{}, nil
 			}
 
-			ctxlog.Info(ctx, "creating TaskRun from BuildRun", namespace, request.Namespace, name, generatedTaskRun.GenerateName, "BuildRun", buildRun.Name)
-			if err = r.client.Create(ctx, generatedTaskRun); err != nil {
-				// system call failure, reconcile again
+			// Create the TaskRun, this needs to be the last step in this block to be idempotent
+			generatedTaskRun, err := r.startTaskRun(ctx, build, buildRun)
+			if err != nil || generatedTaskRun == nil {
 				return reconcile.Result{}, err
 			}
 
-			// Set the LastTaskRunRef in the BuildRun status
-			buildRun.Status.LatestTaskRunRef = &generatedTaskRun.Name
-			ctxlog.Info(ctx, "updating BuildRun status with TaskRun name", namespace, request.Namespace, name, request.Name, "TaskRun", generatedTaskRun.Name)
-			if err = r.client.Status().Update(ctx, buildRun); err != nil {
-				// we ignore the error here to prevent another reconciliation that would create another TaskRun,
-				// the LatestTaskRunRef field will also be set in the reconciliation from a TaskRun
-				// risk is that when the controller is now restarted before the field is set, another TaskRun will be created
-				ctxlog.Error(ctx, err, "Failed to update BuildRun status is ignored", namespace, request.Namespace, name, request.Name)
-			}
-
 			// Increase BuildRun count in metrics
 			buildmetrics.BuildRunCountInc(
 				buildRun.Status.BuildSpec.StrategyName(),
 				buildRun.Namespace,
-				buildRun.Spec.BuildRef.Name,
+				buildRun.BuildName(),
 				buildRun.Name,
 			)
 
@@ -215,7 +171,7 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 			buildmetrics.BuildRunRampUpDurationObserve(
 				buildRun.Status.BuildSpec.StrategyName(),
 				buildRun.Namespace,
-				buildRun.Spec.BuildRef.Name,
+				buildRun.BuildName(),
 				buildRun.Name,
 				generatedTaskRun.CreationTimestamp.Time.Sub(buildRun.CreationTimestamp.Time),
 			)
@@ -241,8 +197,33 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 			return reconcile.Result{}, nil
 		}
 
+		// The BuildRun might have been canceled while its TaskRun was created, in this case the
+		// BuildRun status is updated once the TaskRun reports its cancellation
+		if buildRun.IsCanceled() && !lastTaskRun.IsCancelled() && !lastTaskRun.IsDone() {
+			if err := r.cancelTaskRun(ctx, lastTaskRun); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, nil
+		}
+
+		// The TaskRuns of earlier attempts of a retried BuildRun do not affect the BuildRun status anymore
+		if resources.IsPreviousAttempt(buildRun, lastTaskRun.Name) {
+			ctxlog.Debug(ctx, "taskRun belongs to an earlier attempt of the buildRun", namespace, request.Namespace, name, request.Name)
+			return reconcile.Result{}, nil
+		}
+
 		trCondition := lastTaskRun.Status.GetCondition(apis.ConditionSucceeded)
 		if trCondition != nil {
+			resources.UpdateBuildRunAttempts(buildRun, lastTaskRun, trCondition)
+
+			retry, backoff, err := resources.IsRetryRequired(ctx, r.client, buildRun, lastTaskRun, trCondition)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if retry {
+				return r.retryBuildRun(ctx, buildRun, lastTaskRun, trCondition, backoff)
+			}
+
 			if err := resources.UpdateBuildRunUsingTaskRunCondition(ctx, r.client, buildRun, lastTaskRun, trCondition); err != nil {
 				return reconcile.Result{}, err
 			}
@@ -271,7 +252,7 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 				buildmetrics.BuildRunEstablishObserve(
 					buildRun.Status.BuildSpec.StrategyName(),
 					buildRun.Namespace,
-					buildRun.Spec.BuildRef.Name,
+					buildRun.BuildName(),
 					buildRun.Name,
 					buildRun.Status.StartTime.Time.Sub(buildRun.CreationTimestamp.Time),
 				)
@@ -280,11 +261,14 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 			if lastTaskRun.Status.CompletionTime != nil && buildRun.Status.CompletionTime == nil {
 				buildRun.Status.CompletionTime = lastTaskRun.Status.CompletionTime
 
+				// surface the results of the TaskRun, like the image digest, in the BuildRun status
+				resources.UpdateBuildRunUsingTaskResults(ctx, buildRun, lastTaskRun.Status.TaskRunResults)
+
 				// buildrun completion duration (total time between the creation of the buildrun and the buildrun completion)
 				buildmetrics.BuildRunCompletionObserve(
 					buildRun.Status.BuildSpec.StrategyName(),
 					buildRun.Namespace,
-					buildRun.Spec.BuildRef.Name,
+					buildRun.BuildName(),
 					buildRun.Name,
 					buildRun.Status.CompletionTime.Time.Sub(buildRun.CreationTimestamp.Time),
 				)
@@ -302,7 +286,7 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 							buildmetrics.TaskRunPodRampUpDurationObserve(
 								buildRun.Status.BuildSpec.StrategyName(),
 								buildRun.Namespace,
-								buildRun.Spec.BuildRef.Name,
+								buildRun.BuildName(),
 								buildRun.Name,
 								lastInitPod.State.Terminated.FinishedAt.Sub(pod.CreationTimestamp.Time),
 							)
@@ -313,7 +297,7 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 					buildmetrics.TaskRunRampUpDurationObserve(
 						buildRun.Status.BuildSpec.StrategyName(),
 						buildRun.Namespace,
-						buildRun.Spec.BuildRef.Name,
+						buildRun.BuildName(),
 						buildRun.Name,
 						pod.CreationTimestamp.Time.Sub(lastTaskRun.CreationTimestamp.Time),
 					)
@@ -332,6 +316,122 @@ func (r *ReconcileBuildRun) Reconcile(request reconcile.Request) (reconcile.Resu
 	return reconcile.Result{}, nil
 }
 
+// getRegisteredBuild retrieves the Build referenced by the BuildRun and ensures the build-related labels
+// and owner references on the BuildRun. It returns false if the BuildRun cannot be executed with the Build.
+func (r *ReconcileBuildRun) getRegisteredBuild(ctx context.Context, buildRun *buildv1alpha1.BuildRun, build *buildv1alpha1.Build) (bool, error) {
+	updateBuildRunRequired := false
+
+	err := resources.GetBuildObject(ctx, r.client, buildRun, build)
+	if err != nil {
+		if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
+			return false, nil
+		}
+		// system call failure, reconcile again
+		return false, err
+	}
+
+	// Validate if the Build was successfully registered, a status of an older
+	// generation of the Build does not reflect the current Build spec
+	if build.Status.Registered == "" || build.Status.ObservedGeneration < build.Generation {
+		err := fmt.Errorf("the Build is not yet validated, build: %s", build.Name)
+		// reconcile again until it gets a registration value
+		return false, err
+	}
+
+	if build.Status.Registered != corev1.ConditionTrue {
+		// stop reconciling and mark the BuildRun as Failed
+		// we only reconcile again if the status.Update call fails
+		message := fmt.Sprintf("the Build is not registered correctly, build: %s, registered status: %s, reason: %s", build.Name, build.Status.Registered, build.Status.Reason)
+		if updateErr := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRegistrationFailed); updateErr != nil {
+			return false, updateErr
+		}
+
+		return false, nil
+	}
+
+	// Ensure the build-related labels on the BuildRun
+	if buildRun.GetLabels() == nil {
+		buildRun.Labels = make(map[string]string)
+	}
+
+	// Set OwnerReference for Build and BuildRun only when build.shipwright.io/build-run-deletion is set "true"
+	if build.GetAnnotations()[buildv1alpha1.AnnotationBuildRunDeletion] == "true" && !resources.IsOwnedByBuild(build, buildRun.OwnerReferences) {
+		if err := r.setOwnerReferenceFunc(build, buildRun, r.scheme); err != nil {
+			build.Status.Reason = buildv1alpha1.SetOwnerReferenceFailed
+			build.Status.Message = fmt.Sprintf("unexpected error when trying to set the ownerreference: %v", err)
+			if err := r.client.Status().Update(ctx, build); err != nil {
+				return false, err
+			}
+		}
+		ctxlog.Info(ctx, fmt.Sprintf("updating BuildRun %s OwnerReferences, owner is Build %s", buildRun.Name, build.Name), namespace, buildRun.Namespace, name, buildRun.Name)
+		updateBuildRunRequired = true
+	}
+
+	buildGeneration := strconv.FormatInt(build.Generation, 10)
+	if buildRun.GetLabels()[buildv1alpha1.LabelBuild] != build.Name || buildRun.GetLabels()[buildv1alpha1.LabelBuildGeneration] != buildGeneration {
+		buildRun.Labels[buildv1alpha1.LabelBuild] = build.Name
+		buildRun.Labels[buildv1alpha1.LabelBuildGeneration] = buildGeneration
+		ctxlog.Info(ctx, "updating BuildRun labels", namespace, buildRun.Namespace, name, buildRun.Name)
+		updateBuildRunRequired = true
+	}
+
+	if updateBuildRunRequired {
+		if err = r.client.Update(ctx, buildRun); err != nil {
+			return false, err
+		}
+		ctxlog.Info(ctx, fmt.Sprintf("successfully updated BuildRun %s", buildRun.Name), namespace, buildRun.Namespace, name, buildRun.Name)
+	}
+
+	return true, nil
+}
+
+// validateEmbeddedBuild populates the Build from the Build spec that is embedded in a standalone BuildRun,
+// and runs the Build validations against it. A failed validation marks the BuildRun as failed.
+func (r *ReconcileBuildRun) validateEmbeddedBuild(ctx context.Context, buildRun *buildv1alpha1.BuildRun, build *buildv1alpha1.Build) (bool, error) {
+	if err := resources.GetBuildObject(ctx, r.client, buildRun, build); err != nil {
+		return false, err
+	}
+
+	// Populate the status struct with default values
+	build.Status.Reason = buildv1alpha1.SucceedStatus
+
+	// build a list of validation types that apply to an embedded Build spec
+	validationTypes := []string{
+		validate.SourceURL,
+		validate.Secrets,
+		validate.Strategies,
+		validate.Runtime,
+		validate.Sources,
+		validate.ParamValues,
+		validate.Output,
+	}
+
+	for _, validationType := range validationTypes {
+		v, err := validate.NewValidation(validationType, build, r.client, r.scheme)
+		if err != nil {
+			// when the validation type is unknown
+			return false, err
+		}
+
+		if err := v.ValidatePath(ctx); err != nil {
+			// We enqueue another reconcile here. This is done only for validation
+			// types where the error can be produced from a failed API call.
+			if validationType == validate.Secrets || validationType == validate.Strategies || validationType == validate.ParamValues {
+				return false, err
+			}
+		}
+
+		if build.Status.Reason != buildv1alpha1.SucceedStatus {
+			if err := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, build.Status.Message, string(build.Status.Reason)); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // GetBuildRunObject retrieves an existing BuildRun based on a name and namespace
 func (r *ReconcileBuildRun) GetBuildRunObject(ctx context.Context, objectName string, objectNS string, buildRun *buildv1alpha1.BuildRun) error {
 	if err := r.client.Get(ctx, types.NamespacedName{Name: objectName, Namespace: objectNS}, buildRun); err != nil {
@@ -353,7 +453,7 @@ func (r *ReconcileBuildRun) VerifyRequestName(ctx context.Context, request recon
 		if split := regxBuildRun.Split(request.Name, 2); len(split) > 0 {
 			// Update the related BuildRun
 			err := r.GetBuildRunObject(ctx, split[0], request.Namespace, buildRun)
-			if err == nil && buildRun.Status.CompletionTime == nil {
+			if err == nil && buildRun.Status.CompletionTime == nil && !resources.IsPreviousAttempt(buildRun, request.Name) {
 				// We ignore the errors from the following call, because the parent call of this function will always
 				// return back a reconcile.Result{}, nil. This is done to avoid infinite reconcile loops when a BuildRun
 				// does not longer exists
@@ -363,6 +463,186 @@ func (r *ReconcileBuildRun) VerifyRequestName(ctx context.Context, request recon
 	}
 }
 
+// cancelBuildRun propagates the cancellation of a BuildRun to its TaskRun. If there is
+// no TaskRun that could be canceled, the BuildRun is marked as canceled right away.
+func (r *ReconcileBuildRun) cancelBuildRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun) (reconcile.Result, error) {
+	if buildRun.Status.CompletionTime != nil {
+		ctxlog.Debug(ctx, "buildRun already marked completed, nothing to cancel", namespace, buildRun.Namespace, name, buildRun.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if buildRun.Status.LatestTaskRunRef != nil {
+		taskRun := &v1beta1.TaskRun{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: *buildRun.Status.LatestTaskRunRef, Namespace: buildRun.Namespace}, taskRun)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+
+		// the BuildRun status is updated once the TaskRun reports its cancellation or completion,
+		// unless the TaskRun already failed and the BuildRun waits for its retry
+		if err == nil {
+			if !taskRun.IsDone() {
+				if err := r.cancelTaskRun(ctx, taskRun); err != nil {
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{}, nil
+			}
+
+			if buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason() != resources.ConditionRetrying {
+				return reconcile.Result{}, nil
+			}
+		}
+	}
+
+	message := fmt.Sprintf("BuildRun %s was canceled", buildRun.Name)
+	if err := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRunCanceled); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// cancelTaskRun requests Tekton to cancel the given TaskRun
+func (r *ReconcileBuildRun) cancelTaskRun(ctx context.Context, taskRun *v1beta1.TaskRun) error {
+	if taskRun.IsCancelled() {
+		return nil
+	}
+
+	ctxlog.Info(ctx, "canceling TaskRun of canceled BuildRun", namespace, taskRun.Namespace, name, taskRun.Name)
+
+	original := taskRun.DeepCopy()
+	taskRun.Spec.Status = v1beta1.TaskRunSpecStatusCancelled
+	return r.client.Patch(ctx, taskRun, client.MergeFrom(original))
+}
+
+// startTaskRun creates a TaskRun for the BuildRun and references it in the BuildRun status. A nil TaskRun
+// without an error is returned when the BuildRun was marked as failed and should not be reconciled again.
+func (r *ReconcileBuildRun) startTaskRun(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (*v1beta1.TaskRun, error) {
+	// Choose a service account to use
+	svcAccount, err := resources.RetrieveServiceAccount(ctx, r.client, build, buildRun)
+	if err != nil {
+		if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
+			return nil, nil
+		}
+		// system call failure, reconcile again
+		return nil, err
+	}
+
+	strategy, err := r.getReferencedStrategy(ctx, build, buildRun)
+	if err != nil {
+		if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := resources.EnsureCacheClaims(ctx, r.client, r.scheme, strategy, build, buildRun); err != nil {
+		if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	generatedTaskRun, err := r.createTaskRun(ctx, svcAccount, strategy, build, buildRun)
+	if err != nil {
+		if !resources.IsClientStatusUpdateError(err) && buildRun.Status.IsFailed(buildv1alpha1.Succeeded) {
+			ctxlog.Info(ctx, "taskRun generation failed", namespace, buildRun.Namespace, name, buildRun.Name)
+			return nil, nil
+		}
+		// system call failure, reconcile again
+		return nil, err
+	}
+
+	ctxlog.Info(ctx, "creating TaskRun from BuildRun", namespace, buildRun.Namespace, name, generatedTaskRun.GenerateName, "BuildRun", buildRun.Name)
+	if err = r.client.Create(ctx, generatedTaskRun); err != nil {
+		// system call failure, reconcile again
+		return nil, err
+	}
+
+	// Set the LastTaskRunRef in the BuildRun status
+	buildRun.Status.LatestTaskRunRef = &generatedTaskRun.Name
+	ctxlog.Info(ctx, "updating BuildRun status with TaskRun name", namespace, buildRun.Namespace, name, buildRun.Name, "TaskRun", generatedTaskRun.Name)
+	if err = r.client.Status().Update(ctx, buildRun); err != nil {
+		// we ignore the error here to prevent another reconciliation that would create another TaskRun,
+		// the LatestTaskRunRef field will also be set in the reconciliation from a TaskRun
+		// risk is that when the controller is now restarted before the field is set, another TaskRun will be created
+		ctxlog.Error(ctx, err, "Failed to update BuildRun status is ignored", namespace, buildRun.Namespace, name, buildRun.Name)
+	}
+
+	return generatedTaskRun, nil
+}
+
+// retryBuildRun creates a new TaskRun for a BuildRun whose TaskRun failed. While the backoff
+// did not pass yet, the BuildRun is only marked as retrying and reconciled again later.
+func (r *ReconcileBuildRun) retryBuildRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, trCondition *apis.Condition, backoff time.Duration) (reconcile.Result, error) {
+	attempt := len(buildRun.Status.Attempts) + 1
+	maxAttempts := buildRun.GetRetries().Limit + 1
+
+	if backoff > 0 {
+		message := fmt.Sprintf("TaskRun %s failed with reason %s, BuildRun %s is retried in %s, attempt %d of %d", taskRun.Name, trCondition.Reason, buildRun.Name, backoff.Round(time.Second), attempt, maxAttempts)
+		resources.UpdateConditionWithUnknownStatus(buildRun, message, resources.ConditionRetrying)
+
+		ctxlog.Info(ctx, "updating BuildRun status, waiting for the retry backoff", namespace, buildRun.Namespace, name, buildRun.Name)
+		if err := r.client.Status().Update(ctx, buildRun); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{RequeueAfter: backoff}, nil
+	}
+
+	// The retry runs the Build spec of the first attempt, changes of the Build since then do not
+	// affect the BuildRun, just like they do not affect a BuildRun whose TaskRun is running
+	build := resources.GetBuildSnapshot(buildRun)
+
+	// A retry is subject to the run policy like the first attempt, for example a newer BuildRun
+	// of a Build with run policy SerialLatestOnly supersedes it
+	queued, superseded, err := r.applyRunPolicy(ctx, build, buildRun)
+	if err != nil || superseded {
+		return reconcile.Result{}, err
+	}
+
+	if queued {
+		ctxlog.Info(ctx, "retry of buildRun is queued by the run policy of its Build", namespace, buildRun.Namespace, name, buildRun.Name)
+		if err := r.client.Status().Update(ctx, buildRun); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	message := fmt.Sprintf("TaskRun %s failed with reason %s, BuildRun %s is retried, attempt %d of %d", taskRun.Name, trCondition.Reason, buildRun.Name, attempt, maxAttempts)
+	resources.UpdateConditionWithUnknownStatus(buildRun, message, resources.ConditionRetrying)
+
+	ctxlog.Info(ctx, "retrying BuildRun", namespace, buildRun.Namespace, name, buildRun.Name, "TaskRun", taskRun.Name)
+	if _, err := r.startTaskRun(ctx, build, buildRun); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// applyRunPolicy evaluates the run policy of the Build for a BuildRun that is about to start. A BuildRun
+// that is superseded by a newer BuildRun is canceled. A BuildRun that is queued behind earlier BuildRuns
+// gets a pending condition, the caller is responsible for updating its status.
+func (r *ReconcileBuildRun) applyRunPolicy(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (queued bool, superseded bool, err error) {
+	blockedBy, supersededBy, err := resources.EvaluateRunPolicy(ctx, r.client, build, buildRun)
+	if err != nil {
+		return false, false, err
+	}
+
+	if supersededBy != "" {
+		message := fmt.Sprintf("BuildRun %s was canceled, it is superseded by BuildRun %s of Build %s with run policy %s", buildRun.Name, supersededBy, build.Name, build.Spec.GetRunPolicy())
+		return false, true, resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, message, resources.ConditionBuildRunCanceled)
+	}
+
+	if len(blockedBy) > 0 {
+		message := fmt.Sprintf("BuildRun %s waits for the completion of BuildRuns %s of Build %s with run policy %s", buildRun.Name, strings.Join(blockedBy, ", "), build.Name, build.Spec.GetRunPolicy())
+		resources.UpdateConditionWithPendingStatus(buildRun, message)
+		return true, false, nil
+	}
+
+	return false, false, nil
+}
+
 func (r *ReconcileBuildRun) getReferencedStrategy(ctx context.Context, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (strategy buildv1alpha1.BuilderStrategy, err error) {
 	if build.Spec.Strategy.Kind == nil {
 		// If the strategy Kind is not specified, we default to a namespaced-scope strategy
@@ -412,7 +692,17 @@ func (r *ReconcileBuildRun) createTaskRun(ctx context.Context, serviceAccount *c
 		generatedTaskRun *v1beta1.TaskRun
 	)
 
-	generatedTaskRun, err := resources.GenerateTaskRun(r.config, build, buildRun, serviceAccount.Name, strategy)
+	// the parameter values that reference ConfigMaps are resolved in copies of the
+	// Build and BuildRun, so that the resolved values only end up in the TaskRun
+	taskRunBuild, taskRunBuildRun := build.DeepCopy(), buildRun.DeepCopy()
+	if err := resources.ResolveParamValueRefs(ctx, r.client, buildRun, taskRunBuild.Spec.ParamValues); err != nil {
+		return nil, err
+	}
+	if err := resources.ResolveParamValueRefs(ctx, r.client, buildRun, taskRunBuildRun.Spec.ParamValues); err != nil {
+		return nil, err
+	}
+
+	generatedTaskRun, err := resources.GenerateTaskRun(r.config, taskRunBuild, taskRunBuildRun, serviceAccount.Name, strategy)
 	if err != nil {
 		if updateErr := resources.UpdateConditionWithFalseStatus(ctx, r.client, buildRun, err.Error(), resources.ConditionTaskRunGenerationFailed); updateErr != nil {
 			return nil, resources.HandleError("failed to create taskrun runtime object", err, updateErr)
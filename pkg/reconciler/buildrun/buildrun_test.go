@@ -213,6 +213,153 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(client.StatusCallCount()).To(Equal(0))
 			})
 
+			It("retries a BuildRun with a new TaskRun when a step failed with a retryable exit code", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "Failed")
+				taskRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				taskRunSample.Status.Steps = []v1beta1.StepState{{
+					Name:           "step-build-and-push",
+					ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}}
+				buildRunSample.Spec.Retries = &build.Retries{Limit: 2, ExitCodes: []int32{1}}
+				buildRunSample.Status.BuildSpec = buildSample.Spec.DeepCopy()
+				buildRunSample.Status.BuildSpec.Output.Image = "registry.example.com/first-attempt"
+
+				client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *build.Build:
+						buildSample.DeepCopyInto(object)
+						return nil
+					case *build.BuildRun:
+						buildRunSample.DeepCopyInto(object)
+						return nil
+					case *v1beta1.TaskRun:
+						taskRunSample.DeepCopyInto(object)
+						return nil
+					case *corev1.ServiceAccount:
+						ctl.DefaultServiceAccount(nn.Name).DeepCopyInto(object)
+						return nil
+					case *build.ClusterBuildStrategy:
+						ctl.DefaultClusterBuildStrategy().DeepCopyInto(object)
+						return nil
+					}
+					return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+				})
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).To(BeNil())
+					Expect(buildRun.Status.Attempts).To(HaveLen(1))
+					Expect(buildRun.Status.Attempts[0].TaskRunName).To(Equal(taskRunName))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionUnknown))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionRetrying))
+					Expect(buildRun.Status.BuildSpec).ToNot(BeNil())
+					Expect(buildRun.Status.BuildSpec.Output.Image).To(Equal("registry.example.com/first-attempt"))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+				_, createdObject, _ := client.CreateArgsForCall(0)
+				Expect(createdObject).To(BeAssignableToTypeOf(&v1beta1.TaskRun{}))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("queues the retry of a BuildRun while an earlier BuildRun of a Build with a Serial run policy did not complete", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "Failed")
+				taskRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				taskRunSample.Status.Steps = []v1beta1.StepState{{
+					Name:           "step-build-and-push",
+					ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}}
+				runPolicy := build.RunPolicySerial
+				buildRunSample.Spec.Retries = &build.Retries{Limit: 2, ExitCodes: []int32{1}}
+				buildRunSample.Status.BuildSpec = buildSample.Spec.DeepCopy()
+				buildRunSample.Status.BuildSpec.RunPolicy = &runPolicy
+				buildRunSample.CreationTimestamp = metav1.Now()
+
+				earlierBuildRun := ctl.DefaultBuildRun("earlier-buildrun", buildName)
+				earlierBuildRun.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(-time.Minute))
+				earlierBuildRun.Status.LatestTaskRunRef = pointer.StringPtr("earlier-buildrun-xyz12")
+
+				client.GetCalls(ctl.StubBuildRunGetWithTaskRunAndSA(buildSample, buildRunSample, taskRunSample, ctl.DefaultServiceAccount("foobar")))
+				client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch list := object.(type) {
+					case *build.BuildRunList:
+						list.Items = []build.BuildRun{*earlierBuildRun, *buildRunSample}
+					}
+					return nil
+				})
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).To(BeNil())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionPending))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("cancels the retry of a BuildRun that a newer BuildRun of a Build with a SerialLatestOnly run policy supersedes", func() {
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionFalse, "Failed")
+				taskRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				taskRunSample.Status.Steps = []v1beta1.StepState{{
+					Name:           "step-build-and-push",
+					ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}}
+				runPolicy := build.RunPolicySerialLatestOnly
+				buildRunSample.Spec.Retries = &build.Retries{Limit: 2, ExitCodes: []int32{1}}
+				buildRunSample.Status.BuildSpec = buildSample.Spec.DeepCopy()
+				buildRunSample.Status.BuildSpec.RunPolicy = &runPolicy
+				buildRunSample.Status.LatestTaskRunRef = &taskRunName
+				buildRunSample.CreationTimestamp = metav1.Now()
+
+				newerBuildRun := ctl.DefaultBuildRun("newer-buildrun", buildName)
+				newerBuildRun.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(time.Minute))
+
+				client.GetCalls(ctl.StubBuildRunGetWithTaskRunAndSA(buildSample, buildRunSample, taskRunSample, ctl.DefaultServiceAccount("foobar")))
+				client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch list := object.(type) {
+					case *build.BuildRunList:
+						list.Items = []build.BuildRun{*buildRunSample, *newerBuildRun}
+					}
+					return nil
+				})
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).ToNot(BeNil())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionFalse))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionBuildRunCanceled))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(0))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("ignores the TaskRun of an earlier attempt of the BuildRun", func() {
+				latestTaskRunName := buildRunName + "-abc12"
+				buildRunSample.Status.LatestTaskRunRef = &latestTaskRunName
+				buildRunSample.Status.Attempts = []build.BuildRunAttempt{
+					{TaskRunName: taskRunName},
+					{TaskRunName: latestTaskRunName},
+				}
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.StatusCallCount()).To(Equal(0))
+			})
+
 			It("deletes a generated service account when the task run ends", func() {
 
 				// setup a buildrun to use a generated service account
@@ -245,6 +392,33 @@ var _ = Describe("Reconcile BuildRun", func() {
 				Expect(serviceAccount.Namespace).To(Equal(buildRunSample.Namespace))
 			})
 
+			It("surfaces the TaskRun results in the BuildRun status when the TaskRun completes", func() {
+				taskRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				taskRunSample.Status.TaskRunResults = []v1beta1.TaskRunResult{
+					{Name: "shp-source-default-commit-sha", Value: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+					{Name: "shp-image-digest", Value: "sha256:07626e3c7fdd28d5328a8d6df8d29cd3da760c7f5e2070b534f9b880ed093a53"},
+					{Name: "shp-image-size", Value: "1989004"},
+				}
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					switch buildRun := o.(type) {
+					case *build.BuildRun:
+						Expect(buildRun.Status.Output).ToNot(BeNil())
+						Expect(buildRun.Status.Output.Digest).To(Equal("sha256:07626e3c7fdd28d5328a8d6df8d29cd3da760c7f5e2070b534f9b880ed093a53"))
+						Expect(buildRun.Status.Output.Size).To(Equal(int64(1989004)))
+						Expect(buildRun.Status.Sources).To(HaveLen(1))
+						Expect(buildRun.Status.Sources[0].Name).To(Equal("default"))
+						Expect(buildRun.Status.Sources[0].Git.CommitSha).To(Equal("0e0583421a5e4bf562ffe33f3651e16ba0c78591"))
+						return nil
+					}
+					return fmt.Errorf("unexpected object %v", o)
+				})
+
+				_, err := reconciler.Reconcile(taskRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			})
+
 			It("should not panic in case the build spec strategy is nil", func() {
 				// As long as the Strategy is a pointer, it can happen that the
 				// field is nil. During processing, the BuildSpec is copied
@@ -465,6 +639,195 @@ var _ = Describe("Reconcile BuildRun", func() {
 			})
 		})
 
+		Context("from a canceled BuildRun", func() {
+			BeforeEach(func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				canceledState := build.BuildRunStateCancel
+				buildRunSample.Spec.State = &canceledState
+			})
+
+			It("marks the BuildRun as canceled without creating a TaskRun when there is none yet", func() {
+				client.GetCalls(ctl.StubBuildRun(buildRunSample))
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).ToNot(BeNil())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionFalse))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionBuildRunCanceled))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("cancels the running TaskRun of the BuildRun", func() {
+				buildRunSample.Status.LatestTaskRunRef = &taskRunName
+				taskRunSample = ctl.DefaultTaskRunWithStatus(taskRunName, buildRunName, ns, corev1.ConditionUnknown, "Running")
+
+				client.GetCalls(func(_ context.Context, nn types.NamespacedName, o runtime.Object) error {
+					switch object := o.(type) {
+					case *build.BuildRun:
+						buildRunSample.DeepCopyInto(object)
+						return nil
+					case *v1beta1.TaskRun:
+						if nn.Name == taskRunName {
+							taskRunSample.DeepCopyInto(object)
+							return nil
+						}
+					}
+					return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PatchCallCount()).To(Equal(1))
+				_, patchedObject, _, _ := client.PatchArgsForCall(0)
+				Expect(patchedObject.(*v1beta1.TaskRun).Spec.Status).To(Equal(v1beta1.TaskRunSpecStatus(v1beta1.TaskRunSpecStatusCancelled)))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("does not cancel again a BuildRun that already completed", func() {
+				buildRunSample.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				client.GetCalls(ctl.StubBuildRun(buildRunSample))
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.PatchCallCount()).To(Equal(0))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("from a standalone BuildRun", func() {
+			BeforeEach(func() {
+				buildRunRequest = newReconcileRequest(buildRunName, ns)
+
+				buildRunSample = ctl.DefaultBuildRun(buildRunName, buildName)
+				buildRunSample.Namespace = ns
+				buildRunSample.Spec.BuildRef = nil
+				buildRunSample.Spec.BuildSpec = buildSample.Spec.DeepCopy()
+				buildRunSample.Status.BuildSpec = nil
+			})
+
+			It("creates a TaskRun for a valid embedded Build spec", func() {
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					nil,
+					buildRunSample,
+					ctl.DefaultServiceAccount("default"),
+					ctl.DefaultClusterBuildStrategy(),
+					nil),
+				)
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(client.CreateCallCount()).To(Equal(1))
+
+				_, object, _ := client.CreateArgsForCall(0)
+				taskRun, ok := object.(*v1beta1.TaskRun)
+				Expect(ok).To(BeTrue())
+				Expect(taskRun.Labels).ToNot(HaveKey(build.LabelBuild))
+				Expect(taskRun.Labels[build.LabelBuildRun]).To(Equal(buildRunName))
+			})
+
+			It("fails the BuildRun when the embedded Build spec references an unknown strategy", func() {
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					nil,
+					buildRunSample,
+					ctl.DefaultServiceAccount("default"),
+					nil,
+					nil),
+				)
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).ToNot(BeNil())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionFalse))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(string(build.ClusterBuildStrategyNotFound)))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("fails the BuildRun when the embedded Build spec defines an invalid additional tag", func() {
+				buildRunSample.Spec.BuildSpec.Output.AdditionalTags = []string{"feature/$(commit-sha)"}
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					nil,
+					buildRunSample,
+					ctl.DefaultServiceAccount("default"),
+					ctl.DefaultClusterBuildStrategy(),
+					nil),
+				)
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionFalse))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(string(build.InvalidAdditionalTag)))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("fails the BuildRun when its output defines an invalid additional tag", func() {
+				buildRunSample.Spec.Output = &build.Image{
+					Image:          "quay.io/shipwright/override",
+					AdditionalTags: []string{"latest", ""},
+				}
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					nil,
+					buildRunSample,
+					ctl.DefaultServiceAccount("default"),
+					ctl.DefaultClusterBuildStrategy(),
+					nil),
+				)
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionFalse))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(string(build.InvalidAdditionalTag)))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Message).To(Equal(`the additional tag "" of the output image is invalid`))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
+			It("fails the BuildRun when it defines both a buildRef and a buildSpec", func() {
+				buildRunSample.Spec.BuildRef = &build.BuildRef{Name: buildName}
+				client.GetCalls(ctl.StubBuildRun(buildRunSample))
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionBuildRunAmbiguousBuild))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("from an existing BuildRun resource", func() {
 			var (
 				saName           string
@@ -928,6 +1291,45 @@ var _ = Describe("Reconcile BuildRun", func() {
 				_, err := reconciler.Reconcile(buildRunRequest)
 				Expect(err).ToNot(HaveOccurred())
 			})
+			It("queues the BuildRun while an earlier BuildRun of a Build with a Serial run policy did not complete", func() {
+				buildSample = ctl.DefaultBuild(buildName, strategyName, build.ClusterBuildStrategyKind)
+				runPolicy := build.RunPolicySerial
+				buildSample.Spec.RunPolicy = &runPolicy
+
+				buildRunSample.CreationTimestamp = metav1.Now()
+				earlierBuildRun := ctl.DefaultBuildRun("earlier-buildrun", buildName)
+				earlierBuildRun.CreationTimestamp = metav1.NewTime(buildRunSample.CreationTimestamp.Add(-time.Minute))
+
+				client.GetCalls(ctl.StubBuildRunGetWithSAandStrategies(
+					buildSample,
+					buildRunSample,
+					ctl.DefaultServiceAccount(saName),
+					ctl.DefaultClusterBuildStrategy(),
+					ctl.DefaultNamespacedBuildStrategy()),
+				)
+				client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+					switch list := object.(type) {
+					case *build.BuildRunList:
+						list.Items = []build.BuildRun{*earlierBuildRun, *buildRunSample}
+					}
+					return nil
+				})
+
+				statusWriter.UpdateCalls(func(_ context.Context, o runtime.Object, _ ...crc.UpdateOption) error {
+					buildRun, ok := o.(*build.BuildRun)
+					Expect(ok).To(BeTrue())
+					Expect(buildRun.Status.CompletionTime).To(BeNil())
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Status).To(Equal(corev1.ConditionUnknown))
+					Expect(buildRun.Status.GetCondition(build.Succeeded).Reason).To(Equal(resources.ConditionPending))
+					return nil
+				})
+
+				_, err := reconciler.Reconcile(buildRunRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+				Expect(client.CreateCallCount()).To(Equal(0))
+			})
+
 			It("stops creation when a FALSE registered status of the build occurs", func() {
 				// Init the Build with registered status false
 				buildSample = ctl.DefaultBuildWithFalseRegistered(buildName, strategyName, build.ClusterBuildStrategyKind)
@@ -25,6 +25,7 @@ import (
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
 )
 
 type setOwnerReferenceFunc func(owner, object metav1.Object, scheme *runtime.Scheme) error
@@ -63,6 +64,12 @@ func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler, maxCo
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// Ignore updates to CR status in which case metadata.Generation does not change
 			o := e.ObjectOld.(*buildv1alpha1.BuildRun)
+			n := e.ObjectNew.(*buildv1alpha1.BuildRun)
+
+			// Reconcile when a BuildRun that did not yet complete gets canceled
+			if !o.IsCanceled() && n.IsCanceled() && n.Status.CompletionTime == nil {
+				return true
+			}
 
 			// Avoid reconciling when for updates on the BuildRun the following takes place
 			// - the build.shipwright.io/name label is set
@@ -112,6 +119,62 @@ func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler, maxCo
 		return err
 	}
 
+	// BuildRuns that are queued by the run policy of their Build are reconciled again when
+	// another BuildRun of the same Build completes, or when a newer one might supersede them
+	predQueuedBuildRun := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			o := e.Object.(*buildv1alpha1.BuildRun)
+			return o.Status.LatestTaskRunRef == nil && o.Status.CompletionTime == nil
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*buildv1alpha1.BuildRun)
+			n := e.ObjectNew.(*buildv1alpha1.BuildRun)
+			return o.Status.CompletionTime == nil && n.Status.CompletionTime != nil
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			o := e.Object.(*buildv1alpha1.BuildRun)
+			return o.Status.CompletionTime == nil
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	if err = c.Watch(&source.Kind{Type: &buildv1alpha1.BuildRun{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+
+			buildRun := o.Object.(*buildv1alpha1.BuildRun)
+			if buildRun.Spec.BuildRef == nil || buildRun.Spec.BuildRef.Name == "" {
+				return []reconcile.Request{}
+			}
+
+			buildRunList, err := resources.ListBuildRunsOfBuild(ctx, mgr.GetClient(), buildRun.Namespace, buildRun.Spec.BuildRef.Name)
+			if err != nil {
+				// Avoid entering into the Reconcile space
+				ctxlog.Info(ctx, "unexpected error happened while listing buildruns", namespace, buildRun.Namespace, "error", err)
+				return []reconcile.Request{}
+			}
+
+			reconcileList := []reconcile.Request{}
+			for _, queued := range buildRunList.Items {
+				if queued.Name == buildRun.Name || queued.Status.LatestTaskRunRef != nil || queued.Status.CompletionTime != nil {
+					continue
+				}
+
+				reconcileList = append(reconcileList, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      queued.Name,
+						Namespace: queued.Namespace,
+					},
+				})
+			}
+
+			return reconcileList
+		}),
+	}, predQueuedBuildRun); err != nil {
+		return err
+	}
+
 	// enqueue Reconciles requests only for events where a TaskRun already exists and that is related
 	// to a BuildRun
 	return c.Watch(&source.Kind{Type: &v1beta1.TaskRun{}}, &handler.EnqueueRequestsFromMapFunc{
@@ -7,6 +7,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -15,8 +16,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// GetBuildObject retrieves an existing Build based on a name and namespace
+// GetBuildObject retrieves an existing Build based on a name and namespace. For a standalone
+// BuildRun, the Build is populated from the Build spec that is embedded in the BuildRun.
 func GetBuildObject(ctx context.Context, client client.Client, buildRun *buildv1alpha1.BuildRun, build *buildv1alpha1.Build) error {
+	if buildRun.IsStandalone() {
+		build.ObjectMeta = metav1.ObjectMeta{
+			Name:        buildRun.Name,
+			Namespace:   buildRun.Namespace,
+			Annotations: buildRun.GetAnnotations(),
+		}
+		build.Spec = *buildRun.Spec.BuildSpec.DeepCopy()
+		return nil
+	}
+
 	err := client.Get(ctx, types.NamespacedName{Name: buildRun.Spec.BuildRef.Name, Namespace: buildRun.Namespace}, build)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -31,6 +43,30 @@ func GetBuildObject(ctx context.Context, client client.Client, buildRun *buildv1
 	return err
 }
 
+// GetBuildSnapshot returns the Build as it was when the BuildRun started, with the Build spec
+// from the BuildRun status and the generation from the labels of the BuildRun
+func GetBuildSnapshot(buildRun *buildv1alpha1.BuildRun) *buildv1alpha1.Build {
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildRun.BuildName(),
+			Namespace: buildRun.Namespace,
+		},
+	}
+
+	if buildRun.IsStandalone() {
+		build.Name = buildRun.Name
+		build.Annotations = buildRun.GetAnnotations()
+	} else if generation, err := strconv.ParseInt(buildRun.GetLabels()[buildv1alpha1.LabelBuildGeneration], 10, 64); err == nil {
+		build.Generation = generation
+	}
+
+	if buildRun.Status.BuildSpec != nil {
+		build.Spec = *buildRun.Status.BuildSpec.DeepCopy()
+	}
+
+	return build
+}
+
 // IsOwnedByBuild checks if the controllerReferences contains a well known owner Kind
 func IsOwnedByBuild(build *buildv1alpha1.Build, controlledReferences []metav1.OwnerReference) bool {
 	for _, ref := range controlledReferences {
@@ -127,5 +127,17 @@ var _ = Describe("Build Resource", func() {
 			// Assert that our Build is not owned by an owner
 			Expect(resources.IsOwnedByBuild(buildSample, fakeOwnerRef)).To(BeFalse())
 		})
+		It("should return the Build as it was when the BuildRun started", func() {
+			buildSample := ctl.DefaultBuild(buildName, "foostrategy", build.ClusterBuildStrategyKind)
+			buildRunWithSpec := buildRun.DeepCopy()
+			buildRunWithSpec.Labels = map[string]string{build.LabelBuildGeneration: "3"}
+			buildRunWithSpec.Status.BuildSpec = &buildSample.Spec
+
+			snapshot := resources.GetBuildSnapshot(buildRunWithSpec)
+			Expect(snapshot.Name).To(Equal(buildName))
+			Expect(snapshot.Namespace).To(Equal("bar"))
+			Expect(snapshot.Generation).To(Equal(int64(3)))
+			Expect(snapshot.Spec).To(Equal(buildSample.Spec))
+		})
 	})
 })
@@ -0,0 +1,133 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+)
+
+// defaultCacheSize is the size of a provisioned cache claim when the strategy does not define one
+var defaultCacheSize = resource.MustParse("1Gi")
+
+// CacheClaimName returns the name of the PersistentVolumeClaim that holds a cache of the Build
+func CacheClaimName(build *buildv1alpha1.Build, cache buildv1alpha1.BuildCache) string {
+	if cache.ClaimName != "" {
+		return cache.ClaimName
+	}
+	return fmt.Sprintf("%s-cache-%s", build.Name, cache.Name)
+}
+
+// EnsureCacheClaims verifies that the PersistentVolumeClaims that the Build binds to caches
+// exist, and provisions the claims of the caches that the Build binds without a claim name.
+// The provisioned claims are owned by the Build, so that they are deleted together with it.
+// If a referenced claim does not exist, the BuildRun is marked as failed.
+func EnsureCacheClaims(ctx context.Context, client client.Client, scheme *runtime.Scheme, strategy buildv1alpha1.BuilderStrategy, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) error {
+	for _, cache := range build.Spec.Caches {
+		strategyCache := getStrategyCache(strategy, cache.Name)
+		if strategyCache == nil {
+			continue
+		}
+
+		claim := &corev1.PersistentVolumeClaim{}
+		err := client.Get(ctx, types.NamespacedName{Name: CacheClaimName(build, cache), Namespace: buildRun.Namespace}, claim)
+		if err == nil || !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		if cache.ClaimName != "" {
+			message := fmt.Sprintf("persistentVolumeClaim %s of cache %s not found", cache.ClaimName, cache.Name)
+			if updateErr := UpdateConditionWithFalseStatus(ctx, client, buildRun, message, ConditionCacheClaimNotFound); updateErr != nil {
+				return HandleError("failed to ensure cache claims", err, updateErr)
+			}
+			return err
+		}
+
+		// a BuildRun with an embedded Build spec has no Build that could own the claim,
+		// its caches are not persisted
+		if buildRun.IsStandalone() {
+			continue
+		}
+
+		claim = newCacheClaim(build, cache, strategyCache)
+		if err := controllerutil.SetControllerReference(build, claim, scheme); err != nil {
+			return err
+		}
+
+		ctxlog.Info(ctx, "provisioning cache claim", namespace, claim.Namespace, name, claim.Name, "Build", build.Name)
+		if err := client.Create(ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// amendTaskSpecWithCaches sets the PersistentVolumeClaims of the caches that the Build binds
+// as the source of the corresponding volumes, the other volumes remain emptyDirs
+func amendTaskSpecWithCaches(taskSpec *v1beta1.TaskSpec, strategy buildv1alpha1.BuilderStrategy, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) {
+	for _, cache := range build.Spec.Caches {
+		if getStrategyCache(strategy, cache.Name) == nil || (cache.ClaimName == "" && buildRun.IsStandalone()) {
+			continue
+		}
+
+		for i := range taskSpec.Volumes {
+			if taskSpec.Volumes[i].Name == cache.Name {
+				taskSpec.Volumes[i].VolumeSource = corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: CacheClaimName(build, cache),
+					},
+				}
+			}
+		}
+	}
+}
+
+func newCacheClaim(build *buildv1alpha1.Build, cache buildv1alpha1.BuildCache, strategyCache *buildv1alpha1.StrategyCache) *corev1.PersistentVolumeClaim {
+	size := defaultCacheSize
+	if strategyCache.Size != nil {
+		size = *strategyCache.Size
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CacheClaimName(build, cache),
+			Namespace: build.Namespace,
+			Labels: map[string]string{
+				buildv1alpha1.LabelBuild: build.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+}
+
+func getStrategyCache(strategy buildv1alpha1.BuilderStrategy, name string) *buildv1alpha1.StrategyCache {
+	for _, cache := range strategy.GetCaches() {
+		if cache.Name == name {
+			return &cache
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+)
+
+var _ = Describe("Caches", func() {
+	var (
+		client       *fakes.FakeClient
+		statusWriter *fakes.FakeStatusWriter
+		scheme       *runtime.Scheme
+		strategy     *buildv1alpha1.ClusterBuildStrategy
+		build        *buildv1alpha1.Build
+		buildRun     *buildv1alpha1.BuildRun
+		claims       map[string]bool
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(buildv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+		size := resource.MustParse("5Gi")
+		strategy = &buildv1alpha1.ClusterBuildStrategy{
+			Spec: buildv1alpha1.BuildStrategySpec{
+				Caches: []buildv1alpha1.StrategyCache{{Name: "buildah-images", Size: &size}, {Name: "go-cache"}},
+			},
+		}
+		build = &buildv1alpha1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "buildah-golang-build", Namespace: "default", UID: "a-uid"},
+			Spec: buildv1alpha1.BuildSpec{
+				Caches: []buildv1alpha1.BuildCache{{Name: "buildah-images"}, {Name: "go-cache", ClaimName: "shared-go-cache"}},
+			},
+		}
+		buildRun = &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "buildah-golang-buildrun", Namespace: "default"},
+			Spec:       buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: build.Name}},
+		}
+
+		claims = map[string]bool{"shared-go-cache": true}
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			if _, ok := object.(*corev1.PersistentVolumeClaim); ok && claims[nn.Name] {
+				return nil
+			}
+			return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		statusWriter = &fakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+	})
+
+	Context("when ensuring the cache claims", func() {
+		It("provisions a claim that is owned by the Build for a cache without claim name", func() {
+			Expect(resources.EnsureCacheClaims(context.TODO(), client, scheme, strategy, build, buildRun)).To(Succeed())
+			Expect(client.CreateCallCount()).To(Equal(1))
+
+			_, object, _ := client.CreateArgsForCall(0)
+			claim, ok := object.(*corev1.PersistentVolumeClaim)
+			Expect(ok).To(BeTrue())
+			Expect(claim.Name).To(Equal("buildah-golang-build-cache-buildah-images"))
+			Expect(claim.Labels[buildv1alpha1.LabelBuild]).To(Equal(build.Name))
+			Expect(claim.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("5Gi")))
+			Expect(claim.OwnerReferences).To(HaveLen(1))
+			Expect(claim.OwnerReferences[0].Name).To(Equal(build.Name))
+		})
+
+		It("does not provision a claim that already exists", func() {
+			claims["buildah-golang-build-cache-buildah-images"] = true
+
+			Expect(resources.EnsureCacheClaims(context.TODO(), client, scheme, strategy, build, buildRun)).To(Succeed())
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+
+		It("does not provision claims for a BuildRun with an embedded Build spec", func() {
+			buildRun.Spec.BuildRef = nil
+			buildRun.Spec.BuildSpec = &build.Spec
+
+			Expect(resources.EnsureCacheClaims(context.TODO(), client, scheme, strategy, build, buildRun)).To(Succeed())
+			Expect(client.CreateCallCount()).To(Equal(0))
+		})
+
+		It("fails the BuildRun when a referenced claim does not exist", func() {
+			delete(claims, "shared-go-cache")
+
+			Expect(resources.EnsureCacheClaims(context.TODO(), client, scheme, strategy, build, buildRun)).ToNot(Succeed())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason()).To(Equal(resources.ConditionCacheClaimNotFound))
+			Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetMessage()).To(Equal("persistentVolumeClaim shared-go-cache of cache go-cache not found"))
+		})
+	})
+
+	Context("when naming the cache claims", func() {
+		It("uses the claim name of the Build, or derives one from the Build name", func() {
+			Expect(resources.CacheClaimName(build, build.Spec.Caches[0])).To(Equal("buildah-golang-build-cache-buildah-images"))
+			Expect(resources.CacheClaimName(build, build.Spec.Caches[1])).To(Equal("shared-go-cache"))
+		})
+	})
+})
@@ -7,6 +7,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"knative.dev/pkg/apis"
@@ -19,6 +20,7 @@ import (
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/git"
 )
 
 // Common condition strings for reason, kind, etc.
@@ -33,6 +35,14 @@ const (
 	ConditionServiceAccountNotFound  string = "ServiceAccountNotFound"
 	ConditionBuildRegistrationFailed string = "BuildRegistrationFailed"
 	ConditionBuildNotFound           string = "BuildNotFound"
+	ConditionBuildRunCanceled        string = "BuildRunCanceled"
+	ConditionPending                 string = "Pending"
+	ConditionRetrying                string = "Retrying"
+	ConditionBuildRunAmbiguousBuild  string = "BuildRunAmbiguousBuild"
+	ConditionBuildRunNoRefOrSpec     string = "BuildRunNoRefOrSpec"
+	ConditionParamValueRefNotFound   string = "ParamValueRefNotFound"
+	ConditionCacheClaimNotFound      string = "CacheClaimNotFound"
+	ConditionCommitNotVerified       string = "CommitNotVerified"
 )
 
 // UpdateBuildRunUsingTaskRunCondition updates the BuildRun Succeeded Condition
@@ -40,6 +50,12 @@ func UpdateBuildRunUsingTaskRunCondition(ctx context.Context, client client.Clie
 	var reason, message string = trCondition.Reason, trCondition.Message
 
 	switch v1beta1.TaskRunReason(reason) {
+	case v1beta1.TaskRunReasonCancelled:
+		if buildRun.IsCanceled() {
+			reason = ConditionBuildRunCanceled
+			message = fmt.Sprintf("BuildRun %s was canceled, its TaskRun %s was canceled", buildRun.Name, taskRun.Name)
+		}
+
 	case v1beta1.TaskRunReasonTimedOut:
 		reason = "BuildRunTimeout"
 		message = fmt.Sprintf("BuildRun %s failed to finish within %s",
@@ -65,10 +81,10 @@ func UpdateBuildRunUsingTaskRunCondition(ctx context.Context, client client.Clie
 			buildRun.Status.FailedAt = &buildv1alpha1.FailedAt{Pod: pod.Name}
 
 			// Since the container status list is not sorted, as a quick workaround mark all failed containers
-			var failures = make(map[string]struct{})
+			var failures = make(map[string]int32)
 			for _, containerStatus := range pod.Status.ContainerStatuses {
 				if containerStatus.State.Terminated != nil && containerStatus.State.Terminated.ExitCode != 0 {
-					failures[containerStatus.Name] = struct{}{}
+					failures[containerStatus.Name] = containerStatus.State.Terminated.ExitCode
 				}
 			}
 
@@ -90,6 +106,18 @@ func UpdateBuildRunUsingTaskRunCondition(ctx context.Context, client client.Clie
 					pod.Name,
 					failedContainer.Name,
 				)
+
+				// the Git step of a source refuses a commit that is not signed with a trusted key
+				if strings.HasPrefix(failedContainer.Name, "step-source-") && failures[failedContainer.Name] == git.CommitVerificationFailedExitCode {
+					reason = ConditionCommitNotVerified
+					message = fmt.Sprintf("buildrun step %s failed in pod %s, because the commit is not signed with a trusted key, for detailed information: kubectl --namespace %s logs %s --container=%s",
+						failedContainer.Name,
+						pod.Name,
+						pod.Namespace,
+						pod.Name,
+						failedContainer.Name,
+					)
+				}
 			} else {
 				message = fmt.Sprintf("buildrun failed due to an unexpected error in pod %s: for detailed information: kubectl --namespace %s logs %s --all-containers",
 					pod.Name,
@@ -111,6 +139,31 @@ func UpdateBuildRunUsingTaskRunCondition(ctx context.Context, client client.Clie
 	return nil
 }
 
+// UpdateConditionWithPendingStatus sets the Succeeded condition to Status Unknown with a
+// Pending reason, this is used for BuildRuns that wait for their execution. The caller is
+// responsible for updating the object in the cluster.
+func UpdateConditionWithPendingStatus(buildRun *buildv1alpha1.BuildRun, message string) {
+	UpdateConditionWithUnknownStatus(buildRun, message, ConditionPending)
+}
+
+// UpdateConditionWithUnknownStatus sets the Succeeded condition fields and marks the condition
+// as Status Unknown, unless it is already set to the same reason and message. The caller is
+// responsible for updating the object in the cluster.
+func UpdateConditionWithUnknownStatus(buildRun *buildv1alpha1.BuildRun, message string, reason string) {
+	condition := buildRun.Status.GetCondition(buildv1alpha1.Succeeded)
+	if condition != nil && condition.Status == corev1.ConditionUnknown && condition.Reason == reason && condition.Message == message {
+		return
+	}
+
+	buildRun.Status.SetCondition(&buildv1alpha1.Condition{
+		LastTransitionTime: metav1.Now(),
+		Type:               buildv1alpha1.Succeeded,
+		Status:             corev1.ConditionUnknown,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 // UpdateConditionWithFalseStatus sets the Succeeded condition fields and mark
 // the condition as Status False. It also updates the object in the cluster by
 // calling client Status Update
@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/git"
 	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
 	"github.com/shipwright-io/build/test"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -142,6 +143,31 @@ var _ = Describe("Conditions", func() {
 			)).To(BeNil())
 		})
 
+		It("updates BuildRun condition with a dedicated reason when the TaskRun of a canceled BuildRun is cancelled", func() {
+			canceledBuildRun := ctl.DefaultBuildRun("foo", "bar")
+			canceledState := build.BuildRunStateCancel
+			canceledBuildRun.Spec.State = &canceledState
+
+			fakeTRCondition := &apis.Condition{
+				Type:    apis.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "TaskRunCancelled",
+				Message: "not relevant",
+			}
+
+			Expect(resources.UpdateBuildRunUsingTaskRunCondition(
+				context.TODO(),
+				client,
+				canceledBuildRun,
+				tr,
+				fakeTRCondition,
+			)).To(BeNil())
+
+			condition := canceledBuildRun.Status.GetCondition(build.Succeeded)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(resources.ConditionBuildRunCanceled))
+		})
+
 		It("updates BuildRun condition when TaskRun fails and pod not found", func() {
 
 			// stub a GET API call that fails with not found
@@ -263,5 +289,67 @@ var _ = Describe("Conditions", func() {
 				fakeTRCondition,
 			)).To(BeNil())
 		})
+
+		It("updates a BuildRun condition with a dedicated reason when the commit of a source is not verified", func() {
+			failedBuildRun := ctl.DefaultBuildRun("foo", "bar")
+
+			taskRunGeneratedPod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foopod",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "step-source-default",
+						},
+					},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "step-source-default",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{
+									Reason:   "Error",
+									ExitCode: git.CommitVerificationFailedExitCode,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			// stub a GET API call with taskRunGeneratedPod
+			getClientStub := func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+				switch object := object.(type) {
+				case *corev1.Pod:
+					taskRunGeneratedPod.DeepCopyInto(object)
+					return nil
+				}
+				return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+			}
+
+			// fake the calls with the above stub
+			client.GetCalls(getClientStub)
+
+			fakeTRCondition := &apis.Condition{
+				Type:    apis.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Failed",
+				Message: "not relevant",
+			}
+
+			Expect(resources.UpdateBuildRunUsingTaskRunCondition(
+				context.TODO(),
+				client,
+				failedBuildRun,
+				tr,
+				fakeTRCondition,
+			)).To(BeNil())
+
+			condition := failedBuildRun.Status.GetCondition(build.Succeeded)
+			Expect(condition.Reason).To(Equal(resources.ConditionCommitNotVerified))
+			Expect(failedBuildRun.Status.FailedAt.Container).To(Equal("step-source-default"))
+		})
 	})
 })
@@ -0,0 +1,77 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// MergeEnvVars returns the environment variables of the Build, where the ones
+// with the same name are overwritten by the environment variables of the BuildRun
+func MergeEnvVars(build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) []corev1.EnvVar {
+	return mergeEnvVars(build.Spec.Env, buildRun.Spec.Env)
+}
+
+// ProtectedEnvVarsInUse returns the names of the environment variables that
+// are protected by the strategy
+func ProtectedEnvVarsInUse(envs []corev1.EnvVar, protectedEnvVars []string) []string {
+	protected := map[string]bool{}
+	for _, name := range protectedEnvVars {
+		protected[name] = true
+	}
+
+	inUse := []string{}
+	for _, env := range envs {
+		if protected[env.Name] {
+			inUse = append(inUse, env.Name)
+		}
+	}
+	return inUse
+}
+
+// mergeEnvVars returns a new list of the base environment variables, with the
+// ones of the same name replaced by the overrides, and the remaining overrides
+// appended
+func mergeEnvVars(base []corev1.EnvVar, overrides []corev1.EnvVar) []corev1.EnvVar {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	merged := make([]corev1.EnvVar, 0, len(base)+len(overrides))
+	overridden := map[string]bool{}
+	for _, env := range base {
+		for _, override := range overrides {
+			if override.Name == env.Name {
+				env = override
+				overridden[env.Name] = true
+				break
+			}
+		}
+		merged = append(merged, env)
+	}
+
+	for _, override := range overrides {
+		if !overridden[override.Name] {
+			merged = append(merged, override)
+			overridden[override.Name] = true
+		}
+	}
+
+	return merged
+}
+
+// validateEnvVars returns an error if any of the environment variables is
+// protected by the strategy
+func validateEnvVars(envs []corev1.EnvVar, protectedEnvVars []string) error {
+	if inUse := ProtectedEnvVarsInUse(envs, protectedEnvVars); len(inUse) > 0 {
+		return fmt.Errorf("environment variables protected by the strategy in use: %s", strings.Join(inUse, ","))
+	}
+	return nil
+}
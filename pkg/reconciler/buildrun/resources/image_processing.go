@@ -0,0 +1,143 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
+)
+
+const imageProcessingStepName = "image-processing"
+
+// effectiveOutput returns the output image of the BuildRun, which overrides the one of the Build
+func effectiveOutput(build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) buildv1alpha1.Image {
+	if buildRun.Spec.Output != nil {
+		return *buildRun.Spec.Output
+	}
+	return build.Spec.Output
+}
+
+// amendTaskSpecWithImageProcessing appends a step that modifies the output image after the
+// strategy steps pushed it, and that overwrites the image digest result with the digest of
+// the modified image. The step also pushes the image under its additional tags, attaches its
+// SBOM and signs it. It is only needed if the output defines any of these.
+func amendTaskSpecWithImageProcessing(cfg *config.Config, taskSpec *v1beta1.TaskSpec, output buildv1alpha1.Image, buildRunName string) {
+	if len(output.Labels) == 0 && len(output.Annotations) == 0 && len(output.AdditionalTags) == 0 && output.SBOM == nil && output.Signing == nil {
+		return
+	}
+
+	taskSpec.Results = append(taskSpec.Results, v1beta1.TaskResult{
+		Name:        fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageTags),
+		Description: "The tags of the image",
+	})
+
+	imageProcessingStep := v1beta1.Step{
+		Container: *cfg.ImageProcessingContainerTemplate.DeepCopy(),
+	}
+
+	imageProcessingStep.Container.Name = imageProcessingStepName
+	imageProcessingStep.Container.Args = []string{
+		"--image",
+		fmt.Sprintf("$(params.%s-%s)", prefixParamsResultsVolumes, paramOutputImage),
+		"--result-file-image-digest",
+		fmt.Sprintf("$(results.%s-%s.path)", prefixParamsResultsVolumes, resultImageDigest),
+		"--result-file-image-tags",
+		fmt.Sprintf("$(results.%s-%s.path)", prefixParamsResultsVolumes, resultImageTags),
+	}
+
+	for _, key := range sortedKeys(output.Labels) {
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--label", fmt.Sprintf("%s=%s", key, output.Labels[key]))
+	}
+
+	for _, key := range sortedKeys(output.Annotations) {
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--annotation", fmt.Sprintf("%s=%s", key, output.Annotations[key]))
+	}
+
+	if len(output.AdditionalTags) > 0 {
+		for _, tag := range output.AdditionalTags {
+			imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--additional-tag", tag)
+		}
+
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--buildrun-name", buildRunName)
+
+		// the commit SHA is a result of the Git source step, a source bundle image has no commit SHA
+		commitShaResult := fmt.Sprintf("%s-source-%s-commit-sha", prefixParamsResultsVolumes, defaultSourceName)
+		for _, result := range taskSpec.Results {
+			if result.Name == commitShaResult {
+				imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--commit-sha-file", fmt.Sprintf("$(results.%s.path)", commitShaResult))
+				break
+			}
+		}
+	}
+
+	if output.Credentials != nil {
+		sources.AppendSecretVolume(taskSpec, output.Credentials.Name)
+
+		secretMountPath := fmt.Sprintf("/workspace/%s-output-secret", prefixParamsResultsVolumes)
+
+		imageProcessingStep.VolumeMounts = append(imageProcessingStep.VolumeMounts, corev1.VolumeMount{
+			Name:      sources.SanitizeVolumeNameForSecretName(output.Credentials.Name),
+			MountPath: secretMountPath,
+			ReadOnly:  true,
+		})
+
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args, "--secret-path", secretMountPath)
+	}
+
+	if output.SBOM != nil {
+		taskSpec.Results = append(taskSpec.Results, v1beta1.TaskResult{
+			Name:        fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSBOM),
+			Description: "The digest of the SBOM artifact of the image",
+		})
+
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args,
+			"--sbom-format", string(output.SBOM.Format),
+			"--result-file-image-sbom-digest", fmt.Sprintf("$(results.%s-%s.path)", prefixParamsResultsVolumes, resultImageSBOM),
+		)
+	}
+
+	if output.Signing != nil {
+		taskSpec.Results = append(taskSpec.Results, v1beta1.TaskResult{
+			Name:        fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSignature),
+			Description: "The reference of the signature of the image",
+		})
+
+		sources.AppendSecretVolume(taskSpec, output.Signing.SecretRef.Name)
+
+		signingSecretMountPath := fmt.Sprintf("/workspace/%s-signing-secret", prefixParamsResultsVolumes)
+
+		imageProcessingStep.VolumeMounts = append(imageProcessingStep.VolumeMounts, corev1.VolumeMount{
+			Name:      sources.SanitizeVolumeNameForSecretName(output.Signing.SecretRef.Name),
+			MountPath: signingSecretMountPath,
+			ReadOnly:  true,
+		})
+
+		imageProcessingStep.Container.Args = append(imageProcessingStep.Container.Args,
+			"--signing-key", path.Join(signingSecretMountPath, output.Signing.SecretRef.Key),
+			"--result-file-image-signature", fmt.Sprintf("$(results.%s-%s.path)", prefixParamsResultsVolumes, resultImageSignature),
+		)
+	}
+
+	taskSpec.Steps = append(taskSpec.Steps, imageProcessingStep)
+}
+
+// sortedKeys returns the keys of the map in a stable order, so that the generated
+// TaskRun does not change between reconciles
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,11 +5,25 @@
 package resources
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 )
 
+// paramValueEnvPrefix is the prefix of the environment variables that hold
+// the values of parameters that reference a Secret
+const paramValueEnvPrefix = "SHP_PARAM_"
+
 var systemReservedParamKeys = map[string]bool{
 	"BUILDER_IMAGE": true,
 	"DOCKERFILE":    true,
@@ -53,3 +67,215 @@ func overrideParams(originalParams []buildv1alpha1.ParamValue, overrideParams []
 func IsSystemReservedParameter(param string) bool {
 	return systemReservedParamKeys[param] || strings.HasPrefix(param, "shp-")
 }
+
+// IsSystemParameter verifies if the parameter is one that the BuildRun controller
+// passes to the steps of every strategy
+func IsSystemParameter(param string) bool {
+	switch param {
+	case inputParamBuilder,
+		inputParamDockerfile,
+		inputParamContextDir,
+		fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, paramOutputImage),
+		fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, paramSourceContext),
+		fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, paramSourceRoot):
+		return true
+	}
+	return false
+}
+
+// IsSystemResult verifies if the result is one that the BuildRun controller
+// defines for the steps of every strategy
+func IsSystemResult(result string) bool {
+	switch result {
+	case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageDigest),
+		fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSize):
+		return true
+	}
+	return false
+}
+
+// ValidateParamValue verifies that a parameter value matches the type, the allowed values and
+// the pattern of the strategy parameter. It returns the reason and a message for the first
+// mismatch, or an empty reason if the value is valid.
+func ValidateParamValue(parameter buildv1alpha1.Parameter, paramValue buildv1alpha1.ParamValue) (buildv1alpha1.BuildReason, string) {
+	if paramValue.ValueFrom != nil {
+		switch {
+		case parameter.GetType() != buildv1alpha1.ParameterTypeString:
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s is of type %s, only parameters of type string support valueFrom", paramValue.Name, parameter.GetType())
+
+		case paramValue.Value != "" || len(paramValue.Values) > 0:
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s defines both a value and valueFrom", paramValue.Name)
+
+		case (paramValue.ValueFrom.ConfigMapKeyRef == nil) == (paramValue.ValueFrom.SecretKeyRef == nil):
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s must reference either a configMapKeyRef or a secretKeyRef in valueFrom", paramValue.Name)
+		}
+
+		// the referenced value is not known here, the values of ConfigMaps are
+		// validated once they are resolved for the TaskRun
+		return "", ""
+	}
+
+	values := []string{paramValue.Value}
+
+	switch parameter.GetType() {
+	case buildv1alpha1.ParameterTypeArray:
+		if paramValue.Value != "" {
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s is of type array, its value must be specified in values", paramValue.Name)
+		}
+		values = paramValue.Values
+
+	default:
+		if len(paramValue.Values) > 0 {
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s is of type string, its value must be specified in value", paramValue.Name)
+		}
+	}
+
+	var pattern *regexp.Regexp
+	if parameter.Pattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(parameter.Pattern); err != nil {
+			return buildv1alpha1.InvalidParameterValue, fmt.Sprintf("parameter %s has an invalid pattern: %v", paramValue.Name, err)
+		}
+	}
+
+	for _, value := range values {
+		if len(parameter.AllowedValues) > 0 && !isAllowedValue(parameter.AllowedValues, value) {
+			return buildv1alpha1.InvalidParameterValue, fmt.Sprintf("value %q of parameter %s is not one of the allowed values: %s", value, paramValue.Name, strings.Join(parameter.AllowedValues, ","))
+		}
+
+		if pattern != nil && !pattern.MatchString(value) {
+			return buildv1alpha1.InvalidParameterValue, fmt.Sprintf("value %q of parameter %s does not match the pattern %s", value, paramValue.Name, parameter.Pattern)
+		}
+	}
+
+	return "", ""
+}
+
+// ValidateSecretParamValueUse verifies that the steps of a strategy use a parameter whose value
+// references a Secret only in their command, args and env. The parameter is set to a reference
+// to an environment variable, which Kubernetes does not expand in the other fields of a step.
+func ValidateSecretParamValueUse(steps []buildv1alpha1.BuildStep, paramValue buildv1alpha1.ParamValue) (buildv1alpha1.BuildReason, string) {
+	if paramValue.ValueFrom == nil || paramValue.ValueFrom.SecretKeyRef == nil {
+		return "", ""
+	}
+
+	references := []string{
+		fmt.Sprintf("$(params.%s)", paramValue.Name),
+		fmt.Sprintf("$(inputs.params.%s)", paramValue.Name),
+	}
+
+	for _, step := range steps {
+		container := step.Container.DeepCopy()
+		container.Command = nil
+		container.Args = nil
+		for i := range container.Env {
+			container.Env[i].Value = ""
+		}
+
+		data, err := json.Marshal(container)
+		if err != nil {
+			return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("failed to verify the use of parameter %s in step %s: %v", paramValue.Name, step.Name, err)
+		}
+
+		for _, reference := range references {
+			if strings.Contains(string(data), reference) {
+				return buildv1alpha1.WrongParameterValueType, fmt.Sprintf("parameter %s references a secret, step %s can only use it in its command, args and env", paramValue.Name, step.Name)
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// paramValueToArrayOrString converts a parameter value to the Tekton
+// representation that matches the type of the strategy parameter
+func paramValueToArrayOrString(parameterType buildv1alpha1.ParameterType, paramValue buildv1alpha1.ParamValue) v1beta1.ArrayOrString {
+	if parameterType == buildv1alpha1.ParameterTypeArray {
+		return v1beta1.ArrayOrString{
+			Type:     v1beta1.ParamTypeArray,
+			ArrayVal: paramValue.Values,
+		}
+	}
+
+	return v1beta1.ArrayOrString{
+		Type:      v1beta1.ParamTypeString,
+		StringVal: paramValue.Value,
+	}
+}
+
+func isAllowedValue(allowedValues []string, value string) bool {
+	for _, allowedValue := range allowedValues {
+		if allowedValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveParamValueRefs replaces the parameter values that reference a key of a ConfigMap
+// by the value of the key, the list of parameter values is modified in place. Parameter values
+// that reference a key of a Secret are kept as references, but the Secret and its key must exist.
+// If a ConfigMap, a Secret or one of their keys does not exist, the BuildRun is marked as failed.
+func ResolveParamValueRefs(ctx context.Context, client client.Client, buildRun *buildv1alpha1.BuildRun, paramValues []buildv1alpha1.ParamValue) error {
+	for i, paramValue := range paramValues {
+		if paramValue.ValueFrom == nil {
+			continue
+		}
+
+		if ref := paramValue.ValueFrom.ConfigMapKeyRef; ref != nil {
+			configMap := &corev1.ConfigMap{}
+			if err := client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: buildRun.Namespace}, configMap); err != nil {
+				return paramValueRefNotFound(ctx, client, buildRun, err, fmt.Sprintf("configMap %s referenced by parameter %s not found", ref.Name, paramValue.Name))
+			}
+
+			value, ok := configMap.Data[ref.Key]
+			if !ok {
+				return paramValueRefNotFound(ctx, client, buildRun, nil, fmt.Sprintf("key %s of configMap %s referenced by parameter %s not found", ref.Key, ref.Name, paramValue.Name))
+			}
+
+			paramValues[i] = buildv1alpha1.ParamValue{
+				Name:  paramValue.Name,
+				Value: value,
+			}
+		}
+
+		if ref := paramValue.ValueFrom.SecretKeyRef; ref != nil {
+			secret := &corev1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: buildRun.Namespace}, secret); err != nil {
+				return paramValueRefNotFound(ctx, client, buildRun, err, fmt.Sprintf("secret %s referenced by parameter %s not found", ref.Name, paramValue.Name))
+			}
+
+			if _, ok := secret.Data[ref.Key]; !ok {
+				return paramValueRefNotFound(ctx, client, buildRun, nil, fmt.Sprintf("key %s of secret %s referenced by parameter %s not found", ref.Key, ref.Name, paramValue.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// paramValueRefNotFound marks the BuildRun as failed when the referenced object (err is a
+// not found error) or its key (err is nil) does not exist. Other errors are returned as-is.
+func paramValueRefNotFound(ctx context.Context, client client.Client, buildRun *buildv1alpha1.BuildRun, err error, message string) error {
+	if err == nil {
+		err = fmt.Errorf("%s", message)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if updateErr := UpdateConditionWithFalseStatus(ctx, client, buildRun, message, ConditionParamValueRefNotFound); updateErr != nil {
+		return HandleError("failed to resolve parameter values", err, updateErr)
+	}
+	return err
+}
+
+// paramValueEnvName returns the name of the environment variable that holds the
+// value of a parameter that references a Secret
+func paramValueEnvName(param string) string {
+	return paramValueEnvPrefix + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(param))
+}
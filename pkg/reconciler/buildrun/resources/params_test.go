@@ -5,11 +5,22 @@
 package resources
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
 )
 
 var _ = Describe("Params overrides", func() {
@@ -98,3 +109,200 @@ var _ = Describe("Params overrides", func() {
 			})),
 	)
 })
+
+var _ = Describe("Param values", func() {
+
+	DescribeTable("values are validated against the strategy parameter",
+		func(parameter buildv1alpha1.Parameter, paramValue buildv1alpha1.ParamValue, expectedReason buildv1alpha1.BuildReason) {
+			reason, _ := ValidateParamValue(parameter, paramValue)
+			Expect(reason).To(Equal(expectedReason))
+		},
+
+		Entry("string value of a string parameter",
+			buildv1alpha1.Parameter{Name: "a"},
+			buildv1alpha1.ParamValue{Name: "a", Value: "1"},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("array values of a string parameter",
+			buildv1alpha1.Parameter{Name: "a", Type: buildv1alpha1.ParameterTypeString},
+			buildv1alpha1.ParamValue{Name: "a", Values: []string{"1"}},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("string value of an array parameter",
+			buildv1alpha1.Parameter{Name: "a", Type: buildv1alpha1.ParameterTypeArray},
+			buildv1alpha1.ParamValue{Name: "a", Value: "1"},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("allowed value",
+			buildv1alpha1.Parameter{Name: "a", AllowedValues: []string{"overlay", "vfs"}},
+			buildv1alpha1.ParamValue{Name: "a", Value: "vfs"},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("value that is not allowed",
+			buildv1alpha1.Parameter{Name: "a", AllowedValues: []string{"overlay", "vfs"}},
+			buildv1alpha1.ParamValue{Name: "a", Value: "btrfs"},
+			buildv1alpha1.InvalidParameterValue),
+
+		Entry("array value that is not allowed",
+			buildv1alpha1.Parameter{Name: "a", Type: buildv1alpha1.ParameterTypeArray, AllowedValues: []string{"overlay", "vfs"}},
+			buildv1alpha1.ParamValue{Name: "a", Values: []string{"vfs", "btrfs"}},
+			buildv1alpha1.InvalidParameterValue),
+
+		Entry("value that matches the pattern",
+			buildv1alpha1.Parameter{Name: "a", Pattern: "^[0-9]+$"},
+			buildv1alpha1.ParamValue{Name: "a", Value: "42"},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("value that does not match the pattern",
+			buildv1alpha1.Parameter{Name: "a", Pattern: "^[0-9]+$"},
+			buildv1alpha1.ParamValue{Name: "a", Value: "forty-two"},
+			buildv1alpha1.InvalidParameterValue),
+
+		Entry("value from a secret",
+			buildv1alpha1.Parameter{Name: "a", Pattern: "^[0-9]+$"},
+			buildv1alpha1.ParamValue{Name: "a", ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("value from for an array parameter",
+			buildv1alpha1.Parameter{Name: "a", Type: buildv1alpha1.ParameterTypeArray},
+			buildv1alpha1.ParamValue{Name: "a", ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("value and value from",
+			buildv1alpha1.Parameter{Name: "a"},
+			buildv1alpha1.ParamValue{Name: "a", Value: "1", ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("value from a configMap and a secret",
+			buildv1alpha1.Parameter{Name: "a"},
+			buildv1alpha1.ParamValue{Name: "a", ValueFrom: &buildv1alpha1.ParamValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "proxy"},
+				SecretKeyRef:    &corev1.SecretKeySelector{Key: "token"},
+			}},
+			buildv1alpha1.WrongParameterValueType),
+	)
+
+	DescribeTable("values that reference a secret are only used in the command, args and env of the steps",
+		func(container corev1.Container, expectedReason buildv1alpha1.BuildReason) {
+			paramValue := buildv1alpha1.ParamValue{Name: "token", ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}}
+			reason, _ := ValidateSecretParamValueUse([]buildv1alpha1.BuildStep{{Container: container}}, paramValue)
+			Expect(reason).To(Equal(expectedReason))
+		},
+
+		Entry("use in the command, args and env",
+			corev1.Container{
+				Name:    "build",
+				Command: []string{"$(params.token)"},
+				Args:    []string{"--token=$(inputs.params.token)"},
+				Env:     []corev1.EnvVar{{Name: "TOKEN", Value: "$(params.token)"}},
+			},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("use of another parameter in the image",
+			corev1.Container{Name: "build", Image: "$(params.token-image)"},
+			buildv1alpha1.BuildReason("")),
+
+		Entry("use in the image",
+			corev1.Container{Name: "build", Image: "registry/$(params.token)"},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("use in the working directory",
+			corev1.Container{Name: "build", WorkingDir: "$(inputs.params.token)"},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("use in a volume mount",
+			corev1.Container{Name: "build", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache", SubPath: "$(params.token)"}}},
+			buildv1alpha1.WrongParameterValueType),
+
+		Entry("use in the key of an environment variable from a secret",
+			corev1.Container{Name: "build", Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "$(params.token)"}}}}},
+			buildv1alpha1.WrongParameterValueType),
+	)
+
+	It("does not restrict the use of values that do not reference a secret", func() {
+		steps := []buildv1alpha1.BuildStep{{Container: corev1.Container{Name: "build", Image: "$(params.token)"}}}
+		reason, _ := ValidateSecretParamValueUse(steps, buildv1alpha1.ParamValue{Name: "token", Value: "image"})
+		Expect(reason).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Resolving param values", func() {
+	var (
+		client       *fakes.FakeClient
+		statusWriter *fakes.FakeStatusWriter
+		buildRun     *buildv1alpha1.BuildRun
+		paramValues  []buildv1alpha1.ParamValue
+	)
+
+	BeforeEach(func() {
+		buildRun = &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: "buildrun", Namespace: "default"}}
+		paramValues = []buildv1alpha1.ParamValue{
+			{Name: "sleep-time", Value: "1"},
+			{Name: "proxy", ValueFrom: &buildv1alpha1.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-settings"},
+				Key:                  "http-proxy",
+			}}},
+			{Name: "token", ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "registry-token"},
+				Key:                  "token",
+			}}},
+		}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn k8stypes.NamespacedName, object runtime.Object) error {
+			if configMap, ok := object.(*corev1.ConfigMap); ok && nn.Name == "proxy-settings" {
+				configMap.Data = map[string]string{"http-proxy": "http://proxy:8080"}
+				return nil
+			}
+			if secret, ok := object.(*corev1.Secret); ok && nn.Name == "registry-token" {
+				secret.Data = map[string][]byte{"token": []byte("s3cr3t")}
+				return nil
+			}
+			return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		statusWriter = &fakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+	})
+
+	It("replaces values that reference a ConfigMap and keeps values that reference a Secret", func() {
+		secretParamValue := paramValues[2]
+
+		Expect(ResolveParamValueRefs(context.TODO(), client, buildRun, paramValues)).To(Succeed())
+		Expect(paramValues).To(Equal([]buildv1alpha1.ParamValue{
+			{Name: "sleep-time", Value: "1"},
+			{Name: "proxy", Value: "http://proxy:8080"},
+			secretParamValue,
+		}))
+	})
+
+	It("fails the BuildRun when the key does not exist", func() {
+		paramValues[1].ValueFrom.ConfigMapKeyRef.Key = "https-proxy"
+
+		Expect(ResolveParamValueRefs(context.TODO(), client, buildRun, paramValues)).ToNot(Succeed())
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason()).To(Equal(ConditionParamValueRefNotFound))
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetMessage()).To(Equal("key https-proxy of configMap proxy-settings referenced by parameter proxy not found"))
+	})
+
+	It("fails the BuildRun when the ConfigMap does not exist", func() {
+		paramValues[1].ValueFrom.ConfigMapKeyRef.Name = "other-settings"
+
+		Expect(ResolveParamValueRefs(context.TODO(), client, buildRun, paramValues)).ToNot(Succeed())
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason()).To(Equal(ConditionParamValueRefNotFound))
+	})
+	It("fails the BuildRun when the key of a Secret does not exist", func() {
+		paramValues[2].ValueFrom.SecretKeyRef.Key = "password"
+
+		Expect(ResolveParamValueRefs(context.TODO(), client, buildRun, paramValues)).ToNot(Succeed())
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason()).To(Equal(ConditionParamValueRefNotFound))
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetMessage()).To(Equal("key password of secret registry-token referenced by parameter token not found"))
+	})
+
+	It("fails the BuildRun when the Secret does not exist", func() {
+		paramValues[2].ValueFrom.SecretKeyRef.Name = "other-token"
+
+		Expect(ResolveParamValueRefs(context.TODO(), client, buildRun, paramValues)).ToNot(Succeed())
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetReason()).To(Equal(ConditionParamValueRefNotFound))
+		Expect(buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetMessage()).To(Equal("secret other-token referenced by parameter token not found"))
+	})
+})
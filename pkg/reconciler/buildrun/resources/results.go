@@ -0,0 +1,71 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
+)
+
+// UpdateBuildRunUsingTaskResults surfaces the results of a TaskRun, like the
+// image digest and size or the source commit sha, in the BuildRun status
+func UpdateBuildRunUsingTaskResults(
+	ctx context.Context,
+	buildRun *buildv1alpha1.BuildRun,
+	taskRunResults []v1beta1.TaskRunResult,
+) {
+	// the source results always reflect the latest TaskRun
+	buildRun.Status.Sources = nil
+	sources.AppendGitResult(buildRun, defaultSourceName, taskRunResults)
+	sources.AppendBundleResult(buildRun, defaultSourceName, taskRunResults)
+	if buildRun.Status.BuildSpec != nil && buildRun.Status.BuildSpec.Sources != nil {
+		for _, source := range *buildRun.Status.BuildSpec.Sources {
+			sources.AppendHTTPResult(buildRun, source.Name, taskRunResults)
+		}
+	}
+
+	var output buildv1alpha1.Output
+	for _, result := range taskRunResults {
+		value := strings.TrimSpace(result.Value)
+
+		switch result.Name {
+		case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageDigest):
+			output.Digest = value
+
+		case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSize):
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				ctxlog.Info(ctx, "ignoring invalid image size result", namespace, buildRun.Namespace, name, buildRun.Name, "value", value)
+				continue
+			}
+			output.Size = size
+
+		case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageTags):
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					output.Tags = append(output.Tags, tag)
+				}
+			}
+
+		case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSignature):
+			output.Signature = value
+
+		case fmt.Sprintf("%s-%s", prefixParamsResultsVolumes, resultImageSBOM):
+			output.SBOMDigest = value
+		}
+	}
+
+	if output.Digest != "" || output.Size != 0 || len(output.Tags) > 0 || output.Signature != "" || output.SBOMDigest != "" {
+		buildRun.Status.Output = &output
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+	"github.com/shipwright-io/build/test"
+)
+
+var _ = Describe("TaskRun results to BuildRun", func() {
+
+	var (
+		ctl test.Catalog
+		br  *build.BuildRun
+	)
+
+	BeforeEach(func() {
+		br = ctl.DefaultBuildRun("foo", "bar")
+	})
+
+	Context("when the TaskRun has results", func() {
+
+		It("surfaces the image digest and size", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-image-digest", Value: "sha256:6f7e9a1d7ab0e4a5c1b3e9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8"},
+				{Name: "shp-image-size", Value: "230\n"},
+			})
+
+			Expect(br.Status.Output).ToNot(BeNil())
+			Expect(br.Status.Output.Digest).To(Equal("sha256:6f7e9a1d7ab0e4a5c1b3e9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8"))
+			Expect(br.Status.Output.Size).To(Equal(int64(230)))
+		})
+
+		It("surfaces the tags of the image", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-image-digest", Value: "sha256:6f7e9a1d7ab0e4a5c1b3e9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8"},
+				{Name: "shp-image-tags", Value: "latest,0e05834,sample-go-buildrun\n"},
+			})
+
+			Expect(br.Status.Output).ToNot(BeNil())
+			Expect(br.Status.Output.Tags).To(Equal([]string{"latest", "0e05834", "sample-go-buildrun"}))
+		})
+
+		It("surfaces the digest of the SBOM of the image", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-image-sbom-digest", Value: "sha256:9c1e7d3f5b2a4c6e8d0f1a3b5c7e9d2f4a6b8c0e1d3f5a7b9c2e4d6f8a0b1c3e"},
+			})
+
+			Expect(br.Status.Output).ToNot(BeNil())
+			Expect(br.Status.Output.SBOMDigest).To(Equal("sha256:9c1e7d3f5b2a4c6e8d0f1a3b5c7e9d2f4a6b8c0e1d3f5a7b9c2e4d6f8a0b1c3e"))
+		})
+
+		It("surfaces the signature of the image", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-image-signature", Value: "quay.io/shipwright/sample@sha256:4b3c0e9f1d2a8b7c6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d\n"},
+			})
+
+			Expect(br.Status.Output).ToNot(BeNil())
+			Expect(br.Status.Output.Signature).To(Equal("quay.io/shipwright/sample@sha256:4b3c0e9f1d2a8b7c6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d"))
+		})
+
+		It("surfaces the commit sha of the default source", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-source-default-commit-sha", Value: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+			})
+
+			Expect(br.Status.Sources).To(Equal([]build.SourceResult{
+				{
+					Name: "default",
+					Git:  &build.GitSourceResult{CommitSha: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+				},
+			}))
+			Expect(br.Status.Output).To(BeNil())
+		})
+
+		It("surfaces the commit sha and the ref of the default source", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-source-default-commit-sha", Value: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+				{Name: "shp-source-default-ref", Value: "refs/pull/1/head\n"},
+			})
+
+			Expect(br.Status.Sources).To(Equal([]build.SourceResult{
+				{
+					Name: "default",
+					Git:  &build.GitSourceResult{CommitSha: "0e0583421a5e4bf562ffe33f3651e16ba0c78591", Ref: "refs/pull/1/head"},
+				},
+			}))
+		})
+
+		It("surfaces the image digest of the default source bundle", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-source-default-image-digest", Value: "sha256:3235326357dfb65f17a9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9"},
+			})
+
+			Expect(br.Status.Sources).To(Equal([]build.SourceResult{
+				{
+					Name:   "default",
+					Bundle: &build.BundleSourceResult{Digest: "sha256:3235326357dfb65f17a9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9"},
+				},
+			}))
+		})
+
+		It("surfaces the checksum and size of the HTTP sources of the Build", func() {
+			br.Status.BuildSpec = &build.BuildSpec{
+				Sources: &[]build.BuildSource{
+					{Name: "logo", URL: "https://shipwright.io/icons/logo.svg"},
+				},
+			}
+
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-source-default-commit-sha", Value: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+				{Name: "shp-source-logo-sha256", Value: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+				{Name: "shp-source-logo-size", Value: "3261\n"},
+			})
+
+			Expect(br.Status.Sources).To(Equal([]build.SourceResult{
+				{
+					Name: "default",
+					Git:  &build.GitSourceResult{CommitSha: "0e0583421a5e4bf562ffe33f3651e16ba0c78591"},
+				},
+				{
+					Name: "logo",
+					HTTP: &build.HTTPSourceResult{SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Size: 3261},
+				},
+			}))
+		})
+
+		It("ignores an image size that is not a number", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, []v1beta1.TaskRunResult{
+				{Name: "shp-image-digest", Value: "sha256:6f7e9a1d7ab0e4a5c1b3e9b0e4f6a1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8"},
+				{Name: "shp-image-size", Value: "unknown"},
+			})
+
+			Expect(br.Status.Output).ToNot(BeNil())
+			Expect(br.Status.Output.Size).To(BeZero())
+		})
+	})
+
+	Context("when the TaskRun has no results", func() {
+
+		It("leaves the output and sources empty", func() {
+			resources.UpdateBuildRunUsingTaskResults(context.TODO(), br, nil)
+
+			Expect(br.Status.Output).To(BeNil())
+			Expect(br.Status.Sources).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,165 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"knative.dev/pkg/apis"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// maxBackoffDoublings caps the exponential growth of the retry backoff
+const maxBackoffDoublings = 10
+
+// UpdateBuildRunAttempts records the TaskRun as an attempt in the BuildRun status,
+// or updates the existing record of the TaskRun
+func UpdateBuildRunAttempts(buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, trCondition *apis.Condition) {
+	attempt := buildv1alpha1.BuildRunAttempt{
+		TaskRunName:    taskRun.Name,
+		Reason:         trCondition.Reason,
+		StartTime:      taskRun.Status.StartTime,
+		CompletionTime: taskRun.Status.CompletionTime,
+	}
+
+	for i := range buildRun.Status.Attempts {
+		if buildRun.Status.Attempts[i].TaskRunName == taskRun.Name {
+			buildRun.Status.Attempts[i] = attempt
+			return
+		}
+	}
+
+	buildRun.Status.Attempts = append(buildRun.Status.Attempts, attempt)
+}
+
+// IsPreviousAttempt returns true if the TaskRun belongs to an earlier attempt of
+// the BuildRun, and is therefore no longer relevant for the BuildRun status
+func IsPreviousAttempt(buildRun *buildv1alpha1.BuildRun, taskRunName string) bool {
+	if buildRun.Status.LatestTaskRunRef == nil || *buildRun.Status.LatestTaskRunRef == taskRunName {
+		return false
+	}
+
+	for _, attempt := range buildRun.Status.Attempts {
+		if attempt.TaskRunName == taskRunName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRetryRequired returns true if the BuildRun needs to be retried because its TaskRun failed for
+// one of the reasons or exit codes of its retries and the retry limit is not yet reached. It also returns the time that remains
+// until the retry should happen, based on the backoff and the number of earlier retries.
+func IsRetryRequired(ctx context.Context, client client.Client, buildRun *buildv1alpha1.BuildRun, taskRun *v1beta1.TaskRun, trCondition *apis.Condition) (bool, time.Duration, error) {
+	retries := buildRun.GetRetries()
+	if retries == nil || buildRun.IsCanceled() {
+		return false, 0, nil
+	}
+
+	if trCondition.Status != corev1.ConditionFalse || taskRun.Status.CompletionTime == nil || trCondition.Reason == string(v1beta1.TaskRunReasonCancelled) {
+		return false, 0, nil
+	}
+
+	if retryable, err := isRetryableFailure(ctx, client, taskRun, retries); err != nil || !retryable {
+		return false, 0, err
+	}
+
+	var retriesDone int
+	for _, attempt := range buildRun.Status.Attempts {
+		if attempt.TaskRunName != taskRun.Name {
+			retriesDone++
+		}
+	}
+
+	if retriesDone >= retries.Limit {
+		return false, 0, nil
+	}
+
+	if retries.Backoff == nil {
+		return true, 0, nil
+	}
+
+	doublings := retriesDone
+	if doublings > maxBackoffDoublings {
+		doublings = maxBackoffDoublings
+	}
+
+	retryTime := taskRun.Status.CompletionTime.Add(retries.Backoff.Duration * time.Duration(1<<uint(doublings)))
+	if remaining := time.Until(retryTime); remaining > 0 {
+		return true, remaining, nil
+	}
+
+	return true, 0, nil
+}
+
+// isRetryableFailure returns true if the reason of the pod, or of a waiting or terminated container,
+// is one of the reasons of the retries, or if the step that failed exited with one of their exit
+// codes. The states of the steps are part of the TaskRun status, since the pod of a TaskRun that
+// timed out is deleted.
+func isRetryableFailure(ctx context.Context, client client.Client, taskRun *v1beta1.TaskRun, retries *buildv1alpha1.Retries) (bool, error) {
+	reasons := map[string]struct{}{}
+	for _, reason := range retries.GetReasons() {
+		reasons[reason] = struct{}{}
+	}
+
+	// the steps after the one that failed are skipped, they also have a non-zero exit code
+	for _, step := range taskRun.Status.Steps {
+		if step.Terminated != nil && step.Terminated.ExitCode != 0 {
+			for _, exitCode := range retries.ExitCodes {
+				if step.Terminated.ExitCode == exitCode {
+					return true, nil
+				}
+			}
+			break
+		}
+	}
+
+	var states []corev1.ContainerState
+	for _, step := range taskRun.Status.Steps {
+		states = append(states, step.ContainerState)
+	}
+	for _, sidecar := range taskRun.Status.Sidecars {
+		states = append(states, sidecar.ContainerState)
+	}
+
+	if taskRun.Status.PodName != "" {
+		pod := &corev1.Pod{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: taskRun.Namespace, Name: taskRun.Status.PodName}, pod); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		} else if err == nil {
+			if _, retryable := reasons[pod.Status.Reason]; retryable && pod.Status.Reason != "" {
+				return true, nil
+			}
+
+			for _, containerStatus := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+				states = append(states, containerStatus.State)
+			}
+		}
+	}
+
+	for _, state := range states {
+		if state.Waiting != nil {
+			if _, retryable := reasons[state.Waiting.Reason]; retryable {
+				return true, nil
+			}
+		}
+		if state.Terminated != nil {
+			if _, retryable := reasons[state.Terminated.Reason]; retryable {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,255 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+	"github.com/shipwright-io/build/test"
+)
+
+var _ = Describe("BuildRun retries", func() {
+
+	var (
+		ctl         test.Catalog
+		client      *fakes.FakeClient
+		pod         *corev1.Pod
+		br          *build.BuildRun
+		tr          *v1beta1.TaskRun
+		trCondition *apis.Condition
+	)
+
+	var isRetryRequired = func() (bool, time.Duration) {
+		retry, backoff, err := resources.IsRetryRequired(context.TODO(), client, br, tr, trCondition)
+		Expect(err).ToNot(HaveOccurred())
+		return retry, backoff
+	}
+
+	// imagePullFailed lets the image of the step of the TaskRun fail to be pulled
+	var imagePullFailed = func() {
+		tr.Status.Steps = []v1beta1.StepState{{
+			Name:           "step-build",
+			ContainerState: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"}},
+		}}
+	}
+
+	// stepsFailed lets the steps of the TaskRun terminate with the exit codes
+	var stepsFailed = func(exitCodes ...int32) {
+		tr.Status.Steps = nil
+		for i, exitCode := range exitCodes {
+			tr.Status.Steps = append(tr.Status.Steps, v1beta1.StepState{
+				Name:           fmt.Sprintf("step-%d", i),
+				ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: "Error"}},
+			})
+		}
+	}
+
+	BeforeEach(func() {
+		br = ctl.DefaultBuildRun("foo", "bar")
+		tr = ctl.TaskRunWithStatus("foo-xyz12", "default")
+		tr.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		trCondition = &apis.Condition{
+			Type:   apis.ConditionSucceeded,
+			Status: corev1.ConditionFalse,
+			Reason: string(v1beta1.TaskRunReasonFailed),
+		}
+
+		pod = nil
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			if object, ok := object.(*corev1.Pod); ok && pod != nil && nn.Name == pod.Name {
+				pod.DeepCopyInto(object)
+				return nil
+			}
+			return errors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+	})
+
+	Context("when recording attempts", func() {
+
+		It("adds a new attempt for a new TaskRun and updates it afterwards", func() {
+			resources.UpdateBuildRunAttempts(br, tr, &apis.Condition{Reason: "Running"})
+			resources.UpdateBuildRunAttempts(br, tr, trCondition)
+
+			Expect(br.Status.Attempts).To(HaveLen(1))
+			Expect(br.Status.Attempts[0].TaskRunName).To(Equal(tr.Name))
+			Expect(br.Status.Attempts[0].Reason).To(Equal(string(v1beta1.TaskRunReasonFailed)))
+			Expect(br.Status.Attempts[0].CompletionTime).To(Equal(tr.Status.CompletionTime))
+		})
+
+		It("identifies the TaskRuns of earlier attempts", func() {
+			latest := "foo-abc34"
+			br.Status.Attempts = []build.BuildRunAttempt{{TaskRunName: tr.Name}, {TaskRunName: latest}}
+			br.Status.LatestTaskRunRef = &latest
+
+			Expect(resources.IsPreviousAttempt(br, tr.Name)).To(BeTrue())
+			Expect(resources.IsPreviousAttempt(br, latest)).To(BeFalse())
+			Expect(resources.IsPreviousAttempt(br, "foo-unknown")).To(BeFalse())
+		})
+	})
+
+	Context("when deciding about a retry", func() {
+
+		It("does not retry without retries", func() {
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("retries a TaskRun whose image could not be pulled right away without backoff", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			br.Status.Attempts = []build.BuildRunAttempt{{TaskRunName: tr.Name}}
+			imagePullFailed()
+
+			retry, backoff := isRetryRequired()
+			Expect(retry).To(BeTrue())
+			Expect(backoff).To(BeZero())
+		})
+
+		It("uses the retries of the Build when the BuildRun does not define them", func() {
+			br.Status.BuildSpec.Retries = &build.Retries{Limit: 1}
+			imagePullFailed()
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeTrue())
+		})
+
+		It("does not retry once the limit is reached", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			br.Status.Attempts = []build.BuildRunAttempt{{TaskRunName: "foo-abc34"}, {TaskRunName: tr.Name}}
+			imagePullFailed()
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("does not retry a TaskRun whose step failed with a non-zero exit code", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			tr.Status.Steps = []v1beta1.StepState{{
+				Name:           "step-build",
+				ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}}
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: tr.Status.PodName},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodFailed,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "step-build",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+					}},
+				},
+			}
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("does not retry a TaskRun that ran out of memory by default", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			tr.Status.Steps = []v1beta1.StepState{{
+				Name:           "step-build",
+				ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+			}}
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+
+			br.Spec.Retries.Reasons = []string{"OOMKilled"}
+			retry, _ = isRetryRequired()
+			Expect(retry).To(BeTrue())
+		})
+
+		It("only retries a TaskRun whose image could not be pulled if the reason is configured", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1, Reasons: []string{}}
+			imagePullFailed()
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("retries a TaskRun whose step failed with one of the exit codes", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1, ExitCodes: []int32{2}}
+			stepsFailed(0, 2, 1)
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeTrue())
+		})
+
+		It("only considers the exit code of the step that failed first", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1, ExitCodes: []int32{1}}
+			stepsFailed(0, 2, 1)
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("retries a TaskRun whose pod was evicted", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: tr.Status.PodName},
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			}
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeTrue())
+		})
+
+		It("retries a TaskRun that timed out while the image of a step could not be pulled", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			trCondition.Reason = string(v1beta1.TaskRunReasonTimedOut)
+			tr.Status.Steps = []v1beta1.StepState{{
+				Name:           "step-build",
+				ContainerState: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}}
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeTrue())
+		})
+
+		It("does not retry a TaskRun that timed out while its steps were running", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			trCondition.Reason = string(v1beta1.TaskRunReasonTimedOut)
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("does not retry a canceled BuildRun", func() {
+			br.Spec.Retries = &build.Retries{Limit: 1}
+			canceled := build.BuildRunStateCancel
+			br.Spec.State = &canceled
+			imagePullFailed()
+
+			retry, _ := isRetryRequired()
+			Expect(retry).To(BeFalse())
+		})
+
+		It("doubles the backoff with each retry", func() {
+			br.Spec.Retries = &build.Retries{Limit: 3, Backoff: &metav1.Duration{Duration: time.Minute}}
+			br.Status.Attempts = []build.BuildRunAttempt{{TaskRunName: "foo-abc34"}, {TaskRunName: tr.Name}}
+			imagePullFailed()
+
+			retry, backoff := isRetryRequired()
+			Expect(retry).To(BeTrue())
+			Expect(backoff).To(BeNumerically(">", time.Minute))
+			Expect(backoff).To(BeNumerically("<=", 2*time.Minute))
+		})
+	})
+})
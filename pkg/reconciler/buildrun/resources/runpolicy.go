@@ -0,0 +1,94 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// EvaluateRunPolicy checks the run policy of the Build for a BuildRun that is about to start,
+// against the other BuildRuns of the same Build. It returns the names of the earlier BuildRuns
+// that did not yet complete and block the given BuildRun from being executed. For the
+// SerialLatestOnly policy, a BuildRun that is not blocked is superseded by the newest BuildRun
+// that did not start yet, whose name is returned as well.
+func EvaluateRunPolicy(ctx context.Context, client client.Client, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) (blockedBy []string, supersededBy string, err error) {
+	runPolicy := build.Spec.GetRunPolicy()
+	if runPolicy == buildv1alpha1.RunPolicyParallel {
+		return nil, "", nil
+	}
+
+	buildRunList, err := ListBuildRunsOfBuild(ctx, client, build.Namespace, build.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var newestWaiting *buildv1alpha1.BuildRun
+	for i := range buildRunList.Items {
+		other := &buildRunList.Items[i]
+		if other.Name == buildRun.Name || !isActiveBuildRun(other) {
+			continue
+		}
+
+		if isCreatedBefore(other, buildRun) {
+			blockedBy = append(blockedBy, other.Name)
+			continue
+		}
+
+		if other.Status.LatestTaskRunRef == nil && (newestWaiting == nil || isCreatedBefore(newestWaiting, other)) {
+			newestWaiting = other
+		}
+	}
+
+	// the decision is taken once the BuildRun is no longer blocked, the newer BuildRuns that
+	// are created until then are taken into account
+	if runPolicy == buildv1alpha1.RunPolicySerialLatestOnly && len(blockedBy) == 0 && newestWaiting != nil {
+		supersededBy = newestWaiting.Name
+	}
+
+	return blockedBy, supersededBy, nil
+}
+
+// ListBuildRunsOfBuild returns the BuildRuns that reference the given Build. They are selected by
+// their buildRef rather than by the label of the Build, which is only set once a BuildRun is reconciled.
+func ListBuildRunsOfBuild(ctx context.Context, c client.Client, namespace string, buildName string) (*buildv1alpha1.BuildRunList, error) {
+	buildRunList := &buildv1alpha1.BuildRunList{}
+	if err := c.List(ctx, buildRunList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	items := buildRunList.Items[:0]
+	for _, buildRun := range buildRunList.Items {
+		if buildRun.Spec.BuildRef != nil && buildRun.Spec.BuildRef.Name == buildName {
+			items = append(items, buildRun)
+		}
+	}
+	buildRunList.Items = items
+
+	return buildRunList, nil
+}
+
+// isActiveBuildRun returns true for BuildRuns that did not yet complete. A BuildRun that got
+// canceled before it had a TaskRun is not considered active, it completes without execution.
+func isActiveBuildRun(buildRun *buildv1alpha1.BuildRun) bool {
+	if buildRun.Status.CompletionTime != nil {
+		return false
+	}
+
+	return !(buildRun.IsCanceled() && buildRun.Status.LatestTaskRunRef == nil)
+}
+
+// isCreatedBefore returns true if a was created before b, using the name to order
+// BuildRuns created within the same second
+func isCreatedBefore(a *buildv1alpha1.BuildRun, b *buildv1alpha1.BuildRun) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
@@ -0,0 +1,162 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+	"github.com/shipwright-io/build/test"
+)
+
+var _ = Describe("Build run policy", func() {
+
+	var (
+		ctl       test.Catalog
+		client    *fakes.FakeClient
+		buildObj  *build.Build
+		buildRuns []build.BuildRun
+		now       time.Time
+	)
+
+	// returns a BuildRun of the Build created at the given offset, it is not yet
+	// labeled with the name of the Build like a BuildRun that was not reconciled
+	newBuildRun := func(name string, offset time.Duration) build.BuildRun {
+		buildRun := ctl.DefaultBuildRun(name, buildObj.Name)
+		buildRun.CreationTimestamp = metav1.NewTime(now.Add(offset))
+		return *buildRun
+	}
+
+	withRunPolicy := func(runPolicy build.BuildRunPolicy) {
+		buildObj.Spec.RunPolicy = &runPolicy
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		buildObj = ctl.DefaultBuild("foobar-build", "foobar-strategy", build.ClusterBuildStrategyKind)
+		buildRuns = nil
+
+		client = &fakes.FakeClient{}
+		client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+			list, ok := object.(*build.BuildRunList)
+			Expect(ok).To(BeTrue())
+			list.Items = buildRuns
+			return nil
+		})
+	})
+
+	It("does not block any BuildRun without a run policy", func() {
+		buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute)}
+
+		blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blockedBy).To(BeEmpty())
+		Expect(supersededBy).To(BeEmpty())
+		Expect(client.ListCallCount()).To(Equal(0))
+	})
+
+	Context("with the Serial run policy", func() {
+		BeforeEach(func() {
+			withRunPolicy(build.RunPolicySerial)
+		})
+
+		It("blocks a BuildRun while an earlier BuildRun did not complete", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute)}
+
+			blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(Equal([]string{"first"}))
+			Expect(supersededBy).To(BeEmpty())
+		})
+
+		It("does not block the earliest BuildRun", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute)}
+
+			blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(BeEmpty())
+			Expect(supersededBy).To(BeEmpty())
+		})
+
+		It("does not block a BuildRun when the earlier BuildRuns completed", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute)}
+			buildRuns[0].Status.CompletionTime = &metav1.Time{Time: now}
+
+			blockedBy, _, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(BeEmpty())
+		})
+
+		It("does not block a BuildRun with the BuildRuns of other Builds", func() {
+			buildRuns = []build.BuildRun{*ctl.DefaultBuildRun("other", "other-build"), newBuildRun("second", time.Minute)}
+			buildRuns[0].CreationTimestamp = metav1.NewTime(now)
+
+			blockedBy, _, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(BeEmpty())
+		})
+	})
+
+	Context("with the SerialLatestOnly run policy", func() {
+		BeforeEach(func() {
+			withRunPolicy(build.RunPolicySerialLatestOnly)
+		})
+
+		It("does not supersede a BuildRun while it is blocked by an earlier BuildRun", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute), newBuildRun("third", 2*time.Minute)}
+			buildRuns[0].Status.LatestTaskRunRef = &buildRuns[0].Name
+
+			blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(Equal([]string{"first"}))
+			Expect(supersededBy).To(BeEmpty())
+		})
+
+		It("supersedes a BuildRun that is about to start with the newest BuildRun that did not start", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute), newBuildRun("third", 2*time.Minute), newBuildRun("fourth", 3*time.Minute)}
+			buildRuns[0].Status.LatestTaskRunRef = &buildRuns[0].Name
+			buildRuns[0].Status.CompletionTime = &metav1.Time{Time: now}
+
+			blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(BeEmpty())
+			Expect(supersededBy).To(Equal("fourth"))
+
+			// the third BuildRun waits until the second one completed, and is then superseded as well
+			blockedBy, _, err = resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[2])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(Equal([]string{"second"}))
+		})
+
+		It("starts the newest BuildRun once the superseded BuildRuns completed", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute), newBuildRun("third", 2*time.Minute)}
+			buildRuns[0].Status.CompletionTime = &metav1.Time{Time: now}
+			buildRuns[1].Status.CompletionTime = &metav1.Time{Time: now}
+
+			blockedBy, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[2])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockedBy).To(BeEmpty())
+			Expect(supersededBy).To(BeEmpty())
+		})
+
+		It("does not supersede a BuildRun with a newer BuildRun that already started", func() {
+			buildRuns = []build.BuildRun{newBuildRun("first", 0), newBuildRun("second", time.Minute)}
+			buildRuns[1].Status.LatestTaskRunRef = &buildRuns[1].Name
+
+			_, supersededBy, err := resources.EvaluateRunPolicy(context.TODO(), client, buildObj, &buildRuns[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(supersededBy).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// EffectiveScheduling returns the scheduling of a BuildRun. It starts with the defaults
+// of the strategy, which are overwritten field by field by the scheduling of the Build,
+// which again is overwritten by the scheduling of the BuildRun.
+func EffectiveScheduling(strategy buildv1alpha1.BuilderStrategy, build *buildv1alpha1.Build, buildRun *buildv1alpha1.BuildRun) *buildv1alpha1.Scheduling {
+	var result *buildv1alpha1.Scheduling
+	for _, scheduling := range []*buildv1alpha1.Scheduling{strategy.GetScheduling(), build.Spec.Scheduling, buildRun.Spec.Scheduling} {
+		if scheduling == nil {
+			continue
+		}
+
+		if result == nil {
+			result = &buildv1alpha1.Scheduling{}
+		}
+
+		if len(scheduling.NodeSelector) > 0 {
+			result.NodeSelector = scheduling.NodeSelector
+		}
+		if len(scheduling.Tolerations) > 0 {
+			result.Tolerations = scheduling.Tolerations
+		}
+		if scheduling.Affinity != nil {
+			result.Affinity = scheduling.Affinity
+		}
+		if scheduling.PriorityClassName != nil {
+			result.PriorityClassName = scheduling.PriorityClassName
+		}
+		if scheduling.RuntimeClassName != nil {
+			result.RuntimeClassName = scheduling.RuntimeClassName
+		}
+	}
+
+	return result.DeepCopy()
+}
+
+// ValidateScheduling validates that the tolerations, priority class and runtime class of the
+// scheduling of a Build or BuildRun are within the scheduling bounds of the strategy. The node
+// selector and affinity only narrow down the nodes of the pod, they are not bounded. It returns
+// a reason and a message if the validation fails.
+func ValidateScheduling(scheduling *buildv1alpha1.Scheduling, bounds *buildv1alpha1.SchedulingBounds) (buildv1alpha1.BuildReason, string) {
+	if scheduling == nil {
+		return "", ""
+	}
+
+	if bounds == nil {
+		bounds = &buildv1alpha1.SchedulingBounds{}
+	}
+
+	for i := range scheduling.Tolerations {
+		toleration := &scheduling.Tolerations[i]
+		if !isAllowedToleration(bounds.Tolerations, toleration) {
+			return buildv1alpha1.SchedulingNotAllowed, fmt.Sprintf("toleration of taint %s is not allowed by the strategy", tolerationDescription(toleration))
+		}
+	}
+
+	if scheduling.PriorityClassName != nil && !isAllowedValue(bounds.PriorityClassNames, *scheduling.PriorityClassName) {
+		return buildv1alpha1.SchedulingNotAllowed, fmt.Sprintf("priorityClassName %s is not allowed by the strategy", *scheduling.PriorityClassName)
+	}
+
+	if scheduling.RuntimeClassName != nil && !isAllowedValue(bounds.RuntimeClassNames, *scheduling.RuntimeClassName) {
+		return buildv1alpha1.SchedulingNotAllowed, fmt.Sprintf("runtimeClassName %s is not allowed by the strategy", *scheduling.RuntimeClassName)
+	}
+
+	return "", ""
+}
+
+// isAllowedToleration returns whether the key, operator, value and effect of the toleration match
+// one of the allowed tolerations, an empty operator is the default operator Equal
+func isAllowedToleration(allowedTolerations []corev1.Toleration, toleration *corev1.Toleration) bool {
+	operator := func(toleration *corev1.Toleration) corev1.TolerationOperator {
+		if toleration.Operator == "" {
+			return corev1.TolerationOpEqual
+		}
+		return toleration.Operator
+	}
+
+	for i := range allowedTolerations {
+		allowed := &allowedTolerations[i]
+		if allowed.Key == toleration.Key && operator(allowed) == operator(toleration) && allowed.Value == toleration.Value && allowed.Effect == toleration.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+// tolerationDescription returns the taint that a toleration tolerates, in the format of kubectl taint
+func tolerationDescription(toleration *corev1.Toleration) string {
+	description := toleration.Key
+	if toleration.Operator != corev1.TolerationOpExists && toleration.Value != "" {
+		description += "=" + toleration.Value
+	}
+	if toleration.Effect != "" {
+		description += ":" + string(toleration.Effect)
+	}
+	if description == "" {
+		description = "*"
+	}
+	return description
+}
+
+// podTemplate translates the scheduling into the pod template of a TaskRun
+func podTemplate(scheduling *buildv1alpha1.Scheduling) *v1beta1.PodTemplate {
+	if scheduling == nil {
+		return nil
+	}
+
+	return &v1beta1.PodTemplate{
+		NodeSelector:      scheduling.NodeSelector,
+		Tolerations:       scheduling.Tolerations,
+		Affinity:          scheduling.Affinity,
+		PriorityClassName: scheduling.PriorityClassName,
+		RuntimeClassName:  scheduling.RuntimeClassName,
+	}
+}
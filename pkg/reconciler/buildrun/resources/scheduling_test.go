@@ -0,0 +1,134 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+)
+
+var _ = Describe("EffectiveScheduling", func() {
+	var (
+		strategy *buildv1alpha1.ClusterBuildStrategy
+		build    *buildv1alpha1.Build
+		buildRun *buildv1alpha1.BuildRun
+	)
+
+	name := func(value string) *string {
+		return &value
+	}
+
+	BeforeEach(func() {
+		strategy = &buildv1alpha1.ClusterBuildStrategy{}
+		build = &buildv1alpha1.Build{}
+		buildRun = &buildv1alpha1.BuildRun{}
+	})
+
+	It("returns nil when no scheduling is defined", func() {
+		Expect(resources.EffectiveScheduling(strategy, build, buildRun)).To(BeNil())
+	})
+
+	It("overwrites the strategy defaults field by field", func() {
+		strategy.Spec.Scheduling = &buildv1alpha1.Scheduling{
+			NodeSelector:      map[string]string{"node-role.kubernetes.io/build": ""},
+			Tolerations:       []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists}},
+			PriorityClassName: name("build-default"),
+		}
+		build.Spec.Scheduling = &buildv1alpha1.Scheduling{
+			NodeSelector:     map[string]string{"kubernetes.io/arch": "arm64"},
+			RuntimeClassName: name("gvisor"),
+		}
+		buildRun.Spec.Scheduling = &buildv1alpha1.Scheduling{
+			PriorityClassName: name("build-urgent"),
+		}
+
+		Expect(resources.EffectiveScheduling(strategy, build, buildRun)).To(Equal(&buildv1alpha1.Scheduling{
+			NodeSelector:      map[string]string{"kubernetes.io/arch": "arm64"},
+			Tolerations:       []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists}},
+			PriorityClassName: name("build-urgent"),
+			RuntimeClassName:  name("gvisor"),
+		}))
+	})
+
+	It("does not share data with the strategy, Build and BuildRun", func() {
+		build.Spec.Scheduling = &buildv1alpha1.Scheduling{NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}}
+
+		scheduling := resources.EffectiveScheduling(strategy, build, buildRun)
+		scheduling.NodeSelector["kubernetes.io/arch"] = "amd64"
+
+		Expect(build.Spec.Scheduling.NodeSelector).To(Equal(map[string]string{"kubernetes.io/arch": "arm64"}))
+	})
+})
+
+var _ = Describe("ValidateScheduling", func() {
+	name := func(value string) *string {
+		return &value
+	}
+
+	bounds := &buildv1alpha1.SchedulingBounds{
+		Tolerations: []corev1.Toleration{
+			{Key: "build", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			{Key: "arch", Value: "arm64"},
+		},
+		PriorityClassNames: []string{"build-urgent"},
+		RuntimeClassNames:  []string{"gvisor"},
+	}
+
+	DescribeTable("the tolerations, priority class and runtime class are validated against the bounds of the strategy",
+		func(scheduling *buildv1alpha1.Scheduling, bounds *buildv1alpha1.SchedulingBounds, expectedMessage string) {
+			reason, message := resources.ValidateScheduling(scheduling, bounds)
+			Expect(message).To(Equal(expectedMessage))
+			if expectedMessage == "" {
+				Expect(reason).To(BeEmpty())
+			} else {
+				Expect(reason).To(Equal(buildv1alpha1.SchedulingNotAllowed))
+			}
+		},
+
+		Entry("no scheduling",
+			nil, nil, ""),
+
+		Entry("node selector and affinity without bounds",
+			&buildv1alpha1.Scheduling{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+				Affinity:     &corev1.Affinity{},
+			}, nil, ""),
+
+		Entry("allowed settings",
+			&buildv1alpha1.Scheduling{
+				Tolerations: []corev1.Toleration{
+					{Key: "arch", Operator: corev1.TolerationOpEqual, Value: "arm64"},
+					{Key: "build", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+				PriorityClassName: name("build-urgent"),
+				RuntimeClassName:  name("gvisor"),
+			}, bounds, ""),
+
+		Entry("toleration without bounds",
+			&buildv1alpha1.Scheduling{Tolerations: []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists}}},
+			nil, "toleration of taint build is not allowed by the strategy"),
+
+		Entry("toleration of another effect",
+			&buildv1alpha1.Scheduling{Tolerations: []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}}},
+			bounds, "toleration of taint build:NoExecute is not allowed by the strategy"),
+
+		Entry("toleration of all taints",
+			&buildv1alpha1.Scheduling{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+			bounds, "toleration of taint * is not allowed by the strategy"),
+
+		Entry("priority class that is not allowed",
+			&buildv1alpha1.Scheduling{PriorityClassName: name("system-node-critical")},
+			bounds, "priorityClassName system-node-critical is not allowed by the strategy"),
+
+		Entry("runtime class that is not allowed",
+			&buildv1alpha1.Scheduling{RuntimeClassName: name("runc")},
+			bounds, "runtimeClassName runc is not allowed by the strategy"),
+	)
+})
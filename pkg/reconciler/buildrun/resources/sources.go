@@ -11,16 +11,22 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 )
 
+const defaultSourceName = "default"
+
 // AmendTaskSpecWithSources adds steps, results and volumes for spec.source and spec.sources
 func AmendTaskSpecWithSources(
 	cfg *config.Config,
 	taskSpec *v1beta1.TaskSpec,
 	build *buildv1alpha1.Build,
 ) {
-	// create the step for spec.source, this is always Git
-	sources.AppendGitStep(cfg, taskSpec, build.Spec.Source, "default")
+	// create the step for spec.source, which is either a source bundle image or Git
+	if build.Spec.Source.BundleContainer != nil {
+		sources.AppendBundleStep(cfg, taskSpec, build.Spec.Source, defaultSourceName)
+	} else {
+		sources.AppendGitStep(cfg, taskSpec, build.Spec.Source, defaultSourceName)
+	}
 
-	// create the step for spec.sources, this will eventually change into different steps depending on the type of the source
+	// create a step for each entry of spec.sources
 	if build.Spec.Sources != nil {
 		for _, source := range *build.Spec.Sources {
 			// today, we only have HTTP sources
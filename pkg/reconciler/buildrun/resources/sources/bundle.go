@@ -0,0 +1,87 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sources
+
+import (
+	"fmt"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AppendBundleStep appends the step that pulls a source bundle image, and its results and volume if needed, to the TaskSpec
+func AppendBundleStep(
+	cfg *config.Config,
+	taskSpec *tektonv1beta1.TaskSpec,
+	source buildv1alpha1.Source,
+	name string,
+) {
+	// append the result
+	taskSpec.Results = append(taskSpec.Results, tektonv1beta1.TaskResult{
+		Name:        fmt.Sprintf("%s-source-%s-image-digest", prefixParamsResultsVolumes, name),
+		Description: "The digest of the source bundle image.",
+	})
+
+	// initialize the step from the template
+	bundleStep := tektonv1beta1.Step{
+		Container: *cfg.BundleContainerTemplate.DeepCopy(),
+	}
+
+	// add the build-specific details
+	bundleStep.Container.Name = fmt.Sprintf("source-%s", name)
+	bundleStep.Container.Args = []string{
+		"--image",
+		source.BundleContainer.Image,
+		"--target",
+		fmt.Sprintf("$(params.%s-%s)", prefixParamsResultsVolumes, paramSourceRoot),
+		"--result-file-image-digest",
+		fmt.Sprintf("$(results.%s-source-%s-image-digest.path)", prefixParamsResultsVolumes, name),
+	}
+
+	if source.Credentials != nil {
+		// ensure the value is there
+		AppendSecretVolume(taskSpec, source.Credentials.Name)
+
+		secretMountPath := fmt.Sprintf("/workspace/%s-source-secret", prefixParamsResultsVolumes)
+
+		// define the volume mount on the container
+		bundleStep.VolumeMounts = append(bundleStep.VolumeMounts, corev1.VolumeMount{
+			Name:      SanitizeVolumeNameForSecretName(source.Credentials.Name),
+			MountPath: secretMountPath,
+			ReadOnly:  true,
+		})
+
+		// append the argument
+		bundleStep.Container.Args = append(
+			bundleStep.Container.Args,
+			"--secret-path",
+			secretMountPath,
+		)
+	}
+
+	// append the bundle step
+	taskSpec.Steps = append(taskSpec.Steps, bundleStep)
+}
+
+// AppendBundleResult appends the results of the bundle step of a source to the BuildRun status
+func AppendBundleResult(
+	buildRun *buildv1alpha1.BuildRun,
+	name string,
+	results []tektonv1beta1.TaskRunResult,
+) {
+	imageDigest := findResultValue(results, fmt.Sprintf("%s-source-%s-image-digest", prefixParamsResultsVolumes, name))
+
+	if strings.TrimSpace(imageDigest) != "" {
+		buildRun.Status.Sources = append(buildRun.Status.Sources, buildv1alpha1.SourceResult{
+			Name: name,
+			Bundle: &buildv1alpha1.BundleSourceResult{
+				Digest: strings.TrimSpace(imageDigest),
+			},
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sources_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
+
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Bundle", func() {
+
+	cfg := config.NewDefaultConfig()
+
+	Context("when adding a public source bundle image", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+		})
+
+		JustBeforeEach(func() {
+			sources.AppendBundleStep(cfg, taskSpec, buildv1alpha1.Source{
+				BundleContainer: &buildv1alpha1.BundleContainer{
+					Image: "quay.io/shipwright/source-bundle:latest",
+				},
+			}, "default")
+		})
+
+		It("adds a result for the image digest", func() {
+			Expect(len(taskSpec.Results)).To(Equal(1))
+			Expect(taskSpec.Results[0].Name).To(Equal("shp-source-default-image-digest"))
+		})
+
+		It("adds a step", func() {
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Name).To(Equal("source-default"))
+			Expect(taskSpec.Steps[0].Image).To(Equal(cfg.BundleContainerTemplate.Image))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--image",
+				"quay.io/shipwright/source-bundle:latest",
+				"--target",
+				"$(params.shp-source-root)",
+				"--result-file-image-digest",
+				"$(results.shp-source-default-image-digest.path)",
+			}))
+		})
+	})
+
+	Context("when adding a private source bundle image", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+		})
+
+		JustBeforeEach(func() {
+			sources.AppendBundleStep(cfg, taskSpec, buildv1alpha1.Source{
+				BundleContainer: &buildv1alpha1.BundleContainer{
+					Image: "quay.io/shipwright/source-bundle:latest",
+				},
+				Credentials: &corev1.LocalObjectReference{
+					Name: "a.secret",
+				},
+			}, "default")
+		})
+
+		It("adds a volume for the secret", func() {
+			Expect(len(taskSpec.Volumes)).To(Equal(1))
+			Expect(taskSpec.Volumes[0].Name).To(Equal("shp-a-secret"))
+			Expect(taskSpec.Volumes[0].VolumeSource.Secret).NotTo(BeNil())
+			Expect(taskSpec.Volumes[0].VolumeSource.Secret.SecretName).To(Equal("a.secret"))
+		})
+
+		It("adds a step that mounts the secret", func() {
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--image",
+				"quay.io/shipwright/source-bundle:latest",
+				"--target",
+				"$(params.shp-source-root)",
+				"--result-file-image-digest",
+				"$(results.shp-source-default-image-digest.path)",
+				"--secret-path",
+				"/workspace/shp-source-secret",
+			}))
+			Expect(len(taskSpec.Steps[0].VolumeMounts)).To(Equal(1))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].Name).To(Equal("shp-a-secret"))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].MountPath).To(Equal("/workspace/shp-source-secret"))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].ReadOnly).To(BeTrue())
+		})
+	})
+})
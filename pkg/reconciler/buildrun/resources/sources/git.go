@@ -6,6 +6,8 @@ package sources
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
@@ -20,10 +22,13 @@ func AppendGitStep(
 	source buildv1alpha1.Source,
 	name string,
 ) {
-	// append the result
+	// append the results
 	taskSpec.Results = append(taskSpec.Results, tektonv1beta1.TaskResult{
 		Name:        fmt.Sprintf("%s-source-%s-commit-sha", prefixParamsResultsVolumes, name),
 		Description: "The commit SHA of the cloned source.",
+	}, tektonv1beta1.TaskResult{
+		Name:        fmt.Sprintf("%s-source-%s-ref", prefixParamsResultsVolumes, name),
+		Description: "The ref of the cloned source, which is empty for a commit SHA.",
 	})
 
 	// initialize the step from the template
@@ -40,6 +45,8 @@ func AppendGitStep(
 		fmt.Sprintf("$(params.%s-%s)", prefixParamsResultsVolumes, paramSourceRoot),
 		"--result-file-commit-sha",
 		fmt.Sprintf("$(results.%s-source-%s-commit-sha.path)", prefixParamsResultsVolumes, name),
+		"--result-file-ref",
+		fmt.Sprintf("$(results.%s-source-%s-ref.path)", prefixParamsResultsVolumes, name),
 	}
 
 	// Check if a revision is defined
@@ -52,6 +59,101 @@ func AppendGitStep(
 		)
 	}
 
+	if source.Git != nil {
+		if source.Git.Depth != nil {
+			gitStep.Container.Args = append(
+				gitStep.Container.Args,
+				"--depth",
+				strconv.Itoa(*source.Git.Depth),
+			)
+		}
+
+		if source.Git.Sparse != nil {
+			// an empty list checks out the context directory
+			sparse := *source.Git.Sparse
+			if len(sparse) == 0 && source.ContextDir != nil && *source.ContextDir != "" {
+				sparse = []string{*source.ContextDir}
+			}
+
+			for _, directory := range sparse {
+				gitStep.Container.Args = append(
+					gitStep.Container.Args,
+					"--sparse",
+					directory,
+				)
+			}
+		}
+
+		if source.Git.Submodules != nil {
+			gitStep.Container.Args = append(
+				gitStep.Container.Args,
+				"--submodules",
+				string(*source.Git.Submodules),
+			)
+		}
+
+		if source.Git.LFS != nil {
+			gitStep.Container.Args = append(
+				gitStep.Container.Args,
+				"--lfs",
+				string(*source.Git.LFS),
+			)
+		}
+
+		if source.Git.SubmoduleCredentials != nil {
+			// ensure the value is there
+			AppendSecretVolume(taskSpec, source.Git.SubmoduleCredentials.Name)
+
+			submoduleSecretMountPath := fmt.Sprintf("/workspace/%s-source-submodule-secret", prefixParamsResultsVolumes)
+
+			// define the volume mount on the container
+			gitStep.VolumeMounts = append(gitStep.VolumeMounts, corev1.VolumeMount{
+				Name:      SanitizeVolumeNameForSecretName(source.Git.SubmoduleCredentials.Name),
+				MountPath: submoduleSecretMountPath,
+				ReadOnly:  true,
+			})
+
+			// append the argument
+			gitStep.Container.Args = append(
+				gitStep.Container.Args,
+				"--submodule-secret-path",
+				submoduleSecretMountPath,
+			)
+		}
+	}
+
+	if source.Git != nil && source.Git.Verify != nil {
+		verifyMountPath := fmt.Sprintf("/workspace/%s-source-verify", prefixParamsResultsVolumes)
+
+		// the trusted keys are either in a Secret or in a ConfigMap
+		var volumeName string
+		switch {
+		case source.Git.Verify.SecretRef != nil:
+			AppendSecretVolume(taskSpec, source.Git.Verify.SecretRef.Name)
+			volumeName = SanitizeVolumeNameForSecretName(source.Git.Verify.SecretRef.Name)
+
+		case source.Git.Verify.ConfigMapRef != nil:
+			AppendConfigMapVolume(taskSpec, source.Git.Verify.ConfigMapRef.Name)
+			volumeName = SanitizeVolumeNameForConfigMapName(source.Git.Verify.ConfigMapRef.Name)
+		}
+
+		if volumeName != "" {
+			// define the volume mount on the container
+			gitStep.VolumeMounts = append(gitStep.VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: verifyMountPath,
+				ReadOnly:  true,
+			})
+
+			// append the argument
+			gitStep.Container.Args = append(
+				gitStep.Container.Args,
+				"--verify-path",
+				verifyMountPath,
+			)
+		}
+	}
+
 	if source.Credentials != nil {
 		// ensure the value is there
 		AppendSecretVolume(taskSpec, source.Credentials.Name)
@@ -76,3 +178,23 @@ func AppendGitStep(
 	// append the git step
 	taskSpec.Steps = append(taskSpec.Steps, gitStep)
 }
+
+// AppendGitResult appends the results of the Git step of a source to the BuildRun status
+func AppendGitResult(
+	buildRun *buildv1alpha1.BuildRun,
+	name string,
+	results []tektonv1beta1.TaskRunResult,
+) {
+	commitSha := findResultValue(results, fmt.Sprintf("%s-source-%s-commit-sha", prefixParamsResultsVolumes, name))
+	ref := findResultValue(results, fmt.Sprintf("%s-source-%s-ref", prefixParamsResultsVolumes, name))
+
+	if strings.TrimSpace(commitSha) != "" {
+		buildRun.Status.Sources = append(buildRun.Status.Sources, buildv1alpha1.SourceResult{
+			Name: name,
+			Git: &buildv1alpha1.GitSourceResult{
+				CommitSha: strings.TrimSpace(commitSha),
+				Ref:       strings.TrimSpace(ref),
+			},
+		})
+	}
+}
@@ -34,9 +34,10 @@ var _ = Describe("Git", func() {
 			}, "default")
 		})
 
-		It("adds a result for the commit sha", func() {
-			Expect(len(taskSpec.Results)).To(Equal(1))
+		It("adds results for the commit sha and the ref", func() {
+			Expect(len(taskSpec.Results)).To(Equal(2))
 			Expect(taskSpec.Results[0].Name).To(Equal("shp-source-default-commit-sha"))
+			Expect(taskSpec.Results[1].Name).To(Equal("shp-source-default-ref"))
 		})
 
 		It("adds a step", func() {
@@ -50,10 +51,162 @@ var _ = Describe("Git", func() {
 				"$(params.shp-source-root)",
 				"--result-file-commit-sha",
 				"$(results.shp-source-default-commit-sha.path)",
+				"--result-file-ref",
+				"$(results.shp-source-default-ref.path)",
 			}))
 		})
 	})
 
+	Context("when adding a Git source with a depth and sparse directories", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+		})
+
+		It("adds the depth and the sparse directories to the arguments", func() {
+			depth := 0
+			sources.AppendGitStep(cfg, taskSpec, buildv1alpha1.Source{
+				URL: "https://github.com/shipwright-io/build",
+				Git: &buildv1alpha1.GitSource{
+					Depth:  &depth,
+					Sparse: &[]string{"cmd/git", "pkg"},
+				},
+			}, "default")
+
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--url",
+				"https://github.com/shipwright-io/build",
+				"--target",
+				"$(params.shp-source-root)",
+				"--result-file-commit-sha",
+				"$(results.shp-source-default-commit-sha.path)",
+				"--result-file-ref",
+				"$(results.shp-source-default-ref.path)",
+				"--depth",
+				"0",
+				"--sparse",
+				"cmd/git",
+				"--sparse",
+				"pkg",
+			}))
+		})
+
+		It("checks out the context directory for an empty list of sparse directories", func() {
+			contextDir := "cmd/git"
+			sources.AppendGitStep(cfg, taskSpec, buildv1alpha1.Source{
+				URL:        "https://github.com/shipwright-io/build",
+				ContextDir: &contextDir,
+				Git: &buildv1alpha1.GitSource{
+					Sparse: &[]string{},
+				},
+			}, "default")
+
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args[len(taskSpec.Steps[0].Args)-2:]).To(Equal([]string{
+				"--sparse",
+				"cmd/git",
+			}))
+		})
+	})
+
+	Context("when adding a Git source with submodules, Git LFS and submodule credentials", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+		})
+
+		It("adds the arguments and mounts the submodule secret", func() {
+			submodules := buildv1alpha1.GitSubmodulesShallow
+			lfs := buildv1alpha1.GitLFSEnabled
+			sources.AppendGitStep(cfg, taskSpec, buildv1alpha1.Source{
+				URL: "https://github.com/shipwright-io/build",
+				Git: &buildv1alpha1.GitSource{
+					Submodules:           &submodules,
+					LFS:                  &lfs,
+					SubmoduleCredentials: &corev1.LocalObjectReference{Name: "a.secret"},
+				},
+			}, "default")
+
+			Expect(len(taskSpec.Volumes)).To(Equal(1))
+			Expect(taskSpec.Volumes[0].Name).To(Equal("shp-a-secret"))
+
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--url",
+				"https://github.com/shipwright-io/build",
+				"--target",
+				"$(params.shp-source-root)",
+				"--result-file-commit-sha",
+				"$(results.shp-source-default-commit-sha.path)",
+				"--result-file-ref",
+				"$(results.shp-source-default-ref.path)",
+				"--submodules",
+				"shallow",
+				"--lfs",
+				"enabled",
+				"--submodule-secret-path",
+				"/workspace/shp-source-submodule-secret",
+			}))
+			Expect(len(taskSpec.Steps[0].VolumeMounts)).To(Equal(1))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].Name).To(Equal("shp-a-secret"))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].MountPath).To(Equal("/workspace/shp-source-submodule-secret"))
+		})
+	})
+
+	Context("when adding a Git source with commit signature verification", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+		})
+
+		It("mounts the ConfigMap with the trusted keys", func() {
+			sources.AppendGitStep(cfg, taskSpec, buildv1alpha1.Source{
+				URL: "https://github.com/shipwright-io/build",
+				Git: &buildv1alpha1.GitSource{
+					Verify: &buildv1alpha1.GitVerification{
+						ConfigMapRef: &corev1.LocalObjectReference{Name: "trusted-keys"},
+					},
+				},
+			}, "default")
+
+			Expect(len(taskSpec.Volumes)).To(Equal(1))
+			Expect(taskSpec.Volumes[0].Name).To(Equal("shp-configmap-trusted-keys"))
+			Expect(taskSpec.Volumes[0].ConfigMap).ToNot(BeNil())
+
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args[len(taskSpec.Steps[0].Args)-2:]).To(Equal([]string{
+				"--verify-path",
+				"/workspace/shp-source-verify",
+			}))
+			Expect(len(taskSpec.Steps[0].VolumeMounts)).To(Equal(1))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].Name).To(Equal("shp-configmap-trusted-keys"))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].MountPath).To(Equal("/workspace/shp-source-verify"))
+		})
+
+		It("mounts the Secret with the trusted keys", func() {
+			sources.AppendGitStep(cfg, taskSpec, buildv1alpha1.Source{
+				URL: "https://github.com/shipwright-io/build",
+				Git: &buildv1alpha1.GitSource{
+					Verify: &buildv1alpha1.GitVerification{
+						SecretRef: &corev1.LocalObjectReference{Name: "trusted-keys"},
+					},
+				},
+			}, "default")
+
+			Expect(len(taskSpec.Volumes)).To(Equal(1))
+			Expect(taskSpec.Volumes[0].Name).To(Equal("shp-trusted-keys"))
+			Expect(taskSpec.Volumes[0].Secret).ToNot(BeNil())
+			Expect(taskSpec.Steps[0].VolumeMounts[0].Name).To(Equal("shp-trusted-keys"))
+		})
+	})
+
 	Context("when adding a private Git source", func() {
 
 		var taskSpec *tektonv1beta1.TaskSpec
@@ -71,9 +224,10 @@ var _ = Describe("Git", func() {
 			}, "default")
 		})
 
-		It("adds a result for the commit sha", func() {
-			Expect(len(taskSpec.Results)).To(Equal(1))
+		It("adds results for the commit sha and the ref", func() {
+			Expect(len(taskSpec.Results)).To(Equal(2))
 			Expect(taskSpec.Results[0].Name).To(Equal("shp-source-default-commit-sha"))
+			Expect(taskSpec.Results[1].Name).To(Equal("shp-source-default-ref"))
 		})
 
 		It("adds a volume for the secret", func() {
@@ -94,6 +248,8 @@ var _ = Describe("Git", func() {
 				"$(params.shp-source-root)",
 				"--result-file-commit-sha",
 				"$(results.shp-source-default-commit-sha.path)",
+				"--result-file-ref",
+				"$(results.shp-source-default-ref.path)",
 				"--secret-path",
 				"/workspace/shp-source-secret",
 			}))
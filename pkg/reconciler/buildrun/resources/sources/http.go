@@ -6,6 +6,9 @@ package sources
 
 import (
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
@@ -13,45 +16,96 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// AppendHTTPStep appends the step for a HTTP source to the TaskSpec
+// AppendHTTPStep appends the step that downloads a HTTP source, and its results and volume if needed, to the TaskSpec
 func AppendHTTPStep(
 	cfg *config.Config,
 	taskSpec *tektonv1beta1.TaskSpec,
 	source buildv1alpha1.BuildSource,
 ) {
-	// HTTP is done currently all in a single step, see if there is already one
-	httpStep := findExistingHTTPSourcesStep(taskSpec)
-	if httpStep != nil {
-		httpStep.Container.Args[3] = fmt.Sprintf("%s ; wget \"%s\"", httpStep.Container.Args[3], source.URL)
-	} else {
-		httpStep := tektonv1beta1.Step{
-			Container: corev1.Container{
-				Name:       "sources-http",
-				Image:      cfg.RemoteArtifactsContainerImage,
-				WorkingDir: fmt.Sprintf("$(params.%s-%s)", prefixParamsResultsVolumes, paramSourceRoot),
-				Command: []string{
-					"/bin/sh",
-				},
-				Args: []string{
-					"-e",
-					"-x",
-					"-c",
-					fmt.Sprintf("wget \"%s\"", source.URL),
-				},
-			},
-		}
-
-		// append the git step
-		taskSpec.Steps = append(taskSpec.Steps, httpStep)
+	// append the results
+	taskSpec.Results = append(taskSpec.Results, tektonv1beta1.TaskResult{
+		Name:        fmt.Sprintf("%s-source-%s-sha256", prefixParamsResultsVolumes, source.Name),
+		Description: "The SHA-256 checksum of the downloaded artifact.",
+	}, tektonv1beta1.TaskResult{
+		Name:        fmt.Sprintf("%s-source-%s-size", prefixParamsResultsVolumes, source.Name),
+		Description: "The size of the downloaded artifact in bytes.",
+	})
+
+	target := fmt.Sprintf("$(params.%s-%s)", prefixParamsResultsVolumes, paramSourceRoot)
+	if source.TargetPath != nil {
+		target = path.Join(target, path.Clean("/"+*source.TargetPath))
+	}
+
+	// initialize the step from the template
+	httpStep := tektonv1beta1.Step{
+		Container: *cfg.HTTPContainerTemplate.DeepCopy(),
+	}
+
+	// add the build-specific details
+	httpStep.Container.Name = fmt.Sprintf("source-%s", source.Name)
+	httpStep.Container.Args = []string{
+		"--url",
+		source.URL,
+		"--target",
+		target,
+		"--result-file-sha256",
+		fmt.Sprintf("$(results.%s-source-%s-sha256.path)", prefixParamsResultsVolumes, source.Name),
+		"--result-file-size",
+		fmt.Sprintf("$(results.%s-source-%s-size.path)", prefixParamsResultsVolumes, source.Name),
+	}
+
+	if source.SHA256 != nil {
+		httpStep.Container.Args = append(httpStep.Container.Args, "--sha256", *source.SHA256)
+	}
+
+	if source.Extract != nil {
+		httpStep.Container.Args = append(httpStep.Container.Args, "--extract", string(*source.Extract))
 	}
+
+	if source.Credentials != nil {
+		// ensure the value is there
+		AppendSecretVolume(taskSpec, source.Credentials.Name)
+
+		secretMountPath := fmt.Sprintf("/workspace/%s-source-secret", prefixParamsResultsVolumes)
+
+		// define the volume mount on the container
+		httpStep.VolumeMounts = append(httpStep.VolumeMounts, corev1.VolumeMount{
+			Name:      SanitizeVolumeNameForSecretName(source.Credentials.Name),
+			MountPath: secretMountPath,
+			ReadOnly:  true,
+		})
+
+		// append the argument
+		httpStep.Container.Args = append(
+			httpStep.Container.Args,
+			"--secret-path",
+			secretMountPath,
+		)
+	}
+
+	// append the http step
+	taskSpec.Steps = append(taskSpec.Steps, httpStep)
 }
 
-func findExistingHTTPSourcesStep(taskSpec *tektonv1beta1.TaskSpec) *tektonv1beta1.Step {
-	for _, candidateStep := range taskSpec.Steps {
-		if candidateStep.Name == "sources-http" {
-			return &candidateStep
-		}
+// AppendHTTPResult appends the results of the HTTP step of a source to the BuildRun status
+func AppendHTTPResult(
+	buildRun *buildv1alpha1.BuildRun,
+	name string,
+	results []tektonv1beta1.TaskRunResult,
+) {
+	checksum := strings.TrimSpace(findResultValue(results, fmt.Sprintf("%s-source-%s-sha256", prefixParamsResultsVolumes, name)))
+	if checksum == "" {
+		return
 	}
 
-	return nil
+	// the size is informational, a value that can not be parsed is omitted
+	size, _ := strconv.ParseInt(strings.TrimSpace(findResultValue(results, fmt.Sprintf("%s-source-%s-size", prefixParamsResultsVolumes, name))), 10, 64)
+
+	buildRun.Status.Sources = append(buildRun.Status.Sources, buildv1alpha1.SourceResult{
+		Name: name,
+		HTTP: &buildv1alpha1.HTTPSourceResult{
+			SHA256: checksum,
+			Size:   size,
+		},
+	})
 }
@@ -28,21 +28,33 @@ var _ = Describe("HTTP", func() {
 			taskSpec = &tektonv1beta1.TaskSpec{}
 		})
 
-		It("adds the first step", func() {
+		It("adds the results and the step", func() {
 			sources.AppendHTTPStep(cfg, taskSpec, buildv1alpha1.BuildSource{
 				Name: "logo",
 				URL:  "https://shipwright.io/icons/logo.svg",
 			})
 
+			Expect(len(taskSpec.Results)).To(Equal(2))
+			Expect(taskSpec.Results[0].Name).To(Equal("shp-source-logo-sha256"))
+			Expect(taskSpec.Results[1].Name).To(Equal("shp-source-logo-size"))
+
 			Expect(len(taskSpec.Steps)).To(Equal(1))
-			Expect(taskSpec.Steps[0].Name).To(Equal("sources-http"))
-			Expect(taskSpec.Steps[0].Image).To(Equal(cfg.RemoteArtifactsContainerImage))
-			Expect(taskSpec.Steps[0].WorkingDir).To(Equal("$(params.shp-source-root)"))
-			Expect(taskSpec.Steps[0].Args[3]).To(Equal("wget \"https://shipwright.io/icons/logo.svg\""))
+			Expect(taskSpec.Steps[0].Name).To(Equal("source-logo"))
+			Expect(taskSpec.Steps[0].Image).To(Equal(cfg.HTTPContainerTemplate.Image))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--url",
+				"https://shipwright.io/icons/logo.svg",
+				"--target",
+				"$(params.shp-source-root)",
+				"--result-file-sha256",
+				"$(results.shp-source-logo-sha256.path)",
+				"--result-file-size",
+				"$(results.shp-source-logo-size.path)",
+			}))
 		})
 	})
 
-	Context("when a TaskSpec already contains the http step", func() {
+	Context("when a TaskSpec already contains another source step", func() {
 
 		var taskSpec *tektonv1beta1.TaskSpec
 
@@ -51,65 +63,75 @@ var _ = Describe("HTTP", func() {
 				Steps: []tektonv1beta1.Step{
 					{
 						Container: corev1.Container{
-							Name:       "sources-http",
-							Image:      cfg.RemoteArtifactsContainerImage,
-							WorkingDir: "$(params.shp-source-root)",
-							Command: []string{
-								"/bin/sh",
-							},
-							Args: []string{
-								"-e",
-								"-x",
-								"-c",
-								"wget \"https://tekton.dev/images/tekton-horizontal-color.png\"",
-							},
+							Name: "source-something",
 						},
 					},
 				},
 			}
 		})
 
-		It("updates the existing step", func() {
+		It("appends a separate step for each source", func() {
 			sources.AppendHTTPStep(cfg, taskSpec, buildv1alpha1.BuildSource{
 				Name: "logo",
 				URL:  "https://shipwright.io/icons/logo.svg",
 			})
+			sources.AppendHTTPStep(cfg, taskSpec, buildv1alpha1.BuildSource{
+				Name: "tekton-logo",
+				URL:  "https://tekton.dev/images/tekton-horizontal-color.png",
+			})
 
-			Expect(len(taskSpec.Steps)).To(Equal(1))
-			Expect(taskSpec.Steps[0].Name).To(Equal("sources-http"))
-			Expect(taskSpec.Steps[0].Image).To(Equal(cfg.RemoteArtifactsContainerImage))
-			Expect(taskSpec.Steps[0].WorkingDir).To(Equal("$(params.shp-source-root)"))
-			Expect(taskSpec.Steps[0].Args[3]).To(Equal("wget \"https://tekton.dev/images/tekton-horizontal-color.png\" ; wget \"https://shipwright.io/icons/logo.svg\""))
+			Expect(len(taskSpec.Steps)).To(Equal(3))
+			Expect(taskSpec.Steps[1].Name).To(Equal("source-logo"))
+			Expect(taskSpec.Steps[2].Name).To(Equal("source-tekton-logo"))
+			Expect(taskSpec.Steps[2].Args[1]).To(Equal("https://tekton.dev/images/tekton-horizontal-color.png"))
 		})
 	})
 
-	Context("when a TaskSpec already another source step step", func() {
+	Context("when the source defines a checksum, an archive format, a target path and credentials", func() {
 
 		var taskSpec *tektonv1beta1.TaskSpec
 
 		BeforeEach(func() {
-			taskSpec = &tektonv1beta1.TaskSpec{
-				Steps: []tektonv1beta1.Step{
-					{
-						Container: corev1.Container{
-							Name: "source-something",
-						},
-					},
-				},
-			}
+			taskSpec = &tektonv1beta1.TaskSpec{}
 		})
 
-		It("appends the http step", func() {
+		It("adds the arguments and mounts the secret", func() {
+			sha256 := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+			extract := buildv1alpha1.ExtractFormatTar
+			targetPath := "vendor/sources"
+
 			sources.AppendHTTPStep(cfg, taskSpec, buildv1alpha1.BuildSource{
-				Name: "logo",
-				URL:  "https://shipwright.io/icons/logo.svg",
+				Name:        "sources",
+				URL:         "https://example.com/sources.tar.gz",
+				SHA256:      &sha256,
+				Extract:     &extract,
+				TargetPath:  &targetPath,
+				Credentials: &corev1.LocalObjectReference{Name: "a.secret"},
 			})
 
-			Expect(len(taskSpec.Steps)).To(Equal(2))
-			Expect(taskSpec.Steps[1].Name).To(Equal("sources-http"))
-			Expect(taskSpec.Steps[1].Image).To(Equal(cfg.RemoteArtifactsContainerImage))
-			Expect(taskSpec.Steps[1].WorkingDir).To(Equal("$(params.shp-source-root)"))
-			Expect(taskSpec.Steps[1].Args[3]).To(Equal("wget \"https://shipwright.io/icons/logo.svg\""))
+			Expect(len(taskSpec.Volumes)).To(Equal(1))
+			Expect(taskSpec.Volumes[0].Name).To(Equal("shp-a-secret"))
+
+			Expect(len(taskSpec.Steps)).To(Equal(1))
+			Expect(taskSpec.Steps[0].Args).To(Equal([]string{
+				"--url",
+				"https://example.com/sources.tar.gz",
+				"--target",
+				"$(params.shp-source-root)/vendor/sources",
+				"--result-file-sha256",
+				"$(results.shp-source-sources-sha256.path)",
+				"--result-file-size",
+				"$(results.shp-source-sources-size.path)",
+				"--sha256",
+				sha256,
+				"--extract",
+				"tar",
+				"--secret-path",
+				"/workspace/shp-source-secret",
+			}))
+			Expect(len(taskSpec.Steps[0].VolumeMounts)).To(Equal(1))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].Name).To(Equal("shp-a-secret"))
+			Expect(taskSpec.Steps[0].VolumeMounts[0].MountPath).To(Equal("/workspace/shp-source-secret"))
 		})
 	})
 })
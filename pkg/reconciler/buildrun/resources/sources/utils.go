@@ -52,6 +52,34 @@ func AppendSecretVolume(
 	})
 }
 
+// AppendConfigMapVolume checks if a volume for a ConfigMap already exists, if not it appends it to the TaskSpec
+func AppendConfigMapVolume(
+	taskSpec *tektonv1beta1.TaskSpec,
+	configMapName string,
+) {
+	volumeName := SanitizeVolumeNameForConfigMapName(configMapName)
+
+	// ensure we do not add the ConfigMap twice
+	for _, volume := range taskSpec.Volumes {
+		if volume.VolumeSource.ConfigMap != nil && volume.Name == volumeName {
+			return
+		}
+	}
+
+	// append volume for ConfigMap
+	taskSpec.Volumes = append(taskSpec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+				DefaultMode: secretMountMode,
+			},
+		},
+	})
+}
+
 // SanitizeVolumeNameForSecretName creates the name of a Volume for a Secret
 func SanitizeVolumeNameForSecretName(secretName string) string {
 	// remove forbidden characters
@@ -64,3 +92,20 @@ func SanitizeVolumeNameForSecretName(secretName string) string {
 
 	return sanitizedName
 }
+
+// findResultValue returns the value of the TaskRun result with the given name, or an empty string
+func findResultValue(results []tektonv1beta1.TaskRunResult, name string) string {
+	for _, result := range results {
+		if result.Name == name {
+			return result.Value
+		}
+	}
+
+	return ""
+}
+
+// SanitizeVolumeNameForConfigMapName creates the name of a Volume for a ConfigMap, which
+// differs from the one of a Secret with the same name
+func SanitizeVolumeNameForConfigMapName(configMapName string) string {
+	return SanitizeVolumeNameForSecretName(fmt.Sprintf("configmap-%s", configMapName))
+}
@@ -28,6 +28,10 @@ var _ = Describe("Utils", func() {
 		It("adds the prefix and reduces the length if needed", func() {
 			Expect(sources.SanitizeVolumeNameForSecretName("long-name-long-name-long-name-long-name-long-name-long-name-long-name-")).To(Equal("shp-long-name-long-name-long-name-long-name-long-name-long-name"))
 		})
+
+		It("adds a different prefix for a ConfigMap", func() {
+			Expect(sources.SanitizeVolumeNameForConfigMapName("trusted.keys")).To(Equal("shp-configmap-trusted-keys"))
+		})
 	})
 
 	Context("when a TaskSpec does not contain any volume", func() {
@@ -82,4 +86,24 @@ var _ = Describe("Utils", func() {
 			Expect(len(taskSpec.Volumes)).To(Equal(1))
 		})
 	})
+
+	Context("when a TaskSpec contains a volume secret with the name of a ConfigMap", func() {
+
+		var taskSpec *tektonv1beta1.TaskSpec
+
+		BeforeEach(func() {
+			taskSpec = &tektonv1beta1.TaskSpec{}
+			sources.AppendSecretVolume(taskSpec, "trusted-keys")
+		})
+
+		It("adds a separate volume for the ConfigMap only once", func() {
+			sources.AppendConfigMapVolume(taskSpec, "trusted-keys")
+			sources.AppendConfigMapVolume(taskSpec, "trusted-keys")
+
+			Expect(len(taskSpec.Volumes)).To(Equal(2))
+			Expect(taskSpec.Volumes[1].Name).To(Equal("shp-configmap-trusted-keys"))
+			Expect(taskSpec.Volumes[1].VolumeSource.ConfigMap).NotTo(BeNil())
+			Expect(taskSpec.Volumes[1].VolumeSource.ConfigMap.Name).To(Equal("trusted-keys"))
+		})
+	})
 })
@@ -0,0 +1,114 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// ValidateStepResources validates that the resource overrides reference steps of the
+// strategy and that their quantities are within the resource bounds of the strategy.
+// It returns a reason and a message if the validation fails.
+func ValidateStepResources(steps []buildv1alpha1.BuildStep, overrides []buildv1alpha1.StepResources, bounds *buildv1alpha1.ResourceBounds) (buildv1alpha1.BuildReason, string) {
+	for _, override := range overrides {
+		if override.Step != "" && !hasStep(steps, override.Step) {
+			return buildv1alpha1.UndefinedStep, fmt.Sprintf("resources defined for step %s that does not exist in the strategy", override.Step)
+		}
+
+		if bounds == nil {
+			continue
+		}
+
+		for _, kind := range []string{"limit", "request"} {
+			list := override.Limits
+			if kind == "request" {
+				list = override.Requests
+			}
+
+			for _, name := range sortedResourceNames(list) {
+				quantity := list[name]
+				if min, ok := bounds.Min[name]; ok && quantity.Cmp(min) < 0 {
+					return buildv1alpha1.ResourcesOutOfBounds, fmt.Sprintf("%s %s %s of %s is below the minimum %s", name, kind, quantity.String(), stepDescription(override.Step), min.String())
+				}
+				if max, ok := bounds.Max[name]; ok && quantity.Cmp(max) > 0 {
+					return buildv1alpha1.ResourcesOutOfBounds, fmt.Sprintf("%s %s %s of %s exceeds the maximum %s", name, kind, quantity.String(), stepDescription(override.Step), max.String())
+				}
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// applyStepResources returns a copy of the resources of the step, where the limits and
+// requests are overridden by the given lists of overrides, in their order. Within a list,
+// the overrides for all steps are applied before the ones for the specific step.
+func applyStepResources(stepName string, resources corev1.ResourceRequirements, overrideLists ...[]buildv1alpha1.StepResources) (corev1.ResourceRequirements, error) {
+	result := *resources.DeepCopy()
+
+	for _, overrides := range overrideLists {
+		for _, allSteps := range []bool{true, false} {
+			for _, override := range overrides {
+				if allSteps && override.Step != "" || !allSteps && override.Step != stepName {
+					continue
+				}
+				result.Limits = mergeResourceList(result.Limits, override.Limits)
+				result.Requests = mergeResourceList(result.Requests, override.Requests)
+			}
+		}
+	}
+
+	for _, name := range sortedResourceNames(result.Requests) {
+		request := result.Requests[name]
+		if limit, ok := result.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return result, fmt.Errorf("%s request %s of step %s exceeds its limit %s", name, request.String(), stepName, limit.String())
+		}
+	}
+
+	return result, nil
+}
+
+func mergeResourceList(base corev1.ResourceList, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		base[name] = quantity.DeepCopy()
+	}
+	return base
+}
+
+func hasStep(steps []buildv1alpha1.BuildStep, name string) bool {
+	for _, step := range steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func stepDescription(step string) string {
+	if step == "" {
+		return "all steps"
+	}
+	return "step " + step
+}
@@ -0,0 +1,67 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+)
+
+var _ = Describe("ValidateStepResources", func() {
+	steps := []buildv1alpha1.BuildStep{
+		{Container: corev1.Container{Name: "build"}},
+		{Container: corev1.Container{Name: "push"}},
+	}
+
+	bounds := &buildv1alpha1.ResourceBounds{
+		Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi"), corev1.ResourceCPU: resource.MustParse("2")},
+	}
+
+	memory := func(step string, limit string) buildv1alpha1.StepResources {
+		return buildv1alpha1.StepResources{
+			Step: step,
+			ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)},
+			},
+		}
+	}
+
+	DescribeTable("validates the resource overrides",
+		func(overrides []buildv1alpha1.StepResources, bounds *buildv1alpha1.ResourceBounds, expectedReason buildv1alpha1.BuildReason, expectedMessage string) {
+			reason, message := resources.ValidateStepResources(steps, overrides, bounds)
+			Expect(reason).To(Equal(expectedReason))
+			Expect(message).To(Equal(expectedMessage))
+		},
+
+		Entry("no overrides", nil, bounds, buildv1alpha1.BuildReason(""), ""),
+
+		Entry("overrides without bounds",
+			[]buildv1alpha1.StepResources{memory("", "16Gi")}, nil,
+			buildv1alpha1.BuildReason(""), ""),
+
+		Entry("overrides within the bounds",
+			[]buildv1alpha1.StepResources{memory("", "1Gi"), memory("build", "4Gi")}, bounds,
+			buildv1alpha1.BuildReason(""), ""),
+
+		Entry("override for an undefined step",
+			[]buildv1alpha1.StepResources{memory("test", "1Gi")}, bounds,
+			buildv1alpha1.UndefinedStep, "resources defined for step test that does not exist in the strategy"),
+
+		Entry("override above the maximum",
+			[]buildv1alpha1.StepResources{memory("build", "8Gi")}, bounds,
+			buildv1alpha1.ResourcesOutOfBounds, "memory limit 8Gi of step build exceeds the maximum 4Gi"),
+
+		Entry("override below the minimum",
+			[]buildv1alpha1.StepResources{memory("", "128Mi")}, bounds,
+			buildv1alpha1.ResourcesOutOfBounds, "memory limit 128Mi of all steps is below the minimum 256Mi"),
+	)
+})
@@ -5,6 +5,7 @@
 package resources
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"strconv"
@@ -27,8 +28,11 @@ const (
 	paramSourceRoot    = "source-root"
 	paramSourceContext = "source-context"
 
-	resultImageDigest = "image-digest"
-	resultImageSize   = "image-size"
+	resultImageDigest    = "image-digest"
+	resultImageSize      = "image-size"
+	resultImageTags      = "image-tags"
+	resultImageSignature = "image-signature"
+	resultImageSBOM      = "image-sbom-digest"
 
 	workspaceSource = "source"
 
@@ -67,8 +71,23 @@ func GenerateTaskSpec(
 	buildRun *buildv1alpha1.BuildRun,
 	buildSteps []buildv1alpha1.BuildStep,
 	strategyParams []buildv1alpha1.Parameter,
+	protectedEnvVars []string,
+	resourceBounds *buildv1alpha1.ResourceBounds,
 ) (*v1beta1.TaskSpec, error) {
 
+	// environment variables of the Build and BuildRun are set in all strategy steps
+	envs := MergeEnvVars(build, buildRun)
+	if err := validateEnvVars(envs, protectedEnvVars); err != nil {
+		return nil, err
+	}
+
+	// resources of the Build and BuildRun override the ones of the strategy steps
+	for _, overrides := range [][]buildv1alpha1.StepResources{build.Spec.Resources, buildRun.Spec.Resources} {
+		if reason, message := ValidateStepResources(buildSteps, overrides, resourceBounds); reason != "" {
+			return nil, errors.New(message)
+		}
+	}
+
 	generatedTaskSpec := v1beta1.TaskSpec{
 		Params: []v1beta1.ParamSpec{
 			{
@@ -144,14 +163,27 @@ func GenerateTaskSpec(
 		param := v1beta1.ParamSpec{
 			Name:        p.Name,
 			Description: p.Description,
+			Type:        v1beta1.ParamTypeString,
 		}
 
 		// verify if the paramSpec Default requires a default
 		// value or not
-		if p.Default != nil {
-			param.Default = &v1beta1.ArrayOrString{
-				Type:      v1beta1.ParamTypeString,
-				StringVal: *p.Default,
+		switch p.GetType() {
+		case buildv1alpha1.ParameterTypeArray:
+			param.Type = v1beta1.ParamTypeArray
+			if p.Defaults != nil {
+				param.Default = &v1beta1.ArrayOrString{
+					Type:     v1beta1.ParamTypeArray,
+					ArrayVal: *p.Defaults,
+				}
+			}
+
+		default:
+			if p.Default != nil {
+				param.Default = &v1beta1.ArrayOrString{
+					Type:      v1beta1.ParamTypeString,
+					StringVal: *p.Default,
+				}
 			}
 		}
 
@@ -174,6 +206,11 @@ func GenerateTaskSpec(
 
 		taskImage := getStringTransformations(containerValue.Image)
 
+		taskResources, err := applyStepResources(containerValue.Name, containerValue.Resources, build.Spec.Resources, buildRun.Spec.Resources)
+		if err != nil {
+			return nil, err
+		}
+
 		step := v1beta1.Step{
 			Container: corev1.Container{
 				Image:           taskImage,
@@ -184,8 +221,8 @@ func GenerateTaskSpec(
 				Args:            taskArgs,
 				SecurityContext: containerValue.SecurityContext,
 				WorkingDir:      containerValue.WorkingDir,
-				Resources:       containerValue.Resources,
-				Env:             containerValue.Env,
+				Resources:       taskResources,
+				Env:             mergeEnvVars(containerValue.Env, envs),
 			},
 		}
 
@@ -214,6 +251,9 @@ func GenerateTaskSpec(
 		}
 	}
 
+	// modify the output image after it got pushed by the strategy steps
+	amendTaskSpecWithImageProcessing(cfg, &generatedTaskSpec, effectiveOutput(build, buildRun), buildRun.Name)
+
 	return &generatedTaskSpec, nil
 }
 
@@ -227,12 +267,7 @@ func GenerateTaskRun(
 ) (*v1beta1.TaskRun, error) {
 
 	// retrieve expected imageURL form build or buildRun
-	var image string
-	if buildRun.Spec.Output != nil {
-		image = buildRun.Spec.Output.Image
-	} else {
-		image = build.Spec.Output.Image
-	}
+	image := effectiveOutput(build, buildRun).Image
 
 	taskSpec, err := GenerateTaskSpec(
 		cfg,
@@ -240,11 +275,16 @@ func GenerateTaskRun(
 		buildRun,
 		strategy.GetBuildSteps(),
 		strategy.GetParameters(),
+		strategy.GetProtectedEnvVars(),
+		strategy.GetResourceBounds(),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// persist the caches that the Build binds to PersistentVolumeClaims
+	amendTaskSpecWithCaches(taskSpec, strategy, build, buildRun)
+
 	expectedTaskRun := &v1beta1.TaskRun{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: buildRun.Name + "-",
@@ -280,12 +320,26 @@ func GenerateTaskRun(
 		expectedTaskRun.Annotations = taskRunAnnotations
 	}
 
+	// a standalone BuildRun does not belong to a Build
+	if buildRun.IsStandalone() {
+		delete(expectedTaskRun.Labels, buildv1alpha1.LabelBuild)
+		delete(expectedTaskRun.Labels, buildv1alpha1.LabelBuildGeneration)
+	}
+
 	for label, value := range strategy.GetResourceLabels() {
 		expectedTaskRun.Labels[label] = value
 	}
 
 	expectedTaskRun.Spec.Timeout = effectiveTimeout(build, buildRun)
 
+	// the Build and BuildRun can only set the tolerations, priority class and runtime class that the strategy allows
+	for _, scheduling := range []*buildv1alpha1.Scheduling{build.Spec.Scheduling, buildRun.Spec.Scheduling} {
+		if reason, message := ValidateScheduling(scheduling, strategy.GetSchedulingBounds()); reason != "" {
+			return nil, errors.New(message)
+		}
+	}
+	expectedTaskRun.Spec.PodTemplate = podTemplate(EffectiveScheduling(strategy, build, buildRun))
+
 	params := []v1beta1.Param{
 		{
 			// shp-output-image
@@ -355,6 +409,14 @@ func GenerateTaskRun(
 	// list of params that collide with reserved system strategy parameters
 	undesiredParams := []string{}
 
+	// environment variables that hold the values of parameters that reference a Secret
+	var secretEnvs []corev1.EnvVar
+
+	strategyParams := map[string]buildv1alpha1.Parameter{}
+	for _, strategyParam := range strategy.GetParameters() {
+		strategyParams[strategyParam.Name] = strategyParam
+	}
+
 	// Append params to the TaskRun spec definition
 	for _, p := range buildUserParams {
 
@@ -362,15 +424,59 @@ func GenerateTaskRun(
 			undesiredParams = append(undesiredParams, p.Name)
 		}
 
+		// values of the BuildRun are not validated by the Build controller
+		strategyParam := strategyParams[p.Name]
+		if reason, message := ValidateParamValue(strategyParam, p); reason != "" {
+			return nil, fmt.Errorf("%s", message)
+		}
+
 		buildParam := v1beta1.Param{
-			Name: p.Name,
-			Value: v1beta1.ArrayOrString{
+			Name:  p.Name,
+			Value: paramValueToArrayOrString(strategyParam.GetType(), p),
+		}
+
+		if p.ValueFrom != nil {
+			if p.ValueFrom.SecretKeyRef == nil {
+				return nil, fmt.Errorf("the value of parameter %s was not resolved", p.Name)
+			}
+
+			if reason, message := ValidateSecretParamValueUse(strategy.GetBuildSteps(), p); reason != "" {
+				return nil, fmt.Errorf("%s", message)
+			}
+
+			// the value of a Secret is not part of the TaskRun, the parameter refers to an
+			// environment variable of the strategy steps that is expanded by the kubelet
+			envName := paramValueEnvName(p.Name)
+			secretEnvs = append(secretEnvs, corev1.EnvVar{
+				Name: envName,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: p.ValueFrom.SecretKeyRef,
+				},
+			})
+			buildParam.Value = v1beta1.ArrayOrString{
 				Type:      v1beta1.ParamTypeString,
-				StringVal: p.Value,
-			},
+				StringVal: fmt.Sprintf("$(%s)", envName),
+			}
 		}
+
 		expectedTaskRun.Spec.Params = append(expectedTaskRun.Spec.Params, buildParam)
 	}
+
+	if len(secretEnvs) > 0 {
+		strategySteps := map[string]bool{}
+		for _, step := range strategy.GetBuildSteps() {
+			strategySteps[step.Name] = true
+		}
+
+		for i := range expectedTaskRun.Spec.TaskSpec.Steps {
+			step := &expectedTaskRun.Spec.TaskSpec.Steps[i]
+			if strategySteps[step.Name] {
+				// the step env is shared with the strategy, it must not be modified in place, and the
+				// variables must precede the ones that reference them in their value
+				step.Env = append(append([]corev1.EnvVar{}, secretEnvs...), step.Env...)
+			}
+		}
+	}
 	// if system parameters names are being use, fail the taskRun creation and update the condition message
 	// with a custom error
 	if len(undesiredParams) > 0 {
@@ -383,7 +489,7 @@ func GenerateTaskRun(
 
 StrategyParametersLoop:
 	for _, strategyParam := range strategy.GetParameters() {
-		if strategyParam.Default == nil {
+		if strategyParam.Default == nil && strategyParam.Defaults == nil {
 			for _, p := range buildUserParams {
 				if strategyParam.Name == p.Name {
 					// go back to the outer loop
@@ -70,7 +70,7 @@ var _ = Describe("GenerateTaskrun", func() {
 			})
 
 			JustBeforeEach(func() {
-				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{})
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
 				Expect(err).To(BeNil())
 			})
 
@@ -84,6 +84,8 @@ var _ = Describe("GenerateTaskrun", func() {
 					"$(params.shp-source-root)",
 					"--result-file-commit-sha",
 					"$(results.shp-source-default-commit-sha.path)",
+					"--result-file-ref",
+					"$(results.shp-source-default-ref.path)",
 				}))
 			})
 
@@ -92,8 +94,9 @@ var _ = Describe("GenerateTaskrun", func() {
 				Expect(got.Results).To(utils.ContainNamedElement("shp-image-size"))
 			})
 
-			It("should contain a result for the Git commit SHA", func() {
+			It("should contain results for the Git commit SHA and ref", func() {
 				Expect(got.Results).To(utils.ContainNamedElement("shp-source-default-commit-sha"))
+				Expect(got.Results).To(utils.ContainNamedElement("shp-source-default-ref"))
 			})
 
 			It("should ensure IMAGE is replaced by builder image when needed.", func() {
@@ -137,6 +140,281 @@ var _ = Describe("GenerateTaskrun", func() {
 				Expect(len(got.Params)).To(Equal(5))
 			})
 		})
+
+		Context("when the Build and BuildRun override step resources", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+
+				build.Spec.Resources = []buildv1alpha1.StepResources{
+					{ResourceRequirements: ctl.LoadCustomResources("1", "2Gi")},
+					{Step: "buildah-bud", ResourceRequirements: ctl.LoadCustomResources("2", "4Gi")},
+				}
+				buildRun.Spec.Resources = []buildv1alpha1.StepResources{
+					{Step: "buildah-bud", ResourceRequirements: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+					}},
+				}
+			})
+
+			It("should apply the overrides of the Build and then the ones of the BuildRun", func() {
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				expected := ctl.LoadCustomResources("2", "4Gi")
+				expected.Limits[corev1.ResourceMemory] = resource.MustParse("8Gi")
+				Expect(got.Steps[1].Resources).To(Equal(expected))
+				Expect(got.Steps[2].Resources).To(Equal(ctl.LoadCustomResources("1", "2Gi")))
+
+				Expect(buildStrategy.Spec.BuildSteps[0].Resources).To(Equal(ctl.LoadCustomResources("500m", "1Gi")))
+			})
+
+			It("should fail when the overrides exceed the resource bounds of the strategy", func() {
+				bounds := &buildv1alpha1.ResourceBounds{Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}}
+
+				_, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, bounds)
+				Expect(err).To(MatchError("memory limit 8Gi of step buildah-bud exceeds the maximum 4Gi"))
+			})
+
+			It("should fail when a request exceeds its limit", func() {
+				buildRun.Spec.Resources[0].Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+
+				_, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(MatchError("memory request 4Gi of step buildah-bud exceeds its limit 1Gi"))
+			})
+		})
+
+		Context("when the Build and BuildRun define environment variables", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+
+				buildStrategy.Spec.BuildSteps[0].Env = []corev1.EnvVar{{Name: "STORAGE_DRIVER", Value: "overlay"}, {Name: "BUILDAH_FORMAT", Value: "oci"}}
+				build.Spec.Env = []corev1.EnvVar{{Name: "STORAGE_DRIVER", Value: "vfs"}, {Name: "GOFLAGS", Value: "-mod=vendor"}}
+				buildRun.Spec.Env = []corev1.EnvVar{{Name: "GOFLAGS", Value: "-mod=mod"}}
+			})
+
+			It("should set them in all strategy steps, with the BuildRun values overwriting the Build values", func() {
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[1].Env).To(Equal([]corev1.EnvVar{
+					{Name: "STORAGE_DRIVER", Value: "vfs"},
+					{Name: "BUILDAH_FORMAT", Value: "oci"},
+					{Name: "GOFLAGS", Value: "-mod=mod"},
+				}))
+				Expect(got.Steps[2].Env).To(Equal([]corev1.EnvVar{
+					{Name: "STORAGE_DRIVER", Value: "vfs"},
+					{Name: "GOFLAGS", Value: "-mod=mod"},
+				}))
+				Expect(got.Steps[0].Env).ToNot(ContainElement(corev1.EnvVar{Name: "GOFLAGS", Value: "-mod=mod"}))
+				Expect(buildStrategy.Spec.BuildSteps[0].Env).To(HaveLen(2))
+			})
+
+			It("should fail when they collide with the protected environment variables of the strategy", func() {
+				_, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, []string{"STORAGE_DRIVER", "GOFLAGS"}, nil)
+				Expect(err).To(MatchError("environment variables protected by the strategy in use: STORAGE_DRIVER,GOFLAGS"))
+			})
+		})
+
+		Context("when the output defines labels, annotations, additional tags, SBOM and signing", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.MinimalBuildahBuild))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.MinimalBuildahBuildRun))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+
+				build.Spec.Output.Labels = map[string]string{"team": "a", "description": "sample"}
+				build.Spec.Output.Annotations = map[string]string{"org.opencontainers.image.vendor": "shipwright"}
+			})
+
+			It("should not contain the image processing step without labels, annotations and additional tags", func() {
+				build.Spec.Output.Labels = nil
+				build.Spec.Output.Annotations = nil
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+				Expect(got.Steps).To(HaveLen(3))
+			})
+
+			It("should contain the image processing step after the strategy steps", func() {
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps).To(HaveLen(4))
+				Expect(got.Steps[3].Name).To(Equal("image-processing"))
+				Expect(got.Steps[3].Command[0]).To(Equal("/ko-app/image-processing"))
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--label",
+					"description=sample",
+					"--label",
+					"team=a",
+					"--annotation",
+					"org.opencontainers.image.vendor=shipwright",
+				}))
+			})
+
+			It("should use the output of the BuildRun and mount its credentials", func() {
+				buildRun.Spec.Output = &buildv1alpha1.Image{
+					Image:       "quay.io/shipwright/sample:pr-1",
+					Credentials: &corev1.LocalObjectReference{Name: "push-secret"},
+					Labels:      map[string]string{"preview": "true"},
+				}
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--label",
+					"preview=true",
+					"--secret-path",
+					"/workspace/shp-output-secret",
+				}))
+				Expect(got.Steps[3].VolumeMounts).To(Equal([]corev1.VolumeMount{
+					{Name: "shp-push-secret", MountPath: "/workspace/shp-output-secret", ReadOnly: true},
+				}))
+				Expect(got.Volumes).To(utils.ContainNamedElement("shp-push-secret"))
+			})
+
+			It("should pass the additional tags and the values of their placeholders", func() {
+				build.Spec.Output.Labels = nil
+				build.Spec.Output.Annotations = nil
+				build.Spec.Output.AdditionalTags = []string{"$(commit-sha-short)", "$(buildrun-name)"}
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Results).To(utils.ContainNamedElement("shp-image-tags"))
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--additional-tag",
+					"$(commit-sha-short)",
+					"--additional-tag",
+					"$(buildrun-name)",
+					"--buildrun-name",
+					buildRun.Name,
+					"--commit-sha-file",
+					"$(results.shp-source-default-commit-sha.path)",
+				}))
+			})
+
+			It("should pull a source bundle image and not pass the commit SHA for the additional tags", func() {
+				build.Spec.Source.URL = ""
+				build.Spec.Source.BundleContainer = &buildv1alpha1.BundleContainer{Image: "quay.io/shipwright/source-bundle:latest"}
+				build.Spec.Output.Labels = nil
+				build.Spec.Output.Annotations = nil
+				build.Spec.Output.AdditionalTags = []string{"$(buildrun-name)"}
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Steps[0].Name).To(Equal("source-default"))
+				Expect(got.Steps[0].Command[0]).To(Equal("/ko-app/bundle"))
+				Expect(got.Results).To(utils.ContainNamedElement("shp-source-default-image-digest"))
+				Expect(got.Results).ToNot(utils.ContainNamedElement("shp-source-default-commit-sha"))
+
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--additional-tag",
+					"$(buildrun-name)",
+					"--buildrun-name",
+					buildRun.Name,
+				}))
+			})
+
+			It("should pass the SBOM format", func() {
+				build.Spec.Output.Labels = nil
+				build.Spec.Output.Annotations = nil
+				build.Spec.Output.SBOM = &buildv1alpha1.ImageSBOM{Format: buildv1alpha1.SBOMFormatCycloneDX}
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Results).To(utils.ContainNamedElement("shp-image-sbom-digest"))
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--sbom-format",
+					"CycloneDX",
+					"--result-file-image-sbom-digest",
+					"$(results.shp-image-sbom-digest.path)",
+				}))
+			})
+
+			It("should mount the signing key and pass its path", func() {
+				build.Spec.Output.Labels = nil
+				build.Spec.Output.Annotations = nil
+				build.Spec.Output.Signing = &buildv1alpha1.ImageSigning{
+					SecretRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "signing-secret"},
+						Key:                  "cosign.key",
+					},
+				}
+
+				got, err = resources.GenerateTaskSpec(config.NewDefaultConfig(), build, buildRun, buildStrategy.Spec.BuildSteps, []buildv1alpha1.Parameter{}, nil, nil)
+				Expect(err).To(BeNil())
+
+				Expect(got.Results).To(utils.ContainNamedElement("shp-image-signature"))
+				Expect(got.Steps[3].Args).To(Equal([]string{
+					"--image",
+					"$(params.shp-output-image)",
+					"--result-file-image-digest",
+					"$(results.shp-image-digest.path)",
+					"--result-file-image-tags",
+					"$(results.shp-image-tags.path)",
+					"--signing-key",
+					"/workspace/shp-signing-secret/cosign.key",
+					"--result-file-image-signature",
+					"$(results.shp-image-signature.path)",
+				}))
+				Expect(got.Steps[3].VolumeMounts).To(Equal([]corev1.VolumeMount{
+					{Name: "shp-signing-secret", MountPath: "/workspace/shp-signing-secret", ReadOnly: true},
+				}))
+				Expect(got.Volumes).To(utils.ContainNamedElement("shp-signing-secret"))
+			})
+		})
 	})
 
 	Describe("Generate the TaskRun", func() {
@@ -224,6 +502,113 @@ var _ = Describe("GenerateTaskrun", func() {
 			It("should have no timeout set", func() {
 				Expect(got.Spec.Timeout).To(BeNil())
 			})
+
+			It("should have no pod template set", func() {
+				Expect(got.Spec.PodTemplate).To(BeNil())
+			})
+		})
+
+		Context("when the Build binds caches of the strategy", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithOutput))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.MinimalBuildahBuildStrategy))
+				Expect(err).To(BeNil())
+
+				buildStrategy.Spec.Caches = []buildv1alpha1.StrategyCache{{Name: "buildah-images"}}
+				build.Spec.Caches = []buildv1alpha1.BuildCache{{Name: "buildah-images"}}
+			})
+
+			It("should use the claim of the cache as the source of the volume", func() {
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Spec.TaskSpec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "buildah-images",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: build.Name + "-cache-buildah-images"},
+					},
+				}))
+			})
+
+			It("should keep an emptyDir volume for a BuildRun with an embedded Build spec", func() {
+				buildRun.Spec.BuildRef = nil
+				buildRun.Spec.BuildSpec = &build.Spec
+
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Spec.TaskSpec.Volumes).To(ContainElement(corev1.Volume{Name: "buildah-images"}))
+			})
+		})
+
+		Context("when the strategy, Build and BuildRun define scheduling", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithOutput))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.BuildahBuildStrategySingleStep))
+				Expect(err).To(BeNil())
+
+				priorityClassName := "build"
+				buildStrategy.Spec.Scheduling = &buildv1alpha1.Scheduling{
+					NodeSelector:      map[string]string{"node-role.kubernetes.io/build": ""},
+					PriorityClassName: &priorityClassName,
+				}
+				build.Spec.Scheduling = &buildv1alpha1.Scheduling{
+					Tolerations: []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				}
+				buildRun.Spec.Scheduling = &buildv1alpha1.Scheduling{
+					NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+				}
+				buildStrategy.Spec.SchedulingBounds = &buildv1alpha1.SchedulingBounds{
+					Tolerations:        []corev1.Toleration{{Key: "build", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+					PriorityClassNames: []string{"build", "build-urgent"},
+				}
+			})
+
+			It("should translate the effective scheduling into the pod template", func() {
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				Expect(got.Spec.PodTemplate).ToNot(BeNil())
+				Expect(got.Spec.PodTemplate.NodeSelector).To(Equal(map[string]string{"kubernetes.io/arch": "arm64"}))
+				Expect(got.Spec.PodTemplate.Tolerations).To(Equal(build.Spec.Scheduling.Tolerations))
+				Expect(*got.Spec.PodTemplate.PriorityClassName).To(Equal("build"))
+				Expect(got.Spec.PodTemplate.RuntimeClassName).To(BeNil())
+				Expect(got.Spec.PodTemplate.Affinity).To(BeNil())
+			})
+
+			It("should use a priority class of the BuildRun that the strategy allows", func() {
+				priorityClassName := "build-urgent"
+				buildRun.Spec.Scheduling.PriorityClassName = &priorityClassName
+
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+				Expect(*got.Spec.PodTemplate.PriorityClassName).To(Equal("build-urgent"))
+			})
+
+			It("should fail for a toleration of the BuildRun that the strategy does not allow", func() {
+				buildRun.Spec.Scheduling.Tolerations = []corev1.Toleration{{Key: "app", Value: "latency-sensitive", Effect: corev1.TaintEffectNoSchedule}}
+
+				_, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(MatchError("toleration of taint app=latency-sensitive:NoSchedule is not allowed by the strategy"))
+			})
+
+			It("should fail for a runtime class of the Build when the strategy does not allow any", func() {
+				runtimeClassName := "kata"
+				build.Spec.Scheduling.RuntimeClassName = &runtimeClassName
+
+				_, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(MatchError("runtimeClassName kata is not allowed by the strategy"))
+			})
 		})
 
 		Context("when the taskrun is generated by special settings", func() {
@@ -378,5 +763,139 @@ var _ = Describe("GenerateTaskrun", func() {
 				Expect(paramOutputImageFound).To(BeTrue())
 			})
 		})
+
+		Context("when the strategy defines an array parameter", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithOutput))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.BuildahBuildStrategySingleStep))
+				Expect(err).To(BeNil())
+
+				buildStrategy.Spec.Parameters = append(buildStrategy.Spec.Parameters, buildv1alpha1.Parameter{
+					Name:     "build-args",
+					Type:     buildv1alpha1.ParameterTypeArray,
+					Defaults: &[]string{},
+					Pattern:  "^[A-Z_]+=",
+				})
+				build.Spec.ParamValues = []buildv1alpha1.ParamValue{{Name: "build-args", Values: []string{"GO_VERSION=1.16", "CGO_ENABLED=0"}}}
+			})
+
+			It("should map the array values to a Tekton array parameter", func() {
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				paramSpecFound := false
+				for _, paramSpec := range got.Spec.TaskSpec.Params {
+					if paramSpec.Name == "build-args" {
+						paramSpecFound = true
+						Expect(paramSpec.Type).To(Equal(v1beta1.ParamTypeArray))
+						Expect(paramSpec.Default.Type).To(Equal(v1beta1.ParamTypeArray))
+					}
+				}
+				Expect(paramSpecFound).To(BeTrue())
+
+				paramFound := false
+				for _, param := range got.Spec.Params {
+					if param.Name == "build-args" {
+						paramFound = true
+						Expect(param.Value.Type).To(Equal(v1beta1.ParamTypeArray))
+						Expect(param.Value.ArrayVal).To(Equal([]string{"GO_VERSION=1.16", "CGO_ENABLED=0"}))
+					}
+				}
+				Expect(paramFound).To(BeTrue())
+			})
+
+			It("should fail when a BuildRun value does not match the pattern", func() {
+				buildRun.Spec.ParamValues = []buildv1alpha1.ParamValue{{Name: "build-args", Values: []string{"invalid"}}}
+
+				_, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(MatchError(`value "invalid" of parameter build-args does not match the pattern ^[A-Z_]+=`))
+			})
+		})
+
+		Context("when a param value references a Secret", func() {
+			BeforeEach(func() {
+				build, err = ctl.LoadBuildYAML([]byte(test.BuildahBuildWithOutput))
+				Expect(err).To(BeNil())
+
+				buildRun, err = ctl.LoadBuildRunFromBytes([]byte(test.BuildahBuildRunWithSA))
+				Expect(err).To(BeNil())
+
+				buildStrategy, err = ctl.LoadBuildStrategyFromBytes([]byte(test.BuildahBuildStrategySingleStep))
+				Expect(err).To(BeNil())
+
+				buildStrategy.Spec.Parameters = append(buildStrategy.Spec.Parameters, buildv1alpha1.Parameter{Name: "registry-token"})
+				build.Spec.ParamValues = []buildv1alpha1.ParamValue{{
+					Name: "registry-token",
+					ValueFrom: &buildv1alpha1.ParamValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+						Key:                  "token",
+					}},
+				}}
+			})
+
+			It("should pass the value as an environment variable of the strategy steps", func() {
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				paramFound := false
+				for _, param := range got.Spec.Params {
+					if param.Name == "registry-token" {
+						paramFound = true
+						Expect(param.Value.StringVal).To(Equal("$(SHP_PARAM_REGISTRY_TOKEN)"))
+					}
+				}
+				Expect(paramFound).To(BeTrue())
+
+				for _, step := range got.Spec.TaskSpec.Steps {
+					if step.Name != buildStrategy.Spec.BuildSteps[0].Name {
+						continue
+					}
+					Expect(step.Env).To(ContainElement(corev1.EnvVar{
+						Name: "SHP_PARAM_REGISTRY_TOKEN",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+							Key:                  "token",
+						}},
+					}))
+				}
+				for _, env := range buildStrategy.Spec.BuildSteps[0].Env {
+					Expect(env.Name).ToNot(Equal("SHP_PARAM_REGISTRY_TOKEN"))
+				}
+			})
+
+			It("should add the environment variable before the ones that reference it", func() {
+				buildStrategy.Spec.BuildSteps[0].Env = append(buildStrategy.Spec.BuildSteps[0].Env, corev1.EnvVar{Name: "REGISTRY_TOKEN", Value: "$(params.registry-token)"})
+
+				got, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(BeNil())
+
+				for _, step := range got.Spec.TaskSpec.Steps {
+					if step.Name != buildStrategy.Spec.BuildSteps[0].Name {
+						continue
+					}
+					Expect(step.Env[0].Name).To(Equal("SHP_PARAM_REGISTRY_TOKEN"))
+					Expect(step.Env).To(ContainElement(corev1.EnvVar{Name: "REGISTRY_TOKEN", Value: "$(params.registry-token)"}))
+				}
+			})
+
+			It("should fail when a strategy step uses the value outside of its command, args and env", func() {
+				buildStrategy.Spec.BuildSteps[0].WorkingDir = "/workspace/$(params.registry-token)"
+
+				_, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(MatchError(fmt.Sprintf("parameter registry-token references a secret, step %s can only use it in its command, args and env", buildStrategy.Spec.BuildSteps[0].Name)))
+			})
+
+			It("should fail for a value that references an unresolved ConfigMap", func() {
+				build.Spec.ParamValues[0].ValueFrom = &buildv1alpha1.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "token"}}
+
+				_, err = resources.GenerateTaskRun(config.NewDefaultConfig(), build, buildRun, serviceAccountName, buildStrategy)
+				Expect(err).To(MatchError("the value of parameter registry-token was not resolved"))
+			})
+		})
 	})
 })
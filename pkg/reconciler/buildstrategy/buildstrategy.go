@@ -7,8 +7,12 @@ package buildstrategy
 import (
 	"context"
 
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/validate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -46,6 +50,28 @@ func (r *ReconcileBuildStrategy) Reconcile(request reconcile.Request) (reconcile
 	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
 	defer cancel()
 
-	ctxlog.Info(ctx, "reconciling BuildStrategy", "namespace", request.Namespace, "name", request.Name)
+	ctxlog.Debug(ctx, "start reconciling BuildStrategy", "namespace", request.Namespace, "name", request.Name)
+
+	bs := &buildv1alpha1.BuildStrategy{}
+	if err := r.client.Get(ctx, request.NamespacedName, bs); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debug(ctx, "finish reconciling BuildStrategy, BuildStrategy was not found", "namespace", request.Namespace, "name", request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	condition := validate.BuildStrategyCondition(bs)
+	bs.Status.ObservedGeneration = bs.Generation
+	bs.Status.SetCondition(condition)
+	if err := r.client.Status().Update(ctx, bs); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if condition.Status != corev1.ConditionTrue {
+		ctxlog.Info(ctx, "BuildStrategy is not valid", "namespace", request.Namespace, "name", request.Name, "reason", condition.Reason, "message", condition.Message)
+	}
+
+	ctxlog.Debug(ctx, "finish reconciling BuildStrategy", "namespace", request.Namespace, "name", request.Name)
 	return reconcile.Result{}, nil
 }
@@ -7,13 +7,17 @@ package clusterbuildstrategy
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/validate"
 )
 
 // blank assignment to verify that ReconcileClusterBuildStrategy implements reconcile.Reconciler
@@ -47,6 +51,28 @@ func (r *ReconcileClusterBuildStrategy) Reconcile(request reconcile.Request) (re
 	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
 	defer cancel()
 
-	ctxlog.Info(ctx, "reconciling ClusterBuildStrategy", "name", request.Name)
+	ctxlog.Debug(ctx, "start reconciling ClusterBuildStrategy", "name", request.Name)
+
+	cbs := &buildv1alpha1.ClusterBuildStrategy{}
+	if err := r.client.Get(ctx, request.NamespacedName, cbs); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debug(ctx, "finish reconciling ClusterBuildStrategy, ClusterBuildStrategy was not found", "name", request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	condition := validate.BuildStrategyCondition(cbs)
+	cbs.Status.ObservedGeneration = cbs.Generation
+	cbs.Status.SetCondition(condition)
+	if err := r.client.Status().Update(ctx, cbs); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if condition.Status != corev1.ConditionTrue {
+		ctxlog.Info(ctx, "ClusterBuildStrategy is not valid", "name", request.Name, "reason", condition.Reason, "message", condition.Message)
+	}
+
+	ctxlog.Debug(ctx, "finish reconciling ClusterBuildStrategy", "name", request.Name)
 	return reconcile.Result{}, nil
 }
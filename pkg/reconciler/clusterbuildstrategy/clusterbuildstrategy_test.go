@@ -10,20 +10,31 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/config"
 	"github.com/shipwright-io/build/pkg/controller/fakes"
 	"github.com/shipwright-io/build/pkg/ctxlog"
 	"github.com/shipwright-io/build/pkg/reconciler/clusterbuildstrategy"
+	"github.com/shipwright-io/build/test"
 )
 
 var _ = Describe("Reconcile ClusterBuildStrategy", func() {
 	var (
 		manager           *fakes.FakeManager
+		client            *fakes.FakeClient
+		statusWriter      *fakes.FakeStatusWriter
 		reconciler        reconcile.Reconciler
 		request           reconcile.Request
+		ctl               test.Catalog
+		strategy          *build.ClusterBuildStrategy
 		buildStrategyName string
 	)
 
@@ -33,6 +44,29 @@ var _ = Describe("Reconcile ClusterBuildStrategy", func() {
 		// Fake the manager and get a reconcile Request
 		manager = &fakes.FakeManager{}
 		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: buildStrategyName}}
+
+		strategy = ctl.DefaultClusterBuildStrategy()
+		strategy.Name = buildStrategyName
+		strategy.Generation = 2
+		strategy.Spec.BuildSteps = []build.BuildStep{
+			{Container: corev1.Container{Name: "build", Image: "busybox", Args: []string{"$(params.shp-output-image)"}}},
+		}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, _ types.NamespacedName, object runtime.Object) error {
+			switch object := object.(type) {
+			case *build.ClusterBuildStrategy:
+				if strategy == nil {
+					return errors.NewNotFound(schema.GroupResource{}, buildStrategyName)
+				}
+				strategy.DeepCopyInto(object)
+				return nil
+			}
+			return errors.NewNotFound(schema.GroupResource{}, "schema not found")
+		})
+		statusWriter = &fakes.FakeStatusWriter{}
+		client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+		manager.GetClientReturns(client)
 	})
 
 	JustBeforeEach(func() {
@@ -41,12 +75,45 @@ var _ = Describe("Reconcile ClusterBuildStrategy", func() {
 		reconciler = clusterbuildstrategy.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
 	})
 
+	// returns the ClusterBuildStrategy of the last status update
+	updatedStrategy := func() *build.ClusterBuildStrategy {
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, object, _ := statusWriter.UpdateArgsForCall(0)
+		updated, ok := object.(*build.ClusterBuildStrategy)
+		Expect(ok).To(BeTrue())
+		return updated
+	}
+
 	Describe("Reconcile", func() {
 		Context("when request a new ClusterBuildStrategy", func() {
-			It("succeed without any error", func() {
+			It("marks a valid ClusterBuildStrategy as ready", func() {
+				result, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reconcile.Result{}).To(Equal(result))
+
+				updated := updatedStrategy()
+				Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+				Expect(updated.Status.GetCondition(build.BuildStrategyReady).GetStatus()).To(Equal(corev1.ConditionTrue))
+			})
+
+			It("marks a ClusterBuildStrategy with duplicate step names as not ready", func() {
+				strategy.Spec.BuildSteps = append(strategy.Spec.BuildSteps, strategy.Spec.BuildSteps...)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				condition := updatedStrategy().Status.GetCondition(build.BuildStrategyReady)
+				Expect(condition.GetStatus()).To(Equal(corev1.ConditionFalse))
+				Expect(condition.GetReason()).To(Equal(string(build.StrategyDuplicateStepNames)))
+			})
+
+			It("does not update a ClusterBuildStrategy that does not exist", func() {
+				strategy = nil
+
 				result, err := reconciler.Reconcile(request)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(reconcile.Result{}).To(Equal(result))
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
 			})
 		})
 	})
@@ -0,0 +1,114 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+)
+
+const (
+	namespace string = "namespace"
+	name      string = "name"
+)
+
+// Add creates a new BuildRun retention Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(ctx context.Context, c *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContext(ctx, "retention-controller")
+	return add(ctx, mgr, NewReconciler(ctx, c, mgr), c.Controllers.Build.MaxConcurrentReconciles)
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler, maxConcurrentReconciles int) error {
+	// Create the controller options
+	options := controller.Options{
+		Reconciler: r,
+	}
+	if maxConcurrentReconciles > 0 {
+		options.MaxConcurrentReconciles = maxConcurrentReconciles
+	}
+
+	// Create a new controller
+	c, err := controller.New("retention-controller", mgr, options)
+	if err != nil {
+		return err
+	}
+
+	predBuild := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			o := e.Object.(*buildv1alpha1.Build)
+			return o.Spec.Retention != nil
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			n := e.ObjectNew.(*buildv1alpha1.Build)
+
+			// Ignore updates to CR status in which case metadata.Generation does not change
+			return n.Spec.Retention != nil && e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			// Never reconcile on deletion, there is nothing we have to do
+			return false
+		},
+	}
+
+	predBuildRun := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			// The CreateFunc is also called when the controller is started and iterates over all objects,
+			// this covers BuildRuns that completed while the controller was down
+			o := e.Object.(*buildv1alpha1.BuildRun)
+			return o.Status.CompletionTime != nil
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*buildv1alpha1.BuildRun)
+			n := e.ObjectNew.(*buildv1alpha1.BuildRun)
+
+			// Only reconcile when the BuildRun completes
+			return o.Status.CompletionTime == nil && n.Status.CompletionTime != nil
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	// Watch for changes to the retention of a Build
+	if err = c.Watch(&source.Kind{Type: &buildv1alpha1.Build{}}, &handler.EnqueueRequestForObject{}, predBuild); err != nil {
+		return err
+	}
+
+	// enqueue the Build of a BuildRun that completed
+	return c.Watch(&source.Kind{Type: &buildv1alpha1.BuildRun{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+
+			buildRun := o.Object.(*buildv1alpha1.BuildRun)
+
+			// check if BuildRun is related to a Build
+			if buildRun.GetLabels() == nil || buildRun.GetLabels()[buildv1alpha1.LabelBuild] == "" {
+				return []reconcile.Request{}
+			}
+
+			return []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      buildRun.GetLabels()[buildv1alpha1.LabelBuild],
+						Namespace: buildRun.Namespace,
+					},
+				},
+			}
+		}),
+	}, predBuildRun)
+}
@@ -0,0 +1,158 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+)
+
+// blank assignment to verify that ReconcileRetention implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileRetention{}
+
+// ReconcileRetention deletes the completed BuildRuns of a Build based on its retention
+type ReconcileRetention struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	ctx    context.Context
+	config *config.Config
+	client client.Client
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(ctx context.Context, c *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileRetention{
+		ctx:    ctx,
+		config: c,
+		client: mgr.GetClient(),
+	}
+}
+
+// Reconcile deletes the completed BuildRuns of a Build that exceed the limits or the
+// time to live defined in the retention of the Build. The TaskRuns and pods of the
+// BuildRuns are deleted by the garbage collection, as they are owned by the BuildRun.
+func (r *ReconcileRetention) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	defer cancel()
+
+	ctxlog.Debug(ctx, "start reconciling Build retention", namespace, request.Namespace, name, request.Name)
+
+	b := &buildv1alpha1.Build{}
+	if err := r.client.Get(ctx, request.NamespacedName, b); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctxlog.Debug(ctx, "finish reconciling Build retention. build was not found", namespace, request.Namespace, name, request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	retention := b.Spec.Retention
+	if retention == nil {
+		return reconcile.Result{}, nil
+	}
+
+	buildRunList, err := resources.ListBuildRunsOfBuild(ctx, r.client, b.Namespace, b.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var (
+		now          = time.Now()
+		requeueAfter time.Duration
+		succeeded    []buildv1alpha1.BuildRun
+		failed       []buildv1alpha1.BuildRun
+	)
+
+	for i := range buildRunList.Items {
+		buildRun := &buildRunList.Items[i]
+		if buildRun.Status.CompletionTime == nil {
+			continue
+		}
+
+		if retention.TTLAfterFinished != nil {
+			expiresIn := buildRun.Status.CompletionTime.Add(retention.TTLAfterFinished.Duration).Sub(now)
+			if expiresIn <= 0 {
+				if err := r.deleteBuildRun(ctx, buildRun, fmt.Sprintf("it completed more than %s ago", retention.TTLAfterFinished.Duration)); err != nil {
+					return reconcile.Result{}, err
+				}
+				continue
+			}
+
+			// reconcile again once the next BuildRun expires
+			if requeueAfter == 0 || expiresIn < requeueAfter {
+				requeueAfter = expiresIn
+			}
+		}
+
+		switch buildRun.Status.GetCondition(buildv1alpha1.Succeeded).GetStatus() {
+		case corev1.ConditionTrue:
+			succeeded = append(succeeded, *buildRun)
+		case corev1.ConditionFalse:
+			failed = append(failed, *buildRun)
+		}
+	}
+
+	if retention.SucceededLimit != nil {
+		if err := r.deleteOldestBuildRuns(ctx, succeeded, *retention.SucceededLimit, "succeeded"); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if retention.FailedLimit != nil {
+		if err := r.deleteOldestBuildRuns(ctx, failed, *retention.FailedLimit, "failed"); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	ctxlog.Debug(ctx, "finishing reconciling Build retention", namespace, request.Namespace, name, request.Name)
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// deleteOldestBuildRuns deletes the BuildRuns that completed first, until at most limit BuildRuns are left
+func (r *ReconcileRetention) deleteOldestBuildRuns(ctx context.Context, buildRuns []buildv1alpha1.BuildRun, limit int32, kind string) error {
+	if limit < 0 {
+		limit = 0
+	}
+
+	if len(buildRuns) <= int(limit) {
+		return nil
+	}
+
+	sort.Slice(buildRuns, func(i, j int) bool {
+		return buildRuns[i].Status.CompletionTime.Before(buildRuns[j].Status.CompletionTime)
+	})
+
+	for i := range buildRuns[:len(buildRuns)-int(limit)] {
+		if err := r.deleteBuildRun(ctx, &buildRuns[i], fmt.Sprintf("the limit of %d %s BuildRuns is exceeded", limit, kind)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcileRetention) deleteBuildRun(ctx context.Context, buildRun *buildv1alpha1.BuildRun, reason string) error {
+	ctxlog.Info(ctx, fmt.Sprintf("deleting BuildRun %s, %s", buildRun.Name, reason), namespace, buildRun.Namespace, name, buildRun.Name)
+	if err := r.client.Delete(ctx, buildRun, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
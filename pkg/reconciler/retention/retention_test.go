@@ -0,0 +1,169 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retention_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/config"
+	"github.com/shipwright-io/build/pkg/controller/fakes"
+	"github.com/shipwright-io/build/pkg/ctxlog"
+	"github.com/shipwright-io/build/pkg/reconciler/retention"
+	"github.com/shipwright-io/build/test"
+)
+
+var _ = Describe("Reconcile Build retention", func() {
+	var (
+		manager     *fakes.FakeManager
+		reconciler  reconcile.Reconciler
+		request     reconcile.Request
+		client      *fakes.FakeClient
+		ctl         test.Catalog
+		buildSample *build.Build
+		buildRuns   []build.BuildRun
+		now         time.Time
+	)
+
+	// returns a BuildRun of the Build that completed with the given status at the given offset
+	completedBuildRun := func(buildRunName string, status corev1.ConditionStatus, offset time.Duration) build.BuildRun {
+		buildRun := ctl.DefaultBuildRun(buildRunName, buildSample.Name)
+		buildRun.Namespace = buildSample.Namespace
+		buildRun.Labels = map[string]string{build.LabelBuild: buildSample.Name}
+		buildRun.Status.CompletionTime = &metav1.Time{Time: now.Add(offset)}
+		buildRun.Status.SetCondition(&build.Condition{Type: build.Succeeded, Status: status})
+		return *buildRun
+	}
+
+	// returns the names of the deleted BuildRuns
+	deletedBuildRuns := func() []string {
+		var names []string
+		for i := 0; i < client.DeleteCallCount(); i++ {
+			_, object, _ := client.DeleteArgsForCall(i)
+			names = append(names, object.(*build.BuildRun).Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+
+		buildSample = ctl.DefaultBuild("foobar-build", "foobar-strategy", build.ClusterBuildStrategyKind)
+		buildSample.Namespace = "default"
+		buildRuns = nil
+
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: buildSample.Name, Namespace: buildSample.Namespace}}
+
+		client = &fakes.FakeClient{}
+		client.GetCalls(func(_ context.Context, nn types.NamespacedName, object runtime.Object) error {
+			switch object := object.(type) {
+			case *build.Build:
+				buildSample.DeepCopyInto(object)
+				return nil
+			}
+			return k8serrors.NewNotFound(schema.GroupResource{}, nn.Name)
+		})
+		client.ListCalls(func(_ context.Context, object runtime.Object, _ ...crc.ListOption) error {
+			switch list := object.(type) {
+			case *build.BuildRunList:
+				list.Items = buildRuns
+			}
+			return nil
+		})
+
+		manager = &fakes.FakeManager{}
+		manager.GetClientReturns(client)
+	})
+
+	JustBeforeEach(func() {
+		testCtx := ctxlog.NewContext(context.TODO(), "fake-logger")
+		reconciler = retention.NewReconciler(testCtx, config.NewDefaultConfig(), manager)
+	})
+
+	It("does nothing for a Build without retention", func() {
+		buildRuns = []build.BuildRun{completedBuildRun("first", corev1.ConditionTrue, -time.Hour)}
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(client.ListCallCount()).To(Equal(0))
+		Expect(client.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("does not fail when the Build does not exist", func() {
+		client.GetReturns(k8serrors.NewNotFound(schema.GroupResource{}, buildSample.Name))
+		client.GetCalls(nil)
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("deletes the oldest BuildRuns that exceed the succeeded and failed limits", func() {
+		succeededLimit, failedLimit := int32(1), int32(2)
+		buildSample.Spec.Retention = &build.BuildRetention{
+			SucceededLimit: &succeededLimit,
+			FailedLimit:    &failedLimit,
+		}
+		buildRuns = []build.BuildRun{
+			completedBuildRun("succeeded-new", corev1.ConditionTrue, -time.Minute),
+			completedBuildRun("succeeded-old", corev1.ConditionTrue, -time.Hour),
+			completedBuildRun("failed-oldest", corev1.ConditionFalse, -3*time.Hour),
+			completedBuildRun("failed-old", corev1.ConditionFalse, -2*time.Hour),
+			completedBuildRun("failed-new", corev1.ConditionFalse, -time.Minute),
+		}
+
+		running := ctl.DefaultBuildRun("running", buildSample.Name)
+		buildRuns = append(buildRuns, *running)
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBuildRuns()).To(ConsistOf("succeeded-old", "failed-oldest"))
+	})
+
+	It("deletes all completed BuildRuns of a kind with a limit of zero", func() {
+		succeededLimit := int32(0)
+		buildSample.Spec.Retention = &build.BuildRetention{
+			SucceededLimit: &succeededLimit,
+		}
+		buildRuns = []build.BuildRun{
+			completedBuildRun("succeeded-new", corev1.ConditionTrue, -time.Minute),
+			completedBuildRun("succeeded-old", corev1.ConditionTrue, -time.Hour),
+			completedBuildRun("failed", corev1.ConditionFalse, -time.Hour),
+		}
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBuildRuns()).To(ConsistOf("succeeded-new", "succeeded-old"))
+	})
+
+	It("deletes the BuildRuns that completed longer ago than the TTL and requeues for the others", func() {
+		buildSample.Spec.Retention = &build.BuildRetention{
+			TTLAfterFinished: &metav1.Duration{Duration: time.Hour},
+		}
+		buildRuns = []build.BuildRun{
+			completedBuildRun("expired", corev1.ConditionTrue, -2*time.Hour),
+			completedBuildRun("recent", corev1.ConditionFalse, -30*time.Minute),
+		}
+
+		result, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deletedBuildRuns()).To(ConsistOf("expired"))
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Minute))
+	})
+})
@@ -0,0 +1,225 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources"
+)
+
+// placeholderRegex matches the $(params.*), $(inputs.params.*), $(results.*) and
+// $(build.*) placeholders in the steps of a strategy
+var placeholderRegex = regexp.MustCompile(`\$\((params|inputs\.params|results|build)\.([^)]+)\)`)
+
+// strategyFailure holds the result of a failed strategy check
+type strategyFailure struct {
+	reason  build.BuildStrategyReason
+	message string
+}
+
+// BuildStrategyCondition statically validates the steps and parameters of a
+// strategy and returns the resulting Ready condition. The condition is False for
+// duplicate step names, parameters that collide with system reserved parameters,
+// invalid parameter definitions, caches without volume mounts, and references to
+// undefined parameters or results. The usage of the deprecated $(build.*)
+// placeholders is reported in the reason of a True condition.
+func BuildStrategyCondition(strategy build.BuilderStrategy) *build.Condition {
+	var failures []strategyFailure
+
+	if duplicates := duplicateStepNames(strategy.GetBuildSteps()); len(duplicates) > 0 {
+		failures = append(failures, strategyFailure{
+			reason:  build.StrategyDuplicateStepNames,
+			message: fmt.Sprintf("duplicate step names: %s", strings.Join(duplicates, ",")),
+		})
+	}
+
+	definedParams := map[string]bool{}
+	var restrictedParams []string
+	for _, p := range strategy.GetParameters() {
+		definedParams[p.Name] = true
+		if resources.IsSystemReservedParameter(p.Name) {
+			restrictedParams = append(restrictedParams, p.Name)
+		}
+	}
+	if len(restrictedParams) > 0 {
+		failures = append(failures, strategyFailure{
+			reason:  build.StrategyRestrictedParametersInUse,
+			message: fmt.Sprintf("restricted parameters in use: %s", strings.Join(restrictedParams, ",")),
+		})
+	}
+
+	for _, p := range strategy.GetParameters() {
+		if message := invalidParameterDefinition(p); message != "" {
+			failures = append(failures, strategyFailure{
+				reason:  build.StrategyInvalidParameterDefinition,
+				message: message,
+			})
+		}
+	}
+
+	if undefinedCaches := undefinedCacheVolumes(strategy); len(undefinedCaches) > 0 {
+		failures = append(failures, strategyFailure{
+			reason:  build.StrategyUndefinedCacheVolume,
+			message: fmt.Sprintf("caches without a volume mount in the steps: %s", strings.Join(undefinedCaches, ",")),
+		})
+	}
+
+	undefinedParams := map[string]bool{}
+	undefinedResults := map[string]bool{}
+	deprecatedPlaceholders := map[string]bool{}
+	for _, step := range strategy.GetBuildSteps() {
+		for _, placeholder := range placeholders(step.Container) {
+			kind, reference := placeholder[1], placeholder[2]
+			switch kind {
+			case "params", "inputs.params":
+				reference = strings.TrimSuffix(reference, "[*]")
+				if !definedParams[reference] && !resources.IsSystemParameter(reference) {
+					undefinedParams[reference] = true
+				}
+			case "results":
+				reference = strings.TrimSuffix(reference, ".path")
+				if !resources.IsSystemResult(reference) {
+					undefinedResults[reference] = true
+				}
+			case "build":
+				deprecatedPlaceholders[placeholder[0]] = true
+			}
+		}
+	}
+	if len(undefinedParams) > 0 {
+		failures = append(failures, strategyFailure{
+			reason:  build.StrategyUndefinedParameterReference,
+			message: fmt.Sprintf("references to undefined parameters: %s", strings.Join(sortedKeys(undefinedParams), ",")),
+		})
+	}
+	if len(undefinedResults) > 0 {
+		failures = append(failures, strategyFailure{
+			reason:  build.StrategyUndefinedResultReference,
+			message: fmt.Sprintf("references to undefined results: %s", strings.Join(sortedKeys(undefinedResults), ",")),
+		})
+	}
+
+	condition := &build.Condition{
+		Type:               build.BuildStrategyReady,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	switch {
+	case len(failures) > 0:
+		messages := make([]string, 0, len(failures))
+		for _, failure := range failures {
+			messages = append(messages, failure.message)
+		}
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = string(failures[0].reason)
+		condition.Message = strings.Join(messages, "; ")
+
+	case len(deprecatedPlaceholders) > 0:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = string(build.StrategyDeprecatedPlaceholdersInUse)
+		condition.Message = fmt.Sprintf("deprecated placeholders in use: %s", strings.Join(sortedKeys(deprecatedPlaceholders), ","))
+
+	default:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = string(build.StrategySucceeded)
+		condition.Message = build.AllValidationsSucceeded
+	}
+
+	return condition
+}
+
+// invalidParameterDefinition returns a message if the pattern of the parameter does not
+// compile, or if its defaults do not match its type, allowed values and pattern
+func invalidParameterDefinition(p build.Parameter) string {
+	if p.Pattern != "" {
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Sprintf("parameter %s has an invalid pattern: %v", p.Name, err)
+		}
+	}
+
+	defaults := build.ParamValue{Name: p.Name}
+	switch p.GetType() {
+	case build.ParameterTypeArray:
+		if p.Default != nil {
+			return fmt.Sprintf("parameter %s is of type array, its default must be specified in defaults", p.Name)
+		}
+		if p.Defaults == nil {
+			return ""
+		}
+		defaults.Values = *p.Defaults
+
+	default:
+		if p.Defaults != nil {
+			return fmt.Sprintf("parameter %s is of type string, its default must be specified in default", p.Name)
+		}
+		if p.Default == nil {
+			return ""
+		}
+		defaults.Value = *p.Default
+	}
+
+	if reason, message := resources.ValidateParamValue(p, defaults); reason != "" {
+		return fmt.Sprintf("invalid default of parameter %s: %s", p.Name, message)
+	}
+	return ""
+}
+
+// undefinedCacheVolumes returns the names of the caches that no step mounts as a volume
+func undefinedCacheVolumes(strategy build.BuilderStrategy) []string {
+	mounted := map[string]bool{}
+	for _, step := range strategy.GetBuildSteps() {
+		for _, volumeMount := range step.VolumeMounts {
+			mounted[volumeMount.Name] = true
+		}
+	}
+
+	undefined := []string{}
+	for _, cache := range strategy.GetCaches() {
+		if !mounted[cache.Name] {
+			undefined = append(undefined, cache.Name)
+		}
+	}
+	return undefined
+}
+
+// duplicateStepNames returns the names that are used by more than one step
+func duplicateStepNames(steps []build.BuildStep) []string {
+	seen := map[string]int{}
+	duplicates := []string{}
+	for _, step := range steps {
+		seen[step.Name]++
+		if seen[step.Name] == 2 {
+			duplicates = append(duplicates, step.Name)
+		}
+	}
+	return duplicates
+}
+
+// placeholders returns the submatches of all placeholders used in the fields of a step
+func placeholders(container corev1.Container) [][]string {
+	data, err := json.Marshal(container)
+	if err != nil {
+		return nil
+	}
+	return placeholderRegex.FindAllStringSubmatch(string(data), -1)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+package validate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+func TestBuildStrategyCondition(t *testing.T) {
+	step := func(name string, args ...string) build.BuildStep {
+		return build.BuildStep{Container: corev1.Container{Name: name, Image: "busybox", Args: args}}
+	}
+
+	testCases := []struct {
+		description string
+		steps       []build.BuildStep
+		parameters  []build.Parameter
+		caches      []build.StrategyCache
+		status      corev1.ConditionStatus
+		reason      build.BuildStrategyReason
+		message     string
+	}{{
+		description: "valid strategy",
+		steps:       []build.BuildStep{step("build", "--param=$(params.sleep-time)", "--image=$(params.shp-output-image)", "$(results.shp-image-digest.path)")},
+		parameters:  []build.Parameter{{Name: "sleep-time"}},
+		status:      corev1.ConditionTrue,
+		reason:      build.StrategySucceeded,
+		message:     build.AllValidationsSucceeded,
+	}, {
+		description: "duplicate step names",
+		steps:       []build.BuildStep{step("build"), step("build"), step("build")},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyDuplicateStepNames,
+		message:     "duplicate step names: build",
+	}, {
+		description: "system reserved parameter",
+		steps:       []build.BuildStep{step("build")},
+		parameters:  []build.Parameter{{Name: "shp-foo"}, {Name: "DOCKERFILE"}},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyRestrictedParametersInUse,
+		message:     "restricted parameters in use: shp-foo,DOCKERFILE",
+	}, {
+		description: "undefined parameter",
+		steps:       []build.BuildStep{step("build", "$(params.foo)", "$(inputs.params.bar)", "$(params.DOCKERFILE)")},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyUndefinedParameterReference,
+		message:     "references to undefined parameters: bar,foo",
+	}, {
+		description: "undefined result",
+		steps:       []build.BuildStep{step("build", "$(results.image-digest.path)")},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyUndefinedResultReference,
+		message:     "references to undefined results: image-digest",
+	}, {
+		description: "multiple failures",
+		steps:       []build.BuildStep{step("build", "$(params.foo)"), step("build")},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyDuplicateStepNames,
+		message:     "duplicate step names: build; references to undefined parameters: foo",
+	}, {
+		description: "invalid parameter definitions",
+		steps:       []build.BuildStep{step("build")},
+		parameters: []build.Parameter{
+			{Name: "pattern", Pattern: "("},
+			{Name: "array", Type: build.ParameterTypeArray, Default: &[]string{"a"}[0]},
+			{Name: "driver", AllowedValues: []string{"overlay", "vfs"}, Default: &[]string{"btrfs"}[0]},
+		},
+		status: corev1.ConditionFalse,
+		reason: build.StrategyInvalidParameterDefinition,
+		message: "parameter pattern has an invalid pattern: error parsing regexp: missing closing ): `(`; " +
+			"parameter array is of type array, its default must be specified in defaults; " +
+			`invalid default of parameter driver: value "btrfs" of parameter driver is not one of the allowed values: overlay,vfs`,
+	}, {
+		description: "cache without volume mount",
+		steps:       []build.BuildStep{step("build")},
+		caches:      []build.StrategyCache{{Name: "go-cache"}},
+		status:      corev1.ConditionFalse,
+		reason:      build.StrategyUndefinedCacheVolume,
+		message:     "caches without a volume mount in the steps: go-cache",
+	}, {
+		description: "deprecated placeholders",
+		steps:       []build.BuildStep{step("build", "$(build.dockerfile)", "$(build.output.image)")},
+		status:      corev1.ConditionTrue,
+		reason:      build.StrategyDeprecatedPlaceholdersInUse,
+		message:     "deprecated placeholders in use: $(build.dockerfile),$(build.output.image)",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			strategy := &build.BuildStrategy{Spec: build.BuildStrategySpec{BuildSteps: tc.steps, Parameters: tc.parameters, Caches: tc.caches}}
+
+			condition := BuildStrategyCondition(strategy)
+			if condition.Type != build.BuildStrategyReady {
+				t.Errorf("expected condition type %s, got %s", build.BuildStrategyReady, condition.Type)
+			}
+			if condition.Status != tc.status {
+				t.Errorf("expected status %s, got %s", tc.status, condition.Status)
+			}
+			if condition.Reason != string(tc.reason) {
+				t.Errorf("expected reason %s, got %s", tc.reason, condition.Reason)
+			}
+			if condition.Message != tc.message {
+				t.Errorf("expected message %q, got %q", tc.message, condition.Message)
+			}
+		})
+	}
+}
+
+func TestBuildStrategyConditionOfSamples(t *testing.T) {
+	err := filepath.Walk("../../samples/buildstrategy", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".yaml") {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		strategy := &build.ClusterBuildStrategy{}
+		if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(data)), len(data)).Decode(strategy); err != nil {
+			return err
+		}
+
+		if condition := BuildStrategyCondition(strategy); condition.Status != corev1.ConditionTrue {
+			t.Errorf("expected sample %s to be valid, got reason %s: %s", path, condition.Reason, condition.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/image"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// OutputRef contains all required fields
+// to validate a Build spec output definition
+type OutputRef struct {
+	Build *build.Build // build instance for analysis
+}
+
+// ValidatePath implements BuildPath interface and validates that the additional tags
+// of the output image result in valid tags once their placeholders are resolved
+func (o *OutputRef) ValidatePath(_ context.Context) error {
+	if err := AdditionalTags(o.Build.Spec.Output.AdditionalTags); err != nil {
+		o.Build.Status.Reason = build.InvalidAdditionalTag
+		o.Build.Status.Message = err.Error()
+	}
+
+	return nil
+}
+
+// AdditionalTags validates that the additional tags of an output image result in valid
+// tags once their placeholders are resolved, it also applies to the output of a BuildRun
+func AdditionalTags(tags []string) error {
+	// the values are only known when the BuildRun runs, sample values are sufficient
+	// to verify that the templates produce valid tags. The BuildRun name has the maximum
+	// length of an object name, so that the longest possible name is covered.
+	sampleValues := image.TagValues{
+		CommitSha:    "0000000000000000000000000000000000000000",
+		BuildRunName: strings.Repeat("b", validation.DNS1123SubdomainMaxLength),
+		Timestamp:    time.Now(),
+	}
+
+	for _, tag := range tags {
+		if _, err := image.ResolveTag(tag, sampleValues); err != nil {
+			return fmt.Errorf("the additional tag %q of the output image is invalid", tag)
+		}
+	}
+
+	return nil
+}
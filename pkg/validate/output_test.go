@@ -0,0 +1,61 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+package validate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+func TestOutputRef_ValidatePath(t *testing.T) {
+	testCases := []struct {
+		description    string
+		additionalTags []string
+		expectedReason build.BuildReason
+	}{{
+		description:    "no additional tags",
+		expectedReason: build.SucceedStatus,
+	}, {
+		description:    "static and templated tags",
+		additionalTags: []string{"latest", "$(commit-sha-short)", "$(buildrun-name)", "build-$(timestamp)"},
+		expectedReason: build.SucceedStatus,
+	}, {
+		description:    "tag with invalid characters",
+		additionalTags: []string{"latest", "feature/$(commit-sha)"},
+		expectedReason: build.InvalidAdditionalTag,
+	}, {
+		description:    "BuildRun name with a long static tag part",
+		additionalTags: []string{"release-candidate-$(buildrun-name)"},
+		expectedReason: build.SucceedStatus,
+	}, {
+		description:    "static tag part exceeding the maximum length",
+		additionalTags: []string{strings.Repeat("v", 120) + "-$(timestamp)"},
+		expectedReason: build.InvalidAdditionalTag,
+	}, {
+		description:    "empty tag",
+		additionalTags: []string{""},
+		expectedReason: build.InvalidAdditionalTag,
+	}}
+
+	for _, tc := range testCases {
+		b := &build.Build{Spec: build.BuildSpec{Output: build.Image{
+			Image:          "quay.io/shipwright/sample",
+			AdditionalTags: tc.additionalTags,
+		}}}
+		b.Status.Reason = build.SucceedStatus
+
+		o := &OutputRef{Build: b}
+		if err := o.ValidatePath(context.TODO()); err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.description, err)
+		}
+
+		t.Logf("Test: '%s', Reason: '%s'", tc.description, b.Status.Reason)
+		if b.Status.Reason != tc.expectedReason {
+			t.Fatalf("%s: expectedReason='%s', reason='%s'", tc.description, tc.expectedReason, b.Status.Reason)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright The Shipwright Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ParamValuesRef contains all required fields
+// to validate the references of a Build spec paramValues definition
+type ParamValuesRef struct {
+	Build  *build.Build
+	Client client.Client
+}
+
+// ValidatePath implements BuildPath interface and validates
+// that the ConfigMaps and Secrets referenced by parameter values
+// exist and contain the referenced keys
+func (p ParamValuesRef) ValidatePath(ctx context.Context) error {
+	for _, paramValue := range p.Build.Spec.ParamValues {
+		if paramValue.ValueFrom == nil {
+			continue
+		}
+
+		var (
+			message string
+			err     error
+		)
+
+		switch {
+		case paramValue.ValueFrom.ConfigMapKeyRef != nil:
+			message, err = p.validateConfigMapKeyRef(ctx, paramValue.Name, paramValue.ValueFrom.ConfigMapKeyRef)
+		case paramValue.ValueFrom.SecretKeyRef != nil:
+			message, err = p.validateSecretKeyRef(ctx, paramValue.Name, paramValue.ValueFrom.SecretKeyRef)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if message != "" {
+			p.Build.Status.Reason = build.ParamValueRefNotFound
+			p.Build.Status.Message = message
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (p ParamValuesRef) validateConfigMapKeyRef(ctx context.Context, paramName string, ref *corev1.ConfigMapKeySelector) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: p.Build.Namespace}, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	} else if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("configMap %s referenced by parameter %s not found", ref.Name, paramName), nil
+	}
+
+	if _, ok := configMap.Data[ref.Key]; !ok {
+		return fmt.Sprintf("key %s of configMap %s referenced by parameter %s not found", ref.Key, ref.Name, paramName), nil
+	}
+	return "", nil
+}
+
+func (p ParamValuesRef) validateSecretKeyRef(ctx context.Context, paramName string, ref *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: p.Build.Namespace}, secret); err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	} else if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("secret %s referenced by parameter %s not found", ref.Name, paramName), nil
+	}
+
+	if _, ok := secret.Data[ref.Key]; !ok {
+		return fmt.Sprintf("key %s of secret %s referenced by parameter %s not found", ref.Key, ref.Name, paramName), nil
+	}
+	return "", nil
+}
@@ -58,11 +58,27 @@ func (s Credentials) buildCredentialserences() map[string]build.BuildReason {
 	if s.Build.Spec.Output.Credentials != nil && s.Build.Spec.Output.Credentials.Name != "" {
 		secretRefMap[s.Build.Spec.Output.Credentials.Name] = build.SpecOutputSecretRefNotFound
 	}
+	if s.Build.Spec.Output.Signing != nil && s.Build.Spec.Output.Signing.SecretRef.Name != "" {
+		secretRefMap[s.Build.Spec.Output.Signing.SecretRef.Name] = build.SpecOutputSigningSecretRefNotFound
+	}
 	if s.Build.Spec.Source.Credentials != nil && s.Build.Spec.Source.Credentials.Name != "" {
 		secretRefMap[s.Build.Spec.Source.Credentials.Name] = build.SpecSourceSecretRefNotFound
 	}
+	if s.Build.Spec.Source.Git != nil && s.Build.Spec.Source.Git.SubmoduleCredentials != nil && s.Build.Spec.Source.Git.SubmoduleCredentials.Name != "" {
+		secretRefMap[s.Build.Spec.Source.Git.SubmoduleCredentials.Name] = build.SpecSourceSecretRefNotFound
+	}
+	if s.Build.Spec.Source.Git != nil && s.Build.Spec.Source.Git.Verify != nil && s.Build.Spec.Source.Git.Verify.SecretRef != nil && s.Build.Spec.Source.Git.Verify.SecretRef.Name != "" {
+		secretRefMap[s.Build.Spec.Source.Git.Verify.SecretRef.Name] = build.SpecSourceSecretRefNotFound
+	}
 	if s.Build.Spec.Builder != nil && s.Build.Spec.Builder.Credentials != nil && s.Build.Spec.Builder.Credentials.Name != "" {
 		secretRefMap[s.Build.Spec.Builder.Credentials.Name] = build.SpecBuilderSecretRefNotFound
 	}
+	if s.Build.Spec.Sources != nil {
+		for _, source := range *s.Build.Spec.Sources {
+			if source.Credentials != nil && source.Credentials.Name != "" {
+				secretRefMap[source.Credentials.Name] = build.SpecSourceSecretRefNotFound
+			}
+		}
+	}
 	return secretRefMap
 }
@@ -8,10 +8,26 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
+	"regexp"
+	"strings"
 
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 )
 
+const (
+	// defaultSourceName is the name of spec.source, which the entries of spec.sources can not use
+	defaultSourceName = "default"
+
+	// maxSourceNameLength leaves room for the prefix of the step name, which has a limit of 63 characters
+	maxSourceNameLength = 56
+)
+
+var (
+	sourceNameRegEx = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	sha256RegEx     = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+)
+
 // SourcesRef implements RuntimeRef interface to add validations for `build.spec.sources` slice.
 type SourcesRef struct {
 	Build *build.Build // build instance for analysis
@@ -25,10 +41,20 @@ func (s *SourcesRef) ValidatePath(_ context.Context) error {
 	}
 	sources := *s.Build.Spec.Sources
 
+	names := map[string]bool{}
 	for _, source := range sources {
 		if err := s.validateSourceEntry(source); err != nil {
+			s.Build.Status.Reason = build.SpecSourcesInvalid
+			s.Build.Status.Message = fmt.Sprintf("source %q is not valid: %s", source.Name, err.Error())
 			return err
 		}
+
+		if names[source.Name] {
+			s.Build.Status.Reason = build.SpecSourcesInvalid
+			s.Build.Status.Message = fmt.Sprintf("source %q is defined more than once", source.Name)
+			return fmt.Errorf("source %q is defined more than once", source.Name)
+		}
+		names[source.Name] = true
 	}
 	return nil
 }
@@ -38,12 +64,26 @@ func (s *SourcesRef) validateSourceEntry(source build.BuildSource) error {
 	if source.Name == "" {
 		return fmt.Errorf("name must be informed")
 	}
+	if !sourceNameRegEx.MatchString(source.Name) || len(source.Name) > maxSourceNameLength {
+		return fmt.Errorf("name must consist of at most %d lower case alphanumeric characters or '-'", maxSourceNameLength)
+	}
+	if source.Name == defaultSourceName {
+		return fmt.Errorf("name %q is reserved for spec.source", defaultSourceName)
+	}
 	if source.URL == "" {
 		return fmt.Errorf("URL must be informed")
 	}
 	if _, err := url.ParseRequestURI(source.URL); err != nil {
 		return err
 	}
+	if source.SHA256 != nil && !sha256RegEx.MatchString(*source.SHA256) {
+		return fmt.Errorf("sha256 must be a hex encoded SHA-256 checksum")
+	}
+	if source.TargetPath != nil {
+		if path.IsAbs(*source.TargetPath) || strings.HasPrefix(path.Clean(*source.TargetPath), "..") {
+			return fmt.Errorf("targetPath must be a path inside of the source directory")
+		}
+	}
 	return nil
 }
 
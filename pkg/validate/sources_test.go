@@ -39,6 +39,55 @@ func TestSourcesRef_ValidatePath(t *testing.T) {
 			Name: "name",
 			URL:  "invalid URL",
 		}}}},
+	}, {
+		description: "valid source with all attributes",
+		expectError: false,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name:       "sources",
+			URL:        "https://example.com/sources.tar.gz",
+			SHA256:     pointer("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"),
+			TargetPath: pointer("vendor/sources"),
+		}}}},
+	}, {
+		description: "name is not a valid step name",
+		expectError: true,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name: "My_Source",
+			URL:  "https://example.com/sources.tar.gz",
+		}}}},
+	}, {
+		description: "name is reserved for spec.source",
+		expectError: true,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name: "default",
+			URL:  "https://example.com/sources.tar.gz",
+		}}}},
+	}, {
+		description: "name is used twice",
+		expectError: true,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name: "sources",
+			URL:  "https://example.com/sources.tar.gz",
+		}, {
+			Name: "sources",
+			URL:  "https://example.com/other.tar.gz",
+		}}}},
+	}, {
+		description: "invalid checksum",
+		expectError: true,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name:   "sources",
+			URL:    "https://example.com/sources.tar.gz",
+			SHA256: pointer("md5:d41d8cd98f00b204e9800998ecf8427e"),
+		}}}},
+	}, {
+		description: "target path outside of the source directory",
+		expectError: true,
+		b: &build.Build{Spec: build.BuildSpec{Sources: &[]build.BuildSource{{
+			Name:       "sources",
+			URL:        "https://example.com/sources.tar.gz",
+			TargetPath: pointer("vendor/../../etc"),
+		}}}},
 	}}
 
 	for _, tc := range testCases {
@@ -50,5 +99,12 @@ func TestSourcesRef_ValidatePath(t *testing.T) {
 		if (tc.expectError && err == nil) || (!tc.expectError && err != nil) {
 			t.Fatalf("%s: expectError='%v', err='%v'", tc.description, tc.expectError, err)
 		}
+		if tc.expectError && tc.b.Status.Reason != build.SpecSourcesInvalid {
+			t.Fatalf("%s: expected reason '%s', got '%s'", tc.description, build.SpecSourcesInvalid, tc.b.Status.Reason)
+		}
 	}
 }
+
+func pointer(value string) *string {
+	return &value
+}
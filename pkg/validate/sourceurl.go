@@ -7,13 +7,25 @@ package validate
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 
 	build "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/ctxlog"
 	"github.com/shipwright-io/build/pkg/git"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// gitVerifyGPGPublicKeys is the key of the GPG public keys to verify a commit with
+	gitVerifyGPGPublicKeys = "gpg-public-keys"
+	// gitVerifyAllowedSigners is the key of the SSH allowed signers to verify a commit with
+	gitVerifyAllowedSigners = "allowed-signers"
+)
+
 // SourceURLRef contains all required fields
 // to validate a Build spec source definition
 type SourceURLRef struct {
@@ -25,6 +37,41 @@ type SourceURLRef struct {
 // that the spec.source.url exists. This validation only applies
 // to endpoints that do not require authentication.
 func (s SourceURLRef) ValidatePath(ctx context.Context) error {
+	source := s.Build.Spec.Source
+
+	switch {
+	case source.URL == "" && source.BundleContainer == nil:
+		s.MarkBuildStatus(s.Build, build.SpecSourceInvalid, "the source must define either a url or a bundleContainer")
+		return nil
+
+	case source.URL != "" && source.BundleContainer != nil:
+		s.MarkBuildStatus(s.Build, build.SpecSourceInvalid, "the source must not define both a url and a bundleContainer")
+		return nil
+
+	case source.BundleContainer != nil && source.Git != nil:
+		s.MarkBuildStatus(s.Build, build.SpecSourceInvalid, "the source must not define git settings for a bundleContainer")
+		return nil
+
+	case source.Git != nil && !validGitSettings(source.Git):
+		s.MarkBuildStatus(s.Build, build.SpecSourceInvalid, "the git depth must not be negative, and the sparse directories must be inside of the repository")
+		return nil
+
+	case source.Git != nil && source.Git.Verify != nil && (source.Git.Verify.SecretRef == nil) == (source.Git.Verify.ConfigMapRef == nil):
+		s.MarkBuildStatus(s.Build, build.SpecSourceInvalid, "the git verification must reference either a secretRef or a configMapRef")
+		return nil
+
+	case source.BundleContainer != nil:
+		// a source bundle image is pulled with the registry credentials, there is no repository to verify
+		return nil
+	}
+
+	if source.Git != nil && source.Git.Verify != nil && source.Git.Verify.ConfigMapRef != nil {
+		if message, err := s.validateVerifyConfigMap(ctx, source.Git.Verify.ConfigMapRef.Name); err != nil || message != "" {
+			s.MarkBuildStatus(s.Build, build.SpecSourceConfigMapRefNotFound, message)
+			return err
+		}
+	}
+
 	if s.Build.Spec.Source.Credentials == nil {
 		switch s.Build.GetAnnotations()[build.AnnotationBuildVerifyRepository] {
 		case "true":
@@ -47,6 +94,42 @@ func (s SourceURLRef) ValidatePath(ctx context.Context) error {
 	return nil
 }
 
+// validateVerifyConfigMap returns a message if the configmap with the trusted keys
+// of the commit verification does not exist or contains none of the known keys
+func (s SourceURLRef) validateVerifyConfigMap(ctx context.Context, configMapName string) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: s.Build.Namespace}, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	} else if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("referenced configMap %s not found", configMapName), nil
+	}
+
+	for _, key := range []string{gitVerifyGPGPublicKeys, gitVerifyAllowedSigners} {
+		if _, ok := configMap.Data[key]; ok {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("referenced configMap %s contains neither a %s nor an %s key", configMapName, gitVerifyGPGPublicKeys, gitVerifyAllowedSigners), nil
+}
+
+// validGitSettings returns whether the depth is not negative and the sparse
+// directories are relative paths inside of the repository
+func validGitSettings(gitSource *build.GitSource) bool {
+	if gitSource.Depth != nil && *gitSource.Depth < 0 {
+		return false
+	}
+
+	if gitSource.Sparse != nil {
+		for _, directory := range *gitSource.Sparse {
+			if directory == "" || path.IsAbs(directory) || strings.HasPrefix(path.Clean(directory), "..") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 // MarkBuildStatus updates a Build Status fields
 func (s SourceURLRef) MarkBuildStatus(build *build.Build, reason build.BuildReason, msg string) {
 	build.Status.Reason = reason
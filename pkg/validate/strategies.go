@@ -36,17 +36,13 @@ func (s Strategy) ValidatePath(ctx context.Context) error {
 				if err := s.validateBuildStrategy(ctx, s.Build.Spec.Strategy.Name, buildStrategy); err != nil {
 					return err
 				}
-				if err := s.validateBuildParams(buildStrategy.Spec.Parameters); err != nil {
-					return err
-				}
+				s.validateStrategySpec(buildStrategy.Spec)
 			case build.ClusterBuildStrategyKind:
 				clusterBuildStrategy := &build.ClusterBuildStrategy{}
 				if err := s.validateClusterBuildStrategy(ctx, s.Build.Spec.Strategy.Name, clusterBuildStrategy); err != nil {
 					return err
 				}
-				if err := s.validateBuildParams(clusterBuildStrategy.Spec.Parameters); err != nil {
-					return err
-				}
+				s.validateStrategySpec(clusterBuildStrategy.Spec)
 			default:
 				return fmt.Errorf("unknown strategy kind: %v", *s.Build.Spec.Strategy.Kind)
 			}
@@ -55,9 +51,7 @@ func (s Strategy) ValidatePath(ctx context.Context) error {
 			if err := s.validateBuildStrategy(ctx, s.Build.Spec.Strategy.Name, buildStrategy); err != nil {
 				return err
 			}
-			if err := s.validateBuildParams(buildStrategy.Spec.Parameters); err != nil {
-				return err
-			}
+			s.validateStrategySpec(buildStrategy.Spec)
 		}
 	}
 	return nil
@@ -84,22 +78,97 @@ func (s Strategy) validateClusterBuildStrategy(ctx context.Context, strategyName
 	return nil
 }
 
-func (s Strategy) validateBuildParams(parameters []build.Parameter) error {
+// validateStrategySpec validates the param values, environment variables, step resources,
+// scheduling and caches of the Build against the strategy, unless the strategy does not exist. All failures are
+// reported, with the reason of the first one and the messages of all of them.
+func (s Strategy) validateStrategySpec(spec build.BuildStrategySpec) {
+	if s.Build.Status.Reason != "" && s.Build.Status.Reason != build.SucceedStatus {
+		return
+	}
 
-	if len(s.Build.Spec.ParamValues) == 0 {
-		return nil
+	var reasons []build.BuildReason
+	var messages []string
+	for _, validation := range []func(build.BuildStrategySpec) (build.BuildReason, string){
+		// Check that the Build param is defined in the strategies parameters
+		s.validateParamsInStrategies,
+		// Check that the Build param is not a restricted shipwright one
+		s.validateParamsNamesDefinition,
+		// Check that the Build param values match the type, allowed values and pattern of the strategy parameters,
+		// and that the strategy steps can use the values that reference a Secret
+		s.validateParamValues,
+		s.validateEnvVars,
+		s.validateStepResources,
+		s.validateScheduling,
+		s.validateCaches,
+	} {
+		if reason, message := validation(spec); reason != "" {
+			reasons = append(reasons, reason)
+			messages = append(messages, message)
+		}
 	}
 
-	// Check that the Build param is defined in the strategies parameters
-	s.validateParamsInStrategies(s.Build.Spec.ParamValues, parameters)
+	if len(reasons) > 0 {
+		s.Build.Status.Reason = reasons[0]
+		s.Build.Status.Message = strings.Join(messages, "; ")
+	}
+}
 
-	// Check that the Build param is not a restricted shipwright one
-	s.validateParamsNamesDefinition()
+func (s Strategy) validateCaches(spec build.BuildStrategySpec) (build.BuildReason, string) {
+	undefinedCaches := []string{}
+	for _, buildCache := range s.Build.Spec.Caches {
+		defined := false
+		for _, cache := range spec.Caches {
+			if cache.Name == buildCache.Name {
+				defined = true
+				break
+			}
+		}
+		if !defined {
+			undefinedCaches = append(undefinedCaches, buildCache.Name)
+		}
+	}
 
-	return nil
+	if len(undefinedCaches) > 0 {
+		return build.UndefinedCache, fmt.Sprintf("caches not declared by the strategy: %s", strings.Join(undefinedCaches, ","))
+	}
+	return "", ""
+}
+
+func (s Strategy) validateStepResources(spec build.BuildStrategySpec) (build.BuildReason, string) {
+	return resources.ValidateStepResources(spec.BuildSteps, s.Build.Spec.Resources, spec.ResourceBounds)
+}
+
+func (s Strategy) validateScheduling(spec build.BuildStrategySpec) (build.BuildReason, string) {
+	return resources.ValidateScheduling(s.Build.Spec.Scheduling, spec.SchedulingBounds)
+}
+
+func (s Strategy) validateEnvVars(spec build.BuildStrategySpec) (build.BuildReason, string) {
+	if inUse := resources.ProtectedEnvVarsInUse(s.Build.Spec.Env, spec.ProtectedEnvVars); len(inUse) > 0 {
+		return build.ProtectedEnvVarsInUse, fmt.Sprintf("environment variables protected by the strategy in use: %s", strings.Join(inUse, ","))
+	}
+	return "", ""
+}
+
+func (s Strategy) validateParamValues(spec build.BuildStrategySpec) (build.BuildReason, string) {
+	for _, bp := range s.Build.Spec.ParamValues {
+		for _, sp := range spec.Parameters {
+			if bp.Name != sp.Name {
+				continue
+			}
+
+			if reason, message := resources.ValidateParamValue(sp, bp); reason != "" {
+				return reason, message
+			}
+
+			if reason, message := resources.ValidateSecretParamValueUse(spec.BuildSteps, bp); reason != "" {
+				return reason, message
+			}
+		}
+	}
+	return "", ""
 }
 
-func (s Strategy) validateParamsNamesDefinition() {
+func (s Strategy) validateParamsNamesDefinition(_ build.BuildStrategySpec) (build.BuildReason, string) {
 
 	undesiredParams := []string{}
 
@@ -110,17 +179,17 @@ func (s Strategy) validateParamsNamesDefinition() {
 	}
 
 	if len(undesiredParams) > 0 {
-		s.Build.Status.Reason = build.RestrictedParametersInUse
-		s.Build.Status.Message = fmt.Sprintf("restricted parameters in use: %s", strings.Join(undesiredParams, ","))
+		return build.RestrictedParametersInUse, fmt.Sprintf("restricted parameters in use: %s", strings.Join(undesiredParams, ","))
 	}
+	return "", ""
 }
 
-func (s Strategy) validateParamsInStrategies(params []build.ParamValue, parameters []build.Parameter) {
+func (s Strategy) validateParamsInStrategies(spec build.BuildStrategySpec) (build.BuildReason, string) {
 
 	undefinedParams := []string{}
 	definedParameter := false
-	for _, bp := range params {
-		for _, sp := range parameters {
+	for _, bp := range s.Build.Spec.ParamValues {
+		for _, sp := range spec.Parameters {
 			if bp.Name == sp.Name {
 				definedParameter = true
 			}
@@ -132,7 +201,7 @@ func (s Strategy) validateParamsInStrategies(params []build.ParamValue, paramete
 	}
 
 	if len(undefinedParams) > 0 {
-		s.Build.Status.Reason = build.UndefinedParameter
-		s.Build.Status.Message = fmt.Sprintf("parameter not defined in the strategies: %s", strings.Join(undefinedParams, ","))
+		return build.UndefinedParameter, fmt.Sprintf("parameter not defined in the strategies: %s", strings.Join(undefinedParams, ","))
 	}
+	return "", ""
 }
@@ -24,6 +24,10 @@ const (
 	Runtime = "runtime"
 	// Sources for validating `spec.sources` entries
 	Sources = "sources"
+	// ParamValues for validating the ConfigMaps and Secrets referenced by `spec.paramValues`
+	ParamValues = "paramvalues"
+	// Output for validating the additional tags of `spec.output`
+	Output = "output"
 	// OwnerReferences for validating the ownerreferences between a Build
 	// and BuildRun objects
 	OwnerReferences = "ownerreferences"
@@ -58,6 +62,10 @@ func NewValidation(
 		return &OwnerRef{Build: build, Client: client, Scheme: scheme}, nil
 	case Sources:
 		return &SourcesRef{Build: build}, nil
+	case ParamValues:
+		return &ParamValuesRef{Build: build, Client: client}, nil
+	case Output:
+		return &OutputRef{Build: build}, nil
 	default:
 		return nil, fmt.Errorf("unknown validation type")
 	}
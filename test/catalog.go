@@ -611,6 +611,9 @@ func (c *Catalog) DefaultBuild(buildName string, strategyName string, strategyKi
 			Name: buildName,
 		},
 		Spec: build.BuildSpec{
+			Source: build.Source{
+				URL: "https://github.com/shipwright-io/sample-go",
+			},
 			Strategy: &build.Strategy{
 				Name: strategyName,
 				Kind: &strategyKind,